@@ -4,22 +4,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 )
 
 // see: libraries/chain/contracts/abi_serializer.cpp:53...
 // see: libraries/chain/include/eosio/chain/contracts/types.hpp:100
 type ABI struct {
 	fitNodeos        bool
-	Version          string            `json:"version"`
-	Types            []ABIType         `json:"types,omitempty"`
-	Structs          []StructDef       `json:"structs,omitempty"`
-	Actions          []ActionDef       `json:"actions,omitempty"`
-	Tables           []TableDef        `json:"tables,omitempty"`
-	RicardianClauses []ClausePair      `json:"ricardian_clauses,omitempty"`
-	ErrorMessages    []ABIErrorMessage `json:"error_messages,omitempty"`
-	Extensions       []*Extension      `json:"abi_extensions,omitempty"`
-	Variants         []VariantDef      `json:"variants,omitempty" eos:"binary_extension"`
-	ActionResults    []ActionResultDef `json:"action_results,omitempty" eos:"binary_extension"`
+	Version          string                   `json:"version"`
+	Types            []ABIType                `json:"types,omitempty"`
+	Structs          []StructDef              `json:"structs,omitempty"`
+	Actions          []ActionDef              `json:"actions,omitempty"`
+	Tables           []TableDef               `json:"tables,omitempty"`
+	RicardianClauses []ClausePair             `json:"ricardian_clauses,omitempty"`
+	ErrorMessages    []ABIErrorMessage        `json:"error_messages,omitempty"`
+	Extensions       []*Extension             `json:"abi_extensions,omitempty"`
+	Variants         []VariantDef             `json:"variants,omitempty" eos:"binary_extension"`
+	ActionResults    []ActionResultDef        `json:"action_results,omitempty" eos:"binary_extension"`
+	KVTables         map[TableName]KVTableDef `json:"kv_tables,omitempty" eos:"binary_extension"`
+
+	// binaryExtensions is the number of binary extension fields that
+	// were present when the ABI was unpacked, to pack it back to the
+	// same bytes, and so with the same hash.
+	binaryExtensions int
+	unpacked         bool
 }
 
 func NewABI(r io.Reader) (*ABI, error) {
@@ -33,6 +41,196 @@ func NewABI(r io.Reader) (*ABI, error) {
 	return abi, nil
 }
 
+// MarshalBinary packs the ABI the way `setabi` expects it. An unpacked
+// ABI is packed back to the exact same bytes. Otherwise, `variants` and
+// `action_results` are always packed, and `kv_tables` only when the
+// ABI has some.
+func (a ABI) MarshalBinary(encoder *Encoder) error {
+	fields := []interface{}{a.Version, a.Types, a.Structs, a.Actions, a.Tables, a.RicardianClauses, a.ErrorMessages, a.Extensions}
+	for _, field := range fields {
+		if err := encoder.Encode(field); err != nil {
+			return err
+		}
+	}
+
+	extensions := 2
+	if a.unpacked {
+		extensions = a.binaryExtensions
+	}
+	switch {
+	case len(a.KVTables) > 0:
+		extensions = 3
+	case len(a.ActionResults) > 0 && extensions < 2:
+		extensions = 2
+	case len(a.Variants) > 0 && extensions < 1:
+		extensions = 1
+	}
+
+	if extensions >= 1 {
+		if err := encoder.Encode(a.Variants); err != nil {
+			return err
+		}
+	}
+	if extensions >= 2 {
+		if err := encoder.Encode(a.ActionResults); err != nil {
+			return err
+		}
+	}
+	if extensions >= 3 {
+		if err := a.writeKVTables(encoder); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a ABI) writeKVTables(encoder *Encoder) error {
+	names := make([]Name, 0, len(a.KVTables))
+	for name := range a.KVTables {
+		names = append(names, Name(name))
+	}
+	sortNames(names)
+
+	if err := encoder.writeUVarInt(len(names)); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		table := a.KVTables[TableName(name)]
+		if err := encoder.writeName(name); err != nil {
+			return err
+		}
+		if err := encoder.writeString(table.Type); err != nil {
+			return err
+		}
+		if err := encoder.writeName(table.PrimaryIndex.Name); err != nil {
+			return err
+		}
+		if err := encoder.writeString(table.PrimaryIndex.Type); err != nil {
+			return err
+		}
+
+		indexNames := make([]Name, 0, len(table.SecondaryIndices))
+		for indexName := range table.SecondaryIndices {
+			indexNames = append(indexNames, indexName)
+		}
+		sortNames(indexNames)
+
+		if err := encoder.writeUVarInt(len(indexNames)); err != nil {
+			return err
+		}
+		for _, indexName := range indexNames {
+			if err := encoder.writeName(indexName); err != nil {
+				return err
+			}
+			if err := encoder.writeString(table.SecondaryIndices[indexName].Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalBinary unpacks an ABI, as found in the `setabi` action or
+// returned by `get_raw_abi`.
+func (a *ABI) UnmarshalBinary(decoder *Decoder) error {
+	fields := []interface{}{&a.Version, &a.Types, &a.Structs, &a.Actions, &a.Tables, &a.RicardianClauses, &a.ErrorMessages, &a.Extensions}
+	for _, field := range fields {
+		if err := decoder.Decode(field); err != nil {
+			return err
+		}
+	}
+
+	a.unpacked = true
+	a.binaryExtensions = 0
+
+	if decoder.remaining() == 0 {
+		return nil
+	}
+	if err := decoder.Decode(&a.Variants); err != nil {
+		return fmt.Errorf("variants: %w", err)
+	}
+	a.binaryExtensions++
+
+	if decoder.remaining() == 0 {
+		return nil
+	}
+	if err := decoder.Decode(&a.ActionResults); err != nil {
+		return fmt.Errorf("action results: %w", err)
+	}
+	a.binaryExtensions++
+
+	if decoder.remaining() == 0 {
+		return nil
+	}
+	if err := a.readKVTables(decoder); err != nil {
+		return fmt.Errorf("kv tables: %w", err)
+	}
+	a.binaryExtensions++
+
+	return nil
+}
+
+func (a *ABI) readKVTables(decoder *Decoder) error {
+	count, err := decoder.ReadUvarint32()
+	if err != nil {
+		return err
+	}
+
+	a.KVTables = make(map[TableName]KVTableDef, count)
+	for i := uint32(0); i < count; i++ {
+		name, err := decoder.ReadName()
+		if err != nil {
+			return err
+		}
+
+		var table KVTableDef
+		if table.Type, err = decoder.ReadString(); err != nil {
+			return err
+		}
+		if table.PrimaryIndex.Name, err = decoder.ReadName(); err != nil {
+			return err
+		}
+		if table.PrimaryIndex.Type, err = decoder.ReadString(); err != nil {
+			return err
+		}
+
+		indexCount, err := decoder.ReadUvarint32()
+		if err != nil {
+			return err
+		}
+		if indexCount > 0 {
+			table.SecondaryIndices = make(map[Name]SecondaryIndexDef, indexCount)
+		}
+		for j := uint32(0); j < indexCount; j++ {
+			indexName, err := decoder.ReadName()
+			if err != nil {
+				return err
+			}
+			indexType, err := decoder.ReadString()
+			if err != nil {
+				return err
+			}
+			table.SecondaryIndices[indexName] = SecondaryIndexDef{Type: indexType}
+		}
+
+		a.KVTables[TableName(name)] = table
+	}
+
+	return nil
+}
+
+// sortNames sorts names by their `uint64` value, like `std::map` keys.
+func sortNames(names []Name) {
+	values := make(map[Name]uint64, len(names))
+	for _, name := range names {
+		values[name], _ = StringToName(string(name))
+	}
+	sort.Slice(names, func(i, j int) bool { return values[names[i]] < values[names[j]] })
+}
+
 func (a *ABI) SetFitNodeos(v bool) {
 	a.fitNodeos = v
 }
@@ -64,6 +262,13 @@ func (a *ABI) TableForName(name TableName) *TableDef {
 	return nil
 }
 
+func (a *ABI) KVTableForName(name TableName) *KVTableDef {
+	if table, found := a.KVTables[name]; found {
+		return &table
+	}
+	return nil
+}
+
 func (a *ABI) VariantForName(name string) *VariantDef {
 	for _, s := range a.Variants {
 		if s.Name == name {
@@ -82,6 +287,17 @@ func (a *ABI) ActionResultForName(name ActionName) *ActionResultDef {
 	return nil
 }
 
+// ErrorMessage returns the message declared in `error_messages` for
+// an `eosio_assert_code` error code.
+func (a *ABI) ErrorMessage(code uint64) (string, bool) {
+	for _, m := range a.ErrorMessages {
+		if uint64(m.Code) == code {
+			return m.Message, true
+		}
+	}
+	return "", false
+}
+
 func (a *ABI) TypeNameForNewTypeName(typeName string) (resolvedTypeName string, isAlias bool) {
 	for _, t := range a.Types {
 		if t.NewTypeName == typeName {
@@ -143,3 +359,20 @@ type ActionResultDef struct {
 	Name       ActionName `json:"name"`
 	ResultType string     `json:"result_type"`
 }
+
+// KVTableDef defines a key-value table, introduced by `eosio::abi/1.2`
+// in EOSIO 2.1.
+type KVTableDef struct {
+	Type             string                     `json:"type"`
+	PrimaryIndex     PrimaryKeyIndexDef         `json:"primary_index"`
+	SecondaryIndices map[Name]SecondaryIndexDef `json:"secondary_indices,omitempty"`
+}
+
+type PrimaryKeyIndexDef struct {
+	Name Name   `json:"name"`
+	Type string `json:"type"`
+}
+
+type SecondaryIndexDef struct {
+	Type string `json:"type"`
+}
@@ -1,6 +1,8 @@
 package eos
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"strings"
@@ -121,6 +123,104 @@ func TestABI_ReadPacked_V1_2(t *testing.T) {
 	assert.JSONEq(t, systemABIV1_2, string(actualJSON))
 }
 
+func TestABI_PackedKVTables(t *testing.T) {
+	abiJSON := `{
+		"version": "eosio::abi/1.2",
+		"structs": [{"name": "row", "base": "", "fields": [{"name": "id", "type": "uint64"}, {"name": "name", "type": "string"}]}],
+		"kv_tables": {
+			"kvtable": {"type": "row", "primary_index": {"name": "id", "type": "uint64"}, "secondary_indices": {"byname": {"type": "string"}, "a": {"type": "uint64"}}},
+			"akvtable": {"type": "row", "primary_index": {"name": "id", "type": "uint64"}}
+		}
+	}`
+
+	var abiDef ABI
+	require.NoError(t, json.Unmarshal([]byte(abiJSON), &abiDef))
+
+	packed, err := MarshalBinary(abiDef)
+	require.NoError(t, err)
+
+	name := func(n string) string {
+		value, err := StringToName(n)
+		require.NoError(t, err)
+		data, err := MarshalBinary(value)
+		require.NoError(t, err)
+		return hex.EncodeToString(data)
+	}
+
+	expected := "0e656f73696f3a3a6162692f312e32" + // version
+		"00" + // types
+		"01" + "03726f77" + "00" + "02" + "026964" + "0675696e743634" + "046e616d65" + "06737472696e67" + // structs
+		"00" + "00" + "00" + "00" + "00" + // actions, tables, ricardian_clauses, error_messages, abi_extensions
+		"00" + "00" + // variants, action_results
+		"02" + // kv_tables, sorted by name value
+		name("akvtable") + "03726f77" + name("id") + "0675696e743634" + "00" +
+		name("kvtable") + "03726f77" + name("id") + "0675696e743634" + "02" +
+		name("a") + "0675696e743634" +
+		name("byname") + "06737472696e67"
+	assert.Equal(t, expected, hex.EncodeToString(packed))
+
+	var unpacked ABI
+	require.NoError(t, UnmarshalBinary(packed, &unpacked))
+
+	actualJSON, err := json.Marshal(unpacked)
+	require.NoError(t, err)
+	assert.JSONEq(t, abiJSON, string(actualJSON))
+
+	repacked, err := MarshalBinary(unpacked)
+	require.NoError(t, err)
+	assert.Equal(t, packed, repacked)
+}
+
+func TestABI_PackedExtensionsRoundTrip(t *testing.T) {
+	abiDef := ABI{Version: "eosio::abi/1.0", Types: []ABIType{{NewTypeName: "account_name", Type: "name"}}}
+
+	packed, err := MarshalBinary(abiDef)
+	require.NoError(t, err)
+	assert.Equal(t, "0e656f73696f3a3a6162692f312e30010c6163636f756e745f6e616d65046e616d650000000000000000", hex.EncodeToString(packed))
+
+	// ABIs packed without some binary extensions are packed back as is
+	for _, extensions := range []int{0, 1} {
+		truncated := packed[:len(packed)-2+extensions]
+
+		var unpacked ABI
+		require.NoError(t, UnmarshalBinary(truncated, &unpacked))
+		assert.Equal(t, abiDef.Types, unpacked.Types)
+
+		repacked, err := MarshalBinary(unpacked)
+		require.NoError(t, err)
+		assert.Equal(t, truncated, repacked)
+	}
+
+	// unless the extensions are now used
+	var unpacked ABI
+	require.NoError(t, UnmarshalBinary(packed[:len(packed)-2], &unpacked))
+	unpacked.ActionResults = []ActionResultDef{{Name: "act", ResultType: "uint64"}}
+
+	repacked, err := MarshalBinary(unpacked)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(packed[:len(packed)-1])+"0100000000000032320675696e743634", hex.EncodeToString(repacked))
+}
+
+func TestGetRawABIResp_DecodeABI(t *testing.T) {
+	abiDef := ABI{Version: "eosio::abi/1.1", Types: []ABIType{{NewTypeName: "account_name", Type: "name"}}}
+	packed, err := MarshalBinary(abiDef)
+	require.NoError(t, err)
+	hash := sha256.Sum256(packed)
+
+	resp := &GetRawABIResp{AccountName: "contract", ABIHash: hash[:], ABI: Blob(base64.StdEncoding.EncodeToString(packed))}
+	decoded, err := resp.DecodeABI()
+	require.NoError(t, err)
+	assert.Equal(t, abiDef.Types, decoded.Types)
+
+	resp.ABIHash = make([]byte, 32)
+	_, err = resp.DecodeABI()
+	assert.EqualError(t, err, "abi hash mismatch for account contract, expected 0000000000000000000000000000000000000000000000000000000000000000, got "+hex.EncodeToString(hash[:]))
+
+	resp.ABI = ""
+	_, err = resp.DecodeABI()
+	assert.EqualError(t, err, "no abi returned for account contract")
+}
+
 var testSystemABIRaw = `{
   "version": "__VERSION__",
   "types": [{
@@ -0,0 +1,424 @@
+package eos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ABIChangeKind is the kind of an `ABIChange`.
+type ABIChangeKind string
+
+const (
+	ABITypeAdded              ABIChangeKind = "type_added"
+	ABITypeRemoved            ABIChangeKind = "type_removed"
+	ABITypeChanged            ABIChangeKind = "type_changed"
+	ABIStructAdded            ABIChangeKind = "struct_added"
+	ABIStructRemoved          ABIChangeKind = "struct_removed"
+	ABIStructBaseChanged      ABIChangeKind = "struct_base_changed"
+	ABIFieldAdded             ABIChangeKind = "field_added"
+	ABIFieldRemoved           ABIChangeKind = "field_removed"
+	ABIFieldRenamed           ABIChangeKind = "field_renamed"
+	ABIFieldTypeChanged       ABIChangeKind = "field_type_changed"
+	ABIVariantAdded           ABIChangeKind = "variant_added"
+	ABIVariantRemoved         ABIChangeKind = "variant_removed"
+	ABIVariantTypeAdded       ABIChangeKind = "variant_type_added"
+	ABIVariantTypeRemoved     ABIChangeKind = "variant_type_removed"
+	ABIVariantTypeChanged     ABIChangeKind = "variant_type_changed"
+	ABIActionAdded            ABIChangeKind = "action_added"
+	ABIActionRemoved          ABIChangeKind = "action_removed"
+	ABIActionTypeChanged      ABIChangeKind = "action_type_changed"
+	ABIActionDataChanged      ABIChangeKind = "action_data_changed"
+	ABIActionRicardianChanged ABIChangeKind = "action_ricardian_changed"
+	ABITableAdded             ABIChangeKind = "table_added"
+	ABITableRemoved           ABIChangeKind = "table_removed"
+	ABITableTypeChanged       ABIChangeKind = "table_type_changed"
+	ABITableIndexChanged      ABIChangeKind = "table_index_type_changed"
+	ABITableKeysChanged       ABIChangeKind = "table_keys_changed"
+	ABITableRowChanged        ABIChangeKind = "table_row_changed"
+	ABIActionResultAdded      ABIChangeKind = "action_result_added"
+	ABIActionResultRemoved    ABIChangeKind = "action_result_removed"
+	ABIActionResultChanged    ABIChangeKind = "action_result_changed"
+)
+
+// ABIChange is a difference between two versions of an ABI.
+type ABIChange struct {
+	Kind ABIChangeKind `json:"kind"`
+	// Path locates the change in the ABI, like `structs[transfer].fields[to]`.
+	Path string `json:"path"`
+	// Breaking is true when data encoded with the previous ABI, like
+	// existing table rows, or clients using it are not compatible with
+	// the next ABI anymore.
+	Breaking bool   `json:"breaking"`
+	Previous string `json:"previous,omitempty"`
+	Next     string `json:"next,omitempty"`
+}
+
+func (c *ABIChange) String() string {
+	severity := "compatible"
+	if c.Breaking {
+		severity = "breaking"
+	}
+
+	out := fmt.Sprintf("%s: %s (%s)", c.Path, c.Kind, severity)
+	if c.Previous != "" || c.Next != "" {
+		out += fmt.Sprintf(" %q -> %q", c.Previous, c.Next)
+	}
+	return out
+}
+
+// ABIDiff is the list of changes between two versions of an ABI.
+type ABIDiff struct {
+	Breaking bool         `json:"breaking"`
+	Changes  []*ABIChange `json:"changes"`
+}
+
+// BreakingChanges returns the changes that are not compatible.
+func (d *ABIDiff) BreakingChanges() (out []*ABIChange) {
+	for _, change := range d.Changes {
+		if change.Breaking {
+			out = append(out, change)
+		}
+	}
+	return
+}
+
+// DiffABI compares two versions of an ABI, classifying each change of
+// the types, structs, variants, actions and tables as compatible or
+// breaking. Adding fields at the end of a struct as binary extensions
+// (`type$`) or adding types at the end of a variant is compatible,
+// while changing the type of a field or removing an action is
+// breaking. Actions and tables whose data type is affected by a
+// breaking change are reported as changed too.
+func DiffABI(previous, next *ABI) *ABIDiff {
+	d := &abiDiffer{previous: previous, next: next, broken: map[string]bool{}}
+
+	d.diffTypes()
+	d.diffStructs()
+	d.diffVariants()
+	d.diffActions()
+	d.diffTables()
+	d.diffActionResults()
+
+	diff := &ABIDiff{Changes: d.changes}
+	diff.Breaking = len(diff.BreakingChanges()) > 0
+	return diff
+}
+
+type abiDiffer struct {
+	previous *ABI
+	next     *ABI
+	changes  []*ABIChange
+
+	// broken holds the types with breaking changes, to report the
+	// actions and tables using them.
+	broken map[string]bool
+}
+
+func (d *abiDiffer) report(kind ABIChangeKind, path string, breaking bool, previous, next string) {
+	d.changes = append(d.changes, &ABIChange{Kind: kind, Path: path, Breaking: breaking, Previous: previous, Next: next})
+}
+
+func (d *abiDiffer) diffTypes() {
+	for _, previousType := range d.previous.Types {
+		path := fmt.Sprintf("types[%s]", previousType.NewTypeName)
+		nextType, found := d.next.TypeNameForNewTypeName(previousType.NewTypeName)
+		if !found {
+			if d.next.StructForName(previousType.NewTypeName) == nil && d.next.VariantForName(previousType.NewTypeName) == nil {
+				d.report(ABITypeRemoved, path, true, previousType.Type, "")
+				d.broken[previousType.NewTypeName] = true
+			}
+			continue
+		}
+
+		if nextType != previousType.Type {
+			breaking := !d.sameType(previousType.Type, nextType)
+			d.report(ABITypeChanged, path, breaking, previousType.Type, nextType)
+			if breaking {
+				d.broken[previousType.NewTypeName] = true
+			}
+		}
+	}
+
+	for _, nextType := range d.next.Types {
+		if _, found := d.previous.TypeNameForNewTypeName(nextType.NewTypeName); !found {
+			d.report(ABITypeAdded, fmt.Sprintf("types[%s]", nextType.NewTypeName), false, "", nextType.Type)
+		}
+	}
+}
+
+func (d *abiDiffer) diffStructs() {
+	for _, previousStruct := range d.previous.Structs {
+		path := fmt.Sprintf("structs[%s]", previousStruct.Name)
+		nextStruct := d.next.StructForName(previousStruct.Name)
+		if nextStruct == nil {
+			d.report(ABIStructRemoved, path, true, "", "")
+			d.broken[previousStruct.Name] = true
+			continue
+		}
+
+		if d.diffStruct(path, previousStruct, *nextStruct) {
+			d.broken[previousStruct.Name] = true
+		}
+	}
+
+	for _, nextStruct := range d.next.Structs {
+		if d.previous.StructForName(nextStruct.Name) == nil {
+			d.report(ABIStructAdded, fmt.Sprintf("structs[%s]", nextStruct.Name), false, "", "")
+		}
+	}
+}
+
+// diffStruct reports the changes of a struct, returning whether any of
+// them is breaking.
+func (d *abiDiffer) diffStruct(path string, previous, next StructDef) (breaking bool) {
+	if previous.Base != next.Base {
+		baseBreaking := !d.sameType(previous.Base, next.Base)
+		d.report(ABIStructBaseChanged, path, baseBreaking, previous.Base, next.Base)
+		breaking = breaking || baseBreaking
+	}
+
+	for i, previousField := range previous.Fields {
+		fieldPath := fmt.Sprintf("%s.fields[%s]", path, previousField.Name)
+		if i >= len(next.Fields) {
+			d.report(ABIFieldRemoved, fieldPath, true, previousField.Type, "")
+			breaking = true
+			continue
+		}
+
+		nextField := next.Fields[i]
+		if nextField.Name != previousField.Name {
+			d.report(ABIFieldRenamed, fieldPath, true, previousField.Name, nextField.Name)
+			breaking = true
+		}
+
+		if nextField.Type != previousField.Type {
+			previousType := strings.TrimSuffix(previousField.Type, "$")
+			nextType := strings.TrimSuffix(nextField.Type, "$")
+
+			// a field can become a binary extension, as previous data
+			// always has it, but not the other way around
+			fieldBreaking := !d.sameType(previousType, nextType) ||
+				strings.HasSuffix(previousField.Type, "$") && !strings.HasSuffix(nextField.Type, "$")
+			d.report(ABIFieldTypeChanged, fieldPath, fieldBreaking, previousField.Type, nextField.Type)
+			breaking = breaking || fieldBreaking
+		}
+	}
+
+	for _, nextField := range next.Fields[minInt(len(previous.Fields), len(next.Fields)):] {
+		fieldBreaking := !strings.HasSuffix(nextField.Type, "$")
+		d.report(ABIFieldAdded, fmt.Sprintf("%s.fields[%s]", path, nextField.Name), fieldBreaking, "", nextField.Type)
+		breaking = breaking || fieldBreaking
+	}
+
+	return breaking
+}
+
+func (d *abiDiffer) diffVariants() {
+	for _, previousVariant := range d.previous.Variants {
+		path := fmt.Sprintf("variants[%s]", previousVariant.Name)
+		nextVariant := d.next.VariantForName(previousVariant.Name)
+		if nextVariant == nil {
+			d.report(ABIVariantRemoved, path, true, "", "")
+			d.broken[previousVariant.Name] = true
+			continue
+		}
+
+		for i, previousType := range previousVariant.Types {
+			typePath := fmt.Sprintf("%s.types[%d]", path, i)
+			if i >= len(nextVariant.Types) {
+				d.report(ABIVariantTypeRemoved, typePath, true, previousType, "")
+				d.broken[previousVariant.Name] = true
+				continue
+			}
+
+			if nextType := nextVariant.Types[i]; nextType != previousType {
+				breaking := !d.sameType(previousType, nextType)
+				d.report(ABIVariantTypeChanged, typePath, breaking, previousType, nextType)
+				if breaking {
+					d.broken[previousVariant.Name] = true
+				}
+			}
+		}
+
+		for i := len(previousVariant.Types); i < len(nextVariant.Types); i++ {
+			d.report(ABIVariantTypeAdded, fmt.Sprintf("%s.types[%d]", path, i), false, "", nextVariant.Types[i])
+		}
+	}
+
+	for _, nextVariant := range d.next.Variants {
+		if d.previous.VariantForName(nextVariant.Name) == nil {
+			d.report(ABIVariantAdded, fmt.Sprintf("variants[%s]", nextVariant.Name), false, "", "")
+		}
+	}
+}
+
+func (d *abiDiffer) diffActions() {
+	for _, previousAction := range d.previous.Actions {
+		path := fmt.Sprintf("actions[%s]", previousAction.Name)
+		nextAction := d.next.ActionForName(previousAction.Name)
+		if nextAction == nil {
+			d.report(ABIActionRemoved, path, true, previousAction.Type, "")
+			continue
+		}
+
+		switch {
+		case nextAction.Type != previousAction.Type:
+			d.report(ABIActionTypeChanged, path, !d.sameType(previousAction.Type, nextAction.Type) || d.uses(nextAction.Type), previousAction.Type, nextAction.Type)
+		case d.uses(nextAction.Type):
+			d.report(ABIActionDataChanged, path, true, "", "")
+		}
+
+		if nextAction.RicardianContract != previousAction.RicardianContract {
+			d.report(ABIActionRicardianChanged, path, false, "", "")
+		}
+	}
+
+	for _, nextAction := range d.next.Actions {
+		if d.previous.ActionForName(nextAction.Name) == nil {
+			d.report(ABIActionAdded, fmt.Sprintf("actions[%s]", nextAction.Name), false, "", nextAction.Type)
+		}
+	}
+}
+
+func (d *abiDiffer) diffTables() {
+	for _, previousTable := range d.previous.Tables {
+		path := fmt.Sprintf("tables[%s]", previousTable.Name)
+		nextTable := d.next.TableForName(previousTable.Name)
+		if nextTable == nil {
+			d.report(ABITableRemoved, path, true, previousTable.Type, "")
+			continue
+		}
+
+		if nextTable.IndexType != previousTable.IndexType {
+			d.report(ABITableIndexChanged, path, true, previousTable.IndexType, nextTable.IndexType)
+		}
+
+		previousKeys := tableKeys(previousTable)
+		if nextKeys := tableKeys(*nextTable); nextKeys != previousKeys {
+			d.report(ABITableKeysChanged, path, true, previousKeys, nextKeys)
+		}
+
+		switch {
+		case nextTable.Type != previousTable.Type:
+			d.report(ABITableTypeChanged, path, !d.sameType(previousTable.Type, nextTable.Type) || d.uses(nextTable.Type), previousTable.Type, nextTable.Type)
+		case d.uses(nextTable.Type):
+			d.report(ABITableRowChanged, path, true, "", "")
+		}
+	}
+
+	for _, nextTable := range d.next.Tables {
+		if d.previous.TableForName(nextTable.Name) == nil {
+			d.report(ABITableAdded, fmt.Sprintf("tables[%s]", nextTable.Name), false, "", nextTable.Type)
+		}
+	}
+}
+
+func (d *abiDiffer) diffActionResults() {
+	for _, previousResult := range d.previous.ActionResults {
+		path := fmt.Sprintf("action_results[%s]", previousResult.Name)
+		nextResult := d.next.ActionResultForName(previousResult.Name)
+		if nextResult == nil {
+			d.report(ABIActionResultRemoved, path, true, previousResult.ResultType, "")
+			continue
+		}
+
+		if nextResult.ResultType != previousResult.ResultType || d.uses(nextResult.ResultType) {
+			breaking := !d.sameType(previousResult.ResultType, nextResult.ResultType) || d.uses(nextResult.ResultType)
+			d.report(ABIActionResultChanged, path, breaking, previousResult.ResultType, nextResult.ResultType)
+		}
+	}
+
+	for _, nextResult := range d.next.ActionResults {
+		if d.previous.ActionResultForName(nextResult.Name) == nil {
+			d.report(ABIActionResultAdded, fmt.Sprintf("action_results[%s]", nextResult.Name), false, "", nextResult.ResultType)
+		}
+	}
+}
+
+// sameType returns whether a type of the previous ABI and a type of the
+// next ABI are the same once aliases are resolved. Structs and
+// variants are compared by name, their own changes being reported
+// separately.
+func (d *abiDiffer) sameType(previous, next string) bool {
+	return resolveABIType(d.previous, previous) == resolveABIType(d.next, next)
+}
+
+// uses returns whether a type of the next ABI depends on a type with
+// breaking changes.
+func (d *abiDiffer) uses(typeName string) bool {
+	return d.usesBroken(typeName, map[string]bool{})
+}
+
+func (d *abiDiffer) usesBroken(typeName string, seen map[string]bool) bool {
+	name := abiFundamentalType(strings.TrimSuffix(typeName, "$"))
+	if seen[name] {
+		return false
+	}
+	seen[name] = true
+
+	if d.broken[name] {
+		return true
+	}
+
+	if alias, found := d.next.TypeNameForNewTypeName(name); found {
+		return d.usesBroken(alias, seen)
+	}
+
+	if structDef := d.next.StructForName(name); structDef != nil {
+		if structDef.Base != "" && d.usesBroken(structDef.Base, seen) {
+			return true
+		}
+		for _, field := range structDef.Fields {
+			if d.usesBroken(field.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	if variantDef := d.next.VariantForName(name); variantDef != nil {
+		for _, variantType := range variantDef.Types {
+			if d.usesBroken(variantType, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// resolveABIType resolves the aliases of a type, keeping its array and
+// optional modifiers.
+func resolveABIType(abi *ABI, typeName string) string {
+	seen := map[string]bool{}
+	var suffix string
+	for {
+		fundamental := abiFundamentalType(typeName)
+		suffix = typeName[len(fundamental):] + suffix
+
+		alias, found := abi.TypeNameForNewTypeName(fundamental)
+		if !found || seen[fundamental] {
+			return fundamental + suffix
+		}
+		seen[fundamental] = true
+		typeName = alias
+	}
+}
+
+func tableKeys(table TableDef) string {
+	var keys []string
+	for i, name := range table.KeyNames {
+		keyType := ""
+		if i < len(table.KeyTypes) {
+			keyType = table.KeyTypes[i]
+		}
+		keys = append(keys, name+":"+keyType)
+	}
+	return strings.Join(keys, ",")
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
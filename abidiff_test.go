@@ -0,0 +1,115 @@
+package eos
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const abiDiffTestPrevious = `{
+	"version": "eosio::abi/1.1",
+	"types": [{"new_type_name": "account_name", "type": "name"}],
+	"structs": [
+		{"name": "account", "base": "", "fields": [{"name": "balance", "type": "asset"}]},
+		{"name": "transfer", "base": "", "fields": [
+			{"name": "from", "type": "account_name"},
+			{"name": "to", "type": "account_name"},
+			{"name": "quantity", "type": "asset"},
+			{"name": "memo", "type": "string"}
+		]},
+		{"name": "close", "base": "", "fields": [{"name": "owner", "type": "name"}]},
+		{"name": "stats", "base": "", "fields": [{"name": "supply", "type": "asset"}, {"name": "payload", "type": "payload"}]}
+	],
+	"variants": [{"name": "payload", "types": ["string", "uint64"]}],
+	"actions": [
+		{"name": "transfer", "type": "transfer", "ricardian_contract": ""},
+		{"name": "close", "type": "close", "ricardian_contract": ""}
+	],
+	"tables": [
+		{"name": "accounts", "index_type": "i64", "key_names": [], "key_types": [], "type": "account"},
+		{"name": "stat", "index_type": "i64", "key_names": [], "key_types": [], "type": "stats"}
+	]
+}`
+
+func abiDiffTestABI(t *testing.T, content string) *ABI {
+	abi, err := NewABI(strings.NewReader(content))
+	require.NoError(t, err)
+	return abi
+}
+
+func TestDiffABI_Compatible(t *testing.T) {
+	next := strings.NewReplacer(
+		`{"name": "memo", "type": "string"}`, `{"name": "memo", "type": "string"}, {"name": "tag", "type": "uint64$"}`,
+		`["string", "uint64"]`, `["string", "uint64", "bytes"]`,
+		`{"name": "owner", "type": "name"}`, `{"name": "owner", "type": "account_name"}`,
+		`"ricardian_contract": ""}
+	]`, `"ricardian_contract": ""},
+		{"name": "open", "type": "close", "ricardian_contract": ""}
+	]`,
+	).Replace(abiDiffTestPrevious)
+
+	diff := DiffABI(abiDiffTestABI(t, abiDiffTestPrevious), abiDiffTestABI(t, next))
+	assert.False(t, diff.Breaking)
+	assert.Empty(t, diff.BreakingChanges())
+	assert.Equal(t, []*ABIChange{
+		{Kind: ABIFieldAdded, Path: "structs[transfer].fields[tag]", Next: "uint64$"},
+		{Kind: ABIFieldTypeChanged, Path: "structs[close].fields[owner]", Previous: "name", Next: "account_name"},
+		{Kind: ABIVariantTypeAdded, Path: "variants[payload].types[2]", Next: "bytes"},
+		{Kind: ABIActionAdded, Path: "actions[open]", Next: "close"},
+	}, diff.Changes)
+}
+
+func TestDiffABI_Breaking(t *testing.T) {
+	next := strings.NewReplacer(
+		`{"name": "memo", "type": "string"}`, `{"name": "memo", "type": "string"}, {"name": "tag", "type": "uint64"}`,
+		`{"name": "balance", "type": "asset"}`, `{"name": "balance", "type": "extended_asset"}`,
+		`["string", "uint64"]`, `["uint64", "string"]`,
+		`{"name": "close", "type": "close", "ricardian_contract": ""}`, `{"name": "closed", "type": "close", "ricardian_contract": ""}`,
+		`{"name": "stat", "index_type": "i64"`, `{"name": "stat", "index_type": "i128"`,
+	).Replace(abiDiffTestPrevious)
+
+	diff := DiffABI(abiDiffTestABI(t, abiDiffTestPrevious), abiDiffTestABI(t, next))
+	assert.True(t, diff.Breaking)
+
+	var changes []string
+	for _, change := range diff.Changes {
+		changes = append(changes, change.String())
+	}
+	assert.Equal(t, []string{
+		`structs[account].fields[balance]: field_type_changed (breaking) "asset" -> "extended_asset"`,
+		`structs[transfer].fields[tag]: field_added (breaking) "" -> "uint64"`,
+		`variants[payload].types[0]: variant_type_changed (breaking) "string" -> "uint64"`,
+		`variants[payload].types[1]: variant_type_changed (breaking) "uint64" -> "string"`,
+		`actions[transfer]: action_data_changed (breaking)`,
+		`actions[close]: action_removed (breaking) "close" -> ""`,
+		`actions[closed]: action_added (compatible) "" -> "close"`,
+		`tables[accounts]: table_row_changed (breaking)`,
+		`tables[stat]: table_index_type_changed (breaking) "i64" -> "i128"`,
+		`tables[stat]: table_row_changed (breaking)`,
+	}, changes)
+
+	data, err := json.Marshal(diff.BreakingChanges()[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"kind":"field_type_changed","path":"structs[account].fields[balance]","breaking":true,"previous":"asset","next":"extended_asset"}`, string(data))
+}
+
+func TestDiffABI_Aliases(t *testing.T) {
+	previous := abiDiffTestABI(t, `{
+		"types": [{"new_type_name": "owners", "type": "account_name[]"}, {"new_type_name": "account_name", "type": "name"}],
+		"structs": [{"name": "s", "base": "", "fields": [{"name": "owners", "type": "owners"}, {"name": "extra", "type": "bool$"}]}]
+	}`)
+	next := abiDiffTestABI(t, `{
+		"types": [{"new_type_name": "owners", "type": "name[]"}],
+		"structs": [{"name": "s", "base": "", "fields": [{"name": "owners", "type": "owners"}, {"name": "extra", "type": "bool"}]}]
+	}`)
+
+	diff := DiffABI(previous, next)
+	assert.Equal(t, []*ABIChange{
+		{Kind: ABITypeChanged, Path: "types[owners]", Previous: "account_name[]", Next: "name[]"},
+		{Kind: ABITypeRemoved, Path: "types[account_name]", Breaking: true, Previous: "name"},
+		{Kind: ABIFieldTypeChanged, Path: "structs[s].fields[extra]", Breaking: true, Previous: "bool$", Next: "bool"},
+	}, diff.Changes)
+}
@@ -0,0 +1,366 @@
+package eos
+
+import (
+	"fmt"
+	"strings"
+)
+
+var abiBuiltInTypes = map[string]bool{
+	"bool":                 true,
+	"int8":                 true,
+	"uint8":                true,
+	"int16":                true,
+	"uint16":               true,
+	"int32":                true,
+	"uint32":               true,
+	"int64":                true,
+	"uint64":               true,
+	"int128":               true,
+	"uint128":              true,
+	"varint32":             true,
+	"varuint32":            true,
+	"float32":              true,
+	"float64":              true,
+	"float128":             true,
+	"time_point":           true,
+	"time_point_sec":       true,
+	"block_timestamp_type": true,
+	"name":                 true,
+	"bytes":                true,
+	"string":               true,
+	"checksum160":          true,
+	"checksum256":          true,
+	"checksum512":          true,
+	"public_key":           true,
+	"signature":            true,
+	"symbol":               true,
+	"symbol_code":          true,
+	"asset":                true,
+	"extended_asset":       true,
+}
+
+// ABIDiagnostic is a problem found in an ABI by `ABI.Validate`.
+type ABIDiagnostic struct {
+	// Path locates the problem in the ABI, like `structs[transfer].fields[to]`.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (d *ABIDiagnostic) String() string {
+	return d.Path + ": " + d.Message
+}
+
+// Validate checks that the ABI is well formed: that all the types it
+// references are defined, that type aliases and struct bases have no
+// cycles, that names are unique and that action, table and index
+// names are valid EOS names. It returns nil when no problem was found.
+func (a *ABI) Validate() []*ABIDiagnostic {
+	v := &abiValidator{
+		abi:      a,
+		types:    map[string]string{},
+		structs:  map[string]*StructDef{},
+		variants: map[string]bool{},
+	}
+
+	v.collectNames()
+	v.validateTypes()
+	v.validateStructs()
+	v.validateVariants()
+	v.validateActions()
+	v.validateTables()
+	v.validateKVTables()
+	v.validateActionResults()
+	v.validateErrorMessages()
+
+	return v.diagnostics
+}
+
+type abiValidator struct {
+	abi         *ABI
+	diagnostics []*ABIDiagnostic
+
+	types    map[string]string
+	structs  map[string]*StructDef
+	variants map[string]bool
+}
+
+func (v *abiValidator) report(path string, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, &ABIDiagnostic{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// checkNewName reports names colliding with built-in types or with
+// types already defined.
+func (v *abiValidator) checkNewName(path, name string) bool {
+	switch {
+	case name == "":
+		v.report(path, "name is empty")
+	case abiBuiltInTypes[name]:
+		v.report(path, "name %q redefines a built-in type", name)
+	case v.types[name] != "" || v.structs[name] != nil || v.variants[name]:
+		v.report(path, "name %q is already defined", name)
+	default:
+		return true
+	}
+	return false
+}
+
+// checkType reports types that are not defined, once stripped of their
+// array and optional modifiers.
+func (v *abiValidator) checkType(path, typeName string) {
+	if typeName == "" {
+		v.report(path, "type is empty")
+		return
+	}
+
+	if strings.Contains(typeName, "$") {
+		v.report(path, "type %q is a binary extension outside of struct fields", typeName)
+		return
+	}
+
+	name := abiFundamentalType(typeName)
+	if !abiBuiltInTypes[name] && v.types[name] == "" && v.structs[name] == nil && !v.variants[name] {
+		v.report(path, "type %q is not defined", typeName)
+	}
+}
+
+func (v *abiValidator) checkName(path, name string) {
+	value, _ := StringToName(name)
+	if name == "" || NameToString(value) != name {
+		v.report(path, "%q is not a valid name", name)
+	}
+}
+
+// collectNames collects the names of all the types first, as ABI
+// definitions can reference types defined further down.
+func (v *abiValidator) collectNames() {
+	for _, typeDef := range v.abi.Types {
+		if v.checkNewName(fmt.Sprintf("types[%s]", typeDef.NewTypeName), typeDef.NewTypeName) {
+			v.types[typeDef.NewTypeName] = typeDef.Type
+		}
+	}
+	for i, structDef := range v.abi.Structs {
+		if v.checkNewName(fmt.Sprintf("structs[%s]", structDef.Name), structDef.Name) {
+			v.structs[structDef.Name] = &v.abi.Structs[i]
+		}
+	}
+	for _, variantDef := range v.abi.Variants {
+		if v.checkNewName(fmt.Sprintf("variants[%s]", variantDef.Name), variantDef.Name) {
+			v.variants[variantDef.Name] = true
+		}
+	}
+}
+
+func (v *abiValidator) validateTypes() {
+	for _, typeDef := range v.abi.Types {
+		path := fmt.Sprintf("types[%s]", typeDef.NewTypeName)
+		if v.types[typeDef.NewTypeName] != typeDef.Type {
+			continue
+		}
+
+		v.checkType(path, typeDef.Type)
+
+		chain := []string{typeDef.NewTypeName}
+		seen := map[string]bool{typeDef.NewTypeName: true}
+		for next := abiFundamentalType(typeDef.Type); v.types[next] != ""; next = abiFundamentalType(v.types[next]) {
+			chain = append(chain, next)
+			if seen[next] {
+				v.report(path, "alias cycle %s", strings.Join(chain, " -> "))
+				break
+			}
+			seen[next] = true
+		}
+	}
+}
+
+func (v *abiValidator) validateStructs() {
+	for _, structDef := range v.abi.Structs {
+		path := fmt.Sprintf("structs[%s]", structDef.Name)
+		if v.structs[structDef.Name] == nil {
+			continue
+		}
+
+		if structDef.Base != "" {
+			v.validateBase(path, structDef)
+		}
+
+		fields := map[string]bool{}
+		seenBinaryExtension := false
+		for _, field := range structDef.Fields {
+			fieldPath := fmt.Sprintf("%s.fields[%s]", path, field.Name)
+
+			if field.Name == "" {
+				v.report(fieldPath, "name is empty")
+			} else if fields[field.Name] {
+				v.report(fieldPath, "duplicate field %q", field.Name)
+			}
+			fields[field.Name] = true
+
+			fieldType := field.Type
+			if strings.HasSuffix(fieldType, "$") {
+				fieldType = strings.TrimSuffix(fieldType, "$")
+				seenBinaryExtension = true
+			} else if seenBinaryExtension {
+				v.report(fieldPath, "field follows a binary extension field but is not one")
+			}
+
+			v.checkType(fieldPath, fieldType)
+		}
+	}
+}
+
+func (v *abiValidator) validateBase(path string, structDef StructDef) {
+	base := v.resolveAlias(structDef.Base)
+	if v.structs[base] == nil {
+		if base == structDef.Base {
+			v.report(path, "base %q is not a defined struct", structDef.Base)
+		} else {
+			v.report(path, "base %q (%q) is not a defined struct", structDef.Base, base)
+		}
+		return
+	}
+
+	chain := []string{structDef.Name}
+	seen := map[string]bool{structDef.Name: true}
+	for current := v.structs[base]; current != nil; {
+		chain = append(chain, current.Name)
+		if seen[current.Name] {
+			v.report(path, "base cycle %s", strings.Join(chain, " -> "))
+			return
+		}
+		seen[current.Name] = true
+
+		if current.Base == "" {
+			return
+		}
+		current = v.structs[v.resolveAlias(current.Base)]
+	}
+}
+
+// resolveAlias follows type aliases, stopping on cycles (reported by
+// `validateTypes`).
+func (v *abiValidator) resolveAlias(name string) string {
+	seen := map[string]bool{}
+	for v.types[name] != "" && !seen[name] {
+		seen[name] = true
+		name = v.types[name]
+	}
+	return name
+}
+
+func (v *abiValidator) validateVariants() {
+	for _, variantDef := range v.abi.Variants {
+		path := fmt.Sprintf("variants[%s]", variantDef.Name)
+		if len(variantDef.Types) == 0 {
+			v.report(path, "variant has no types")
+		}
+
+		for i, typeName := range variantDef.Types {
+			v.checkType(fmt.Sprintf("%s.types[%d]", path, i), typeName)
+		}
+	}
+}
+
+func (v *abiValidator) validateActions() {
+	actions := map[ActionName]bool{}
+	for _, actionDef := range v.abi.Actions {
+		path := fmt.Sprintf("actions[%s]", actionDef.Name)
+		v.checkName(path, string(actionDef.Name))
+		if actions[actionDef.Name] {
+			v.report(path, "duplicate action %q", actionDef.Name)
+		}
+		actions[actionDef.Name] = true
+
+		v.checkType(path, actionDef.Type)
+	}
+}
+
+func (v *abiValidator) validateTables() {
+	tables := map[TableName]bool{}
+	for _, tableDef := range v.abi.Tables {
+		path := fmt.Sprintf("tables[%s]", tableDef.Name)
+		v.checkName(path, string(tableDef.Name))
+		if tables[tableDef.Name] {
+			v.report(path, "duplicate table %q", tableDef.Name)
+		}
+		tables[tableDef.Name] = true
+
+		v.checkType(path, tableDef.Type)
+
+		if len(tableDef.KeyNames) != len(tableDef.KeyTypes) {
+			v.report(path, "%d key names for %d key types", len(tableDef.KeyNames), len(tableDef.KeyTypes))
+		}
+	}
+}
+
+func (v *abiValidator) validateKVTables() {
+	names := make([]Name, 0, len(v.abi.KVTables))
+	for name := range v.abi.KVTables {
+		names = append(names, Name(name))
+	}
+	sortNames(names)
+
+	for _, name := range names {
+		table := v.abi.KVTables[TableName(name)]
+		path := fmt.Sprintf("kv_tables[%s]", name)
+		v.checkName(path, string(name))
+		v.checkType(path, table.Type)
+
+		v.checkName(path+".primary_index", string(table.PrimaryIndex.Name))
+		v.checkType(path+".primary_index", table.PrimaryIndex.Type)
+
+		indexNames := make([]Name, 0, len(table.SecondaryIndices))
+		for indexName := range table.SecondaryIndices {
+			indexNames = append(indexNames, indexName)
+		}
+		sortNames(indexNames)
+
+		for _, indexName := range indexNames {
+			indexPath := fmt.Sprintf("%s.secondary_indices[%s]", path, indexName)
+			v.checkName(indexPath, string(indexName))
+			v.checkType(indexPath, table.SecondaryIndices[indexName].Type)
+		}
+	}
+}
+
+func (v *abiValidator) validateActionResults() {
+	results := map[ActionName]bool{}
+	for _, resultDef := range v.abi.ActionResults {
+		path := fmt.Sprintf("action_results[%s]", resultDef.Name)
+		v.checkName(path, string(resultDef.Name))
+		if results[resultDef.Name] {
+			v.report(path, "duplicate action result %q", resultDef.Name)
+		}
+		results[resultDef.Name] = true
+
+		v.checkType(path, resultDef.ResultType)
+	}
+}
+
+func (v *abiValidator) validateErrorMessages() {
+	codes := map[Uint64]bool{}
+	for _, message := range v.abi.ErrorMessages {
+		if codes[message.Code] {
+			v.report(fmt.Sprintf("error_messages[%d]", message.Code), "duplicate error code %d", message.Code)
+		}
+		codes[message.Code] = true
+	}
+}
+
+// abiFundamentalType strips the array (`[]`, `[N]`) and optional (`?`)
+// modifiers of an ABI type.
+func abiFundamentalType(typeName string) string {
+	for {
+		switch {
+		case strings.HasSuffix(typeName, "?"):
+			typeName = strings.TrimSuffix(typeName, "?")
+		case strings.HasSuffix(typeName, "]"):
+			index := strings.LastIndex(typeName, "[")
+			if index < 0 {
+				return typeName
+			}
+			typeName = typeName[:index]
+		default:
+			return typeName
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package eos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestABI_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		abi      string
+		expected []string
+	}{
+		{
+			name: "valid",
+			abi: `{
+				"types": [{"new_type_name": "account_name", "type": "name"}],
+				"structs": [
+					{"name": "base", "fields": [{"name": "from", "type": "account_name"}]},
+					{"name": "transfer", "base": "base", "fields": [
+						{"name": "to", "type": "account_name[]"},
+						{"name": "memo", "type": "string?"},
+						{"name": "payload", "type": "payload"},
+						{"name": "extra", "type": "uint8[4]$"}
+					]}
+				],
+				"variants": [{"name": "payload", "types": ["base", "bytes"]}],
+				"actions": [{"name": "transfer", "type": "transfer"}],
+				"tables": [{"name": "accounts", "index_type": "i64", "type": "base"}],
+				"action_results": [{"name": "transfer", "result_type": "uint64"}]
+			}`,
+		},
+		{
+			name: "undefined types",
+			abi: `{
+				"types": [{"new_type_name": "alias", "type": "missing"}],
+				"structs": [{"name": "transfer", "base": "nobase", "fields": [{"name": "to", "type": "account[]"}]}],
+				"variants": [{"name": "payload", "types": ["bytes", "missing"]}],
+				"actions": [{"name": "transfer", "type": "transfers"}],
+				"tables": [{"name": "accounts", "type": "account"}],
+				"action_results": [{"name": "transfer", "result_type": "result"}]
+			}`,
+			expected: []string{
+				`types[alias]: type "missing" is not defined`,
+				`structs[transfer]: base "nobase" is not a defined struct`,
+				`structs[transfer].fields[to]: type "account[]" is not defined`,
+				`variants[payload].types[1]: type "missing" is not defined`,
+				`actions[transfer]: type "transfers" is not defined`,
+				`tables[accounts]: type "account" is not defined`,
+				`action_results[transfer]: type "result" is not defined`,
+			},
+		},
+		{
+			name: "cycles",
+			abi: `{
+				"types": [
+					{"new_type_name": "a", "type": "b"},
+					{"new_type_name": "b", "type": "a[]"},
+					{"new_type_name": "base_alias", "type": "second"}
+				],
+				"structs": [
+					{"name": "first", "base": "base_alias"},
+					{"name": "second", "base": "first"}
+				]
+			}`,
+			expected: []string{
+				`types[a]: alias cycle a -> b -> a`,
+				`types[b]: alias cycle b -> a -> b`,
+				`structs[first]: base cycle first -> second -> first`,
+				`structs[second]: base cycle second -> first -> second`,
+			},
+		},
+		{
+			name: "duplicates",
+			abi: `{
+				"types": [{"new_type_name": "uint64", "type": "name"}],
+				"structs": [
+					{"name": "transfer", "fields": [{"name": "to", "type": "name"}, {"name": "to", "type": "name"}]},
+					{"name": "transfer"}
+				],
+				"variants": [{"name": "transfer", "types": ["name"]}],
+				"actions": [{"name": "transfer", "type": "transfer"}, {"name": "transfer", "type": "transfer"}],
+				"tables": [{"name": "accounts", "type": "transfer"}, {"name": "accounts", "type": "transfer"}],
+				"error_messages": [{"error_code": 1, "error_msg": "a"}, {"error_code": 1, "error_msg": "b"}]
+			}`,
+			expected: []string{
+				`types[uint64]: name "uint64" redefines a built-in type`,
+				`structs[transfer]: name "transfer" is already defined`,
+				`variants[transfer]: name "transfer" is already defined`,
+				`structs[transfer].fields[to]: duplicate field "to"`,
+				`actions[transfer]: duplicate action "transfer"`,
+				`tables[accounts]: duplicate table "accounts"`,
+				`error_messages[1]: duplicate error code 1`,
+			},
+		},
+		{
+			name: "names and extensions",
+			abi: `{
+				"structs": [{"name": "act", "fields": [
+					{"name": "a", "type": "name$"},
+					{"name": "b", "type": "name"}
+				]}],
+				"variants": [{"name": "empty"}],
+				"actions": [
+					{"name": "Transfer", "type": "act"},
+					{"name": "toolongactionname", "type": "act"},
+					{"name": "trailing.", "type": "act"}
+				],
+				"tables": [{"name": "rows", "type": "act$", "key_names": ["id"]}]
+			}`,
+			expected: []string{
+				`structs[act].fields[b]: field follows a binary extension field but is not one`,
+				`variants[empty]: variant has no types`,
+				`actions[Transfer]: "Transfer" is not a valid name`,
+				`actions[toolongactionname]: "toolongactionname" is not a valid name`,
+				`actions[trailing.]: "trailing." is not a valid name`,
+				`tables[rows]: type "act$" is a binary extension outside of struct fields`,
+				`tables[rows]: 1 key names for 0 key types`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			abi, err := NewABI(strings.NewReader(test.abi))
+			require.NoError(t, err)
+
+			var diagnostics []string
+			for _, diagnostic := range abi.Validate() {
+				diagnostics = append(diagnostics, diagnostic.String())
+			}
+			assert.Equal(t, test.expected, diagnostics)
+		})
+	}
+}
@@ -129,6 +129,10 @@ func (data *ActionData) EncodeActionData() ([]byte, error) {
 		return data.HexData, nil
 	}
 
+	if _, isJSON := data.Data.(json.RawMessage); isJSON {
+		return data.HexData, nil
+	}
+
 	buf := new(bytes.Buffer)
 	encoder := NewEncoder(buf)
 
@@ -145,15 +149,7 @@ func (a *Action) MapToRegisteredAction() error {
 		return nil
 	}
 
-	actionMap := RegisteredActions[a.Account]
-
-	var decodeInto reflect.Type
-	if actionMap != nil {
-		objType := actionMap[a.Name]
-		if objType != nil {
-			decodeInto = objType
-		}
-	}
+	decodeInto := DefaultRegistry.ActionType(a.Account, a.Name)
 	if decodeInto == nil {
 		return nil
 	}
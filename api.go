@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/eoscanada/eos-go/ecc"
+	"go.uber.org/zap"
 )
 
 type API struct {
@@ -30,6 +31,12 @@ type API struct {
 	DefaultMaxCPUUsageMS    uint8
 	DefaultMaxNetUsageWords uint32 // in 8-bytes words
 
+	// RetryPolicy, when set, retries transient failures of all calls.
+	RetryPolicy *RetryPolicy
+	// Endpoints, when set, is used instead of `BaseURL` to fail over
+	// across multiple `nodeos` endpoints.
+	Endpoints *EndpointPool
+
 	lastGetInfo      *InfoResp
 	lastGetInfoStamp time.Time
 	lastGetInfoLock  sync.Mutex
@@ -153,6 +160,19 @@ func (api *API) SetSigner(s Signer) {
 	api.Signer = s
 }
 
+// SetRetryPolicy configures how transient failures are retried, see
+// `RetryPolicy`.
+func (api *API) SetRetryPolicy(policy *RetryPolicy) {
+	api.RetryPolicy = policy
+}
+
+// SetEndpoints configures multiple base URLs to fail over across,
+// overriding `BaseURL`. Combine with `SetRetryPolicy` so that a failed
+// call is retried on another endpoint.
+func (api *API) SetEndpoints(pool *EndpointPool) {
+	api.Endpoints = pool
+}
+
 // ProducerPause will pause block production on a nodeos with
 // `producer_api` plugin loaded.
 func (api *API) ProducerPause(ctx context.Context) error {
@@ -614,6 +634,14 @@ func (api *API) GetTableRows(ctx context.Context, params GetTableRowsRequest) (o
 	return
 }
 
+// GetKVTableRows returns the rows of a key-value table, see
+// `GetKVTableRowsRequest.EncodeBounds` to set the bounds from the
+// contract's ABI.
+func (api *API) GetKVTableRows(ctx context.Context, params GetKVTableRowsRequest) (out *GetKVTableRowsResp, err error) {
+	err = api.call(ctx, "chain", "get_kv_table_rows", params, &out)
+	return
+}
+
 func (api *API) GetRawABI(ctx context.Context, params GetRawABIRequest) (out *GetRawABIResp, err error) {
 	err = api.call(ctx, "chain", "get_raw_abi", params, &out)
 	return
@@ -656,15 +684,69 @@ func (api *API) GetCurrencyStats(ctx context.Context, code AccountName, symbol s
 // See more here: libraries/chain/contracts/abi_serializer.cpp:58...
 
 func (api *API) call(ctx context.Context, baseAPI string, endpoint string, body interface{}, out interface{}) error {
-	jsonBody, err := enc(body)
-	if err != nil {
-		return err
+	var jsonBody []byte
+	if body != nil {
+		reader, err := enc(body)
+		if err != nil {
+			return err
+		}
+
+		jsonBody, err = io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
 	}
 
-	targetURL := fmt.Sprintf("%s/v1/%s/%s", api.BaseURL, baseAPI, endpoint)
-	req, err := http.NewRequest("POST", targetURL, jsonBody)
+	policy := api.RetryPolicy
+	idempotent := isIdempotentCall(baseAPI, endpoint)
+	maxAttempts := policy.maxAttempts()
+
+	for attempt := 0; ; attempt++ {
+		baseURL := api.BaseURL
+		if api.Endpoints != nil {
+			baseURL = api.Endpoints.pick()
+		}
+
+		cerr := api.callOnce(ctx, baseURL, baseAPI, endpoint, jsonBody, out)
+		if api.Endpoints != nil {
+			api.Endpoints.report(baseURL, cerr == nil || !cerr.transient)
+		}
+
+		if cerr == nil {
+			return nil
+		}
+
+		if attempt+1 >= maxAttempts || !policy.shouldRetry(cerr, idempotent) || ctx.Err() != nil {
+			return cerr.err
+		}
+
+		delay := policy.backoff(attempt)
+		zlog.Debug("retrying api call",
+			zap.String("base_url", baseURL),
+			zap.String("endpoint", baseAPI+"/"+endpoint),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("delay", delay),
+			zap.Error(cerr.err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return cerr.err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (api *API) callOnce(ctx context.Context, baseURL string, baseAPI string, endpoint string, jsonBody []byte, out interface{}) *callError {
+	var reqBody io.Reader
+	if jsonBody != nil {
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	targetURL := fmt.Sprintf("%s/v1/%s/%s", baseURL, baseAPI, endpoint)
+	req, err := http.NewRequest("POST", targetURL, reqBody)
 	if err != nil {
-		return fmt.Errorf("NewRequest: %w", err)
+		return &callError{err: fmt.Errorf("NewRequest: %w", err)}
 	}
 
 	for k, v := range api.Header {
@@ -687,37 +769,46 @@ func (api *API) call(ctx context.Context, baseAPI string, endpoint string, body
 
 	resp, err := api.HttpClient.Do(req.WithContext(ctx))
 	if err != nil {
-		return fmt.Errorf("%s: %w", req.URL.String(), err)
+		return &callError{
+			err:       fmt.Errorf("%s: %w", req.URL.String(), err),
+			transient: ctx.Err() == nil,
+			notSent:   isDialError(err),
+		}
 	}
 	defer resp.Body.Close()
 
 	var cnt bytes.Buffer
 	_, err = io.Copy(&cnt, resp.Body)
 	if err != nil {
-		return fmt.Errorf("Copy: %w", err)
+		return &callError{err: fmt.Errorf("Copy: %w", err), transient: ctx.Err() == nil}
 	}
 
 	if resp.StatusCode == 404 {
 		var apiErr APIError
 		if err := json.Unmarshal(cnt.Bytes(), &apiErr); err != nil {
-			return ErrNotFound
+			return &callError{err: ErrNotFound}
 		}
-		return apiErr
+		return &callError{err: apiErr}
 	}
 
 	if resp.StatusCode > 299 {
 		var apiErr APIError
 		if err := json.Unmarshal(cnt.Bytes(), &apiErr); err != nil {
-			return fmt.Errorf("%s: status code=%d, body=%s", req.URL.String(), resp.StatusCode, cnt.String())
+			return &callError{
+				err:       fmt.Errorf("%s: status code=%d, body=%s", req.URL.String(), resp.StatusCode, cnt.String()),
+				transient: isRetryableStatusCode(resp.StatusCode),
+			}
 		}
 
 		// Handle cases where some API calls (/v1/chain/get_account for example) returns a 500
 		// error when retrieving data that does not exist.
 		if apiErr.IsUnknownKeyError() {
-			return ErrNotFound
+			return &callError{err: ErrNotFound}
 		}
 
-		return apiErr
+		// A JSON body that is not a `nodeos` error comes from an intermediate
+		// (proxy, load balancer), the call can be retried.
+		return &callError{err: apiErr, transient: apiErr.ErrorStruct.Name == "" && isRetryableStatusCode(resp.StatusCode)}
 	}
 
 	if api.Debug {
@@ -734,7 +825,7 @@ func (api *API) call(ctx context.Context, baseAPI string, endpoint string, body
 	}
 
 	if err := json.Unmarshal(cnt.Bytes(), &out); err != nil {
-		return fmt.Errorf("Unmarshal: %w", err)
+		return &callError{err: fmt.Errorf("Unmarshal: %w", err)}
 	}
 
 	return nil
@@ -0,0 +1,283 @@
+package eos
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how `API.call` retries failed requests. Set it
+// on `API.RetryPolicy` (or through `API.SetRetryPolicy`) and every
+// API method picks it up.
+//
+// Only transient failures are retried: transport errors, `429` and
+// `5xx` responses whose body is not a well-formed nodeos error
+// (`APIError`). Chain errors (assertion failures, unknown key, etc.)
+// are deterministic and returned right away.
+//
+// Requests that can have side effects (pushing transactions, wallet,
+// producer and net endpoints) are retried only when the request
+// provably never reached the server (failure to connect), unless
+// `RetryPushes` is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the
+	// first one. A value of 0 or 1 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+
+	// Multiplier is applied to the delay after each attempt, defaults
+	// to 2 when zero.
+	Multiplier float64
+
+	// Jitter is the fraction (between 0 and 1) of each delay that is
+	// randomized, to avoid synchronized retries from many clients.
+	Jitter float64
+
+	// RetryPushes allows retrying non-idempotent calls (like
+	// `push_transaction`) on any transient failure. A pushed
+	// transaction is de-duplicated by the chain, so this is mostly
+	// safe, but the caller might then receive a `tx_duplicate` error
+	// for a transaction that was in fact accepted.
+	RetryPushes bool
+}
+
+// DefaultRetryPolicy returns a policy doing 3 attempts with an
+// exponential backoff starting at 100ms.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay to wait after the attempt number `attempt`
+// (0-based) failed.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	if p.InitialBackoff <= 0 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay = delay*(1-jitter) + delay*jitter*rand.Float64()
+	}
+
+	// Without `MaxBackoff`, the delay grows past what a `time.Duration`
+	// holds, where the conversion is undefined.
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(delay)
+}
+
+// nonIdempotentEndpoints are the `baseAPI/endpoint` pairs (or whole
+// `baseAPI`s) whose calls can have side effects on the server.
+var nonIdempotentEndpoints = map[string]bool{
+	"chain/push_transaction":  true,
+	"chain/push_transactions": true,
+	"chain/send_transaction":  true,
+	"chain/send_transaction2": true,
+	"producer":                true,
+	"wallet":                  true,
+	"net":                     true,
+}
+
+func isIdempotentCall(baseAPI, endpoint string) bool {
+	return !nonIdempotentEndpoints[baseAPI] && !nonIdempotentEndpoints[baseAPI+"/"+endpoint]
+}
+
+// callError wraps an error returned by a single attempt of `API.call`
+// along with the information needed to decide if it can be retried.
+type callError struct {
+	err error
+
+	// transient is true when retrying could give a different outcome.
+	transient bool
+
+	// notSent is true when the request never reached the server.
+	notSent bool
+}
+
+func (p *RetryPolicy) shouldRetry(cerr *callError, idempotent bool) bool {
+	if !cerr.transient {
+		return false
+	}
+
+	return idempotent || cerr.notSent || p.RetryPushes
+}
+
+func isRetryableStatusCode(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial"
+	}
+
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// FailoverStrategy defines how an `EndpointPool` picks the endpoint
+// used for the next attempt.
+type FailoverStrategy int
+
+const (
+	// FailoverRoundRobin cycles through endpoints in order, skipping
+	// the ones that failed recently as long as a healthy one exists.
+	FailoverRoundRobin FailoverStrategy = iota
+
+	// FailoverHealthWeighted picks endpoints randomly, with a
+	// probability halved for each consecutive failure of the endpoint.
+	FailoverHealthWeighted
+)
+
+// EndpointPool is a set of `nodeos` base URLs across which `API.call`
+// spreads its requests and fails over. It is safe for concurrent use.
+type EndpointPool struct {
+	Strategy FailoverStrategy
+
+	// Cooldown is the time during which an endpoint that just failed
+	// is avoided by `FailoverRoundRobin`, defaults to 5 seconds.
+	Cooldown time.Duration
+
+	endpoints []*poolEndpoint
+	next      int
+	lock      sync.Mutex
+}
+
+type poolEndpoint struct {
+	baseURL             string
+	consecutiveFailures int
+	lastFailure         time.Time
+}
+
+func NewEndpointPool(strategy FailoverStrategy, baseURLs ...string) *EndpointPool {
+	pool := &EndpointPool{
+		Strategy: strategy,
+		Cooldown: 5 * time.Second,
+	}
+
+	for _, baseURL := range baseURLs {
+		pool.endpoints = append(pool.endpoints, &poolEndpoint{baseURL: strings.TrimRight(baseURL, "/")})
+	}
+
+	return pool
+}
+
+// BaseURLs returns the base URLs of the pool, in the order they were
+// given.
+func (p *EndpointPool) BaseURLs() (out []string) {
+	for _, endpoint := range p.endpoints {
+		out = append(out, endpoint.baseURL)
+	}
+	return
+}
+
+func (p *EndpointPool) pick() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.endpoints) == 0 {
+		return ""
+	}
+
+	if p.Strategy == FailoverHealthWeighted {
+		return p.pickWeighted()
+	}
+
+	return p.pickRoundRobin()
+}
+
+func (p *EndpointPool) pickRoundRobin() string {
+	now := time.Now()
+	count := len(p.endpoints)
+
+	var fallback *poolEndpoint
+	for i := 0; i < count; i++ {
+		endpoint := p.endpoints[(p.next+i)%count]
+		if endpoint.consecutiveFailures == 0 || now.Sub(endpoint.lastFailure) >= p.Cooldown {
+			p.next = (p.next + i + 1) % count
+			return endpoint.baseURL
+		}
+
+		if fallback == nil || endpoint.lastFailure.Before(fallback.lastFailure) {
+			fallback = endpoint
+		}
+	}
+
+	// All endpoints failed recently, use the one that failed the longest ago
+	p.next = (p.next + 1) % count
+	return fallback.baseURL
+}
+
+func (p *EndpointPool) pickWeighted() string {
+	weights := make([]float64, len(p.endpoints))
+	total := 0.0
+	for i, endpoint := range p.endpoints {
+		weights[i] = 1 / math.Pow(2, math.Min(float64(endpoint.consecutiveFailures), 16))
+		total += weights[i]
+	}
+
+	target := rand.Float64() * total
+	for i, weight := range weights {
+		if target < weight {
+			return p.endpoints[i].baseURL
+		}
+		target -= weight
+	}
+
+	return p.endpoints[len(p.endpoints)-1].baseURL
+}
+
+func (p *EndpointPool) report(baseURL string, success bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, endpoint := range p.endpoints {
+		if endpoint.baseURL != baseURL {
+			continue
+		}
+
+		if success {
+			endpoint.consecutiveFailures = 0
+		} else {
+			endpoint.consecutiveFailures++
+			endpoint.lastFailure = time.Now()
+		}
+		return
+	}
+}
@@ -0,0 +1,171 @@
+package eos
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func newFlakyServer(t *testing.T, failures int32, failureStatus int, failureBody string) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(failureStatus)
+			w.Write([]byte(failureBody))
+			return
+		}
+		w.Write([]byte(`{"server_version":"ok","head_block_num":10}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestAPICall_RetryTransientErrors(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, http.StatusBadGateway, "bad gateway")
+
+	api := New(server.URL)
+	api.SetRetryPolicy(testRetryPolicy())
+
+	info, err := api.GetInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), info.HeadBlockNum)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestAPICall_NoRetryWithoutPolicy(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, "unavailable")
+
+	_, err := New(server.URL).GetInfo(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestAPICall_GivesUpAfterMaxAttempts(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable, "unavailable")
+
+	api := New(server.URL)
+	api.SetRetryPolicy(testRetryPolicy())
+
+	_, err := api.GetInfo(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestAPICall_NoRetryOnChainError(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusInternalServerError, `{"code":500,"message":"Internal Service Error","error":{"code":3050003,"name":"eosio_assert_message_exception","what":"eosio_assert_message assertion failure","details":[]}}`)
+
+	api := New(server.URL)
+	api.SetRetryPolicy(testRetryPolicy())
+
+	_, err := api.GetInfo(context.Background())
+	require.Error(t, err)
+	assert.IsType(t, APIError{}, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestAPICall_NoRetryOnPushes(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusBadGateway, "bad gateway")
+
+	api := New(server.URL)
+	api.SetRetryPolicy(testRetryPolicy())
+
+	_, err := api.PushTransaction(context.Background(), &PackedTransaction{})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	policy := testRetryPolicy()
+	policy.RetryPushes = true
+	api.SetRetryPolicy(policy)
+
+	_, err = api.PushTransactionRaw(context.Background(), &PackedTransaction{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestAPICall_FailoverAcrossEndpoints(t *testing.T) {
+	down, downCalls := newFlakyServer(t, 100, http.StatusServiceUnavailable, "unavailable")
+	up, upCalls := newFlakyServer(t, 0, 0, "")
+
+	api := New("")
+	api.SetRetryPolicy(testRetryPolicy())
+	api.SetEndpoints(NewEndpointPool(FailoverRoundRobin, down.URL, up.URL+"/"))
+
+	for i := 0; i < 4; i++ {
+		_, err := api.GetInfo(context.Background())
+		require.NoError(t, err)
+	}
+
+	// The first call hits the failing endpoint and fails over, the
+	// following ones avoid it while it cools down.
+	assert.Equal(t, int32(1), atomic.LoadInt32(downCalls))
+	assert.Equal(t, int32(4), atomic.LoadInt32(upCalls))
+}
+
+func TestAPICall_PushRetriedWhenNotSent(t *testing.T) {
+	up, upCalls := newFlakyServer(t, 0, 0, "")
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	api := New("")
+	api.SetRetryPolicy(testRetryPolicy())
+	api.SetEndpoints(NewEndpointPool(FailoverRoundRobin, closed.URL, up.URL))
+
+	_, err := api.PushTransactionRaw(context.Background(), &PackedTransaction{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(upCalls))
+}
+
+func TestEndpointPool_HealthWeighted(t *testing.T) {
+	pool := NewEndpointPool(FailoverHealthWeighted, "http://a", "http://b")
+	for i := 0; i < 10; i++ {
+		pool.report("http://a", false)
+	}
+
+	picks := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		picks[pool.pick()]++
+	}
+
+	assert.Greater(t, picks["http://b"], 950)
+
+	pool.report("http://a", true)
+	picks = map[string]int{}
+	for i := 0; i < 1000; i++ {
+		picks[pool.pick()]++
+	}
+
+	assert.Greater(t, picks["http://a"], 300)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(0))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(1)
+		assert.True(t, delay >= 100*time.Millisecond && delay <= 200*time.Millisecond, "delay %s out of bounds", delay)
+	}
+
+	unbounded := &RetryPolicy{InitialBackoff: 100 * time.Millisecond}
+	assert.Equal(t, time.Duration(math.MaxInt64), unbounded.backoff(100))
+	assert.Equal(t, time.Duration(math.MaxInt64), unbounded.backoff(10000))
+
+	assert.Equal(t, time.Duration(0), (&RetryPolicy{}).backoff(10000))
+}
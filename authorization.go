@@ -0,0 +1,455 @@
+package eos
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/eoscanada/eos-go/ecc"
+)
+
+// DefaultMaxAuthorityDepth is the default `max_authority_depth` of the
+// chain configuration, limiting the recursion through the accounts of
+// an authority.
+const DefaultMaxAuthorityDepth = 6
+
+var ErrUnknownPermission = errors.New("unknown permission")
+
+// ErrIrrelevantAuthorization is reported when a declared authorization
+// does not satisfy the minimum permission required by the action,
+// following the `linkauth` mappings of the actor.
+var ErrIrrelevantAuthorization = errors.New("irrelevant authorization")
+
+// AuthorizationEvaluator checks, without a node, whether transactions
+// pass the authorization checks of `nodeos`, given the permissions and
+// `linkauth` mappings of the accounts involved and the keys that will
+// sign.
+//
+// The native actions managing permissions (`updateauth`, `deleteauth`,
+// `linkauth`, `unlinkauth` and `canceldelay` of `eosio`) only require
+// their declared authorizations to be satisfied.
+type AuthorizationEvaluator struct {
+	// MaxAuthorityDepth limits the recursion through account
+	// permissions, defaults to `DefaultMaxAuthorityDepth`.
+	MaxAuthorityDepth int
+
+	permissions map[PermissionLevel]*Permission
+	links       map[authorizationLink]PermissionName
+}
+
+type authorizationLink struct {
+	account AccountName
+	code    AccountName
+	action  ActionName
+}
+
+// anyPermission is the special permission a `linkauth` can require so
+// that any permission of the account satisfies it.
+const anyPermission = PermissionName("eosio.any")
+
+func NewAuthorizationEvaluator() *AuthorizationEvaluator {
+	return &AuthorizationEvaluator{
+		MaxAuthorityDepth: DefaultMaxAuthorityDepth,
+		permissions:       map[PermissionLevel]*Permission{},
+		links:             map[authorizationLink]PermissionName{},
+	}
+}
+
+// AddAccount registers the permissions of an account, along with their
+// linked actions, as returned by `API.GetAccount`.
+func (e *AuthorizationEvaluator) AddAccount(account *AccountResp) {
+	for _, permission := range account.Permissions {
+		e.SetPermission(account.AccountName, permission)
+	}
+
+	for _, link := range account.EosioAnyLinkedActions {
+		e.LinkAuth(account.AccountName, link.Account, link.Action, anyPermission)
+	}
+}
+
+// SetPermission registers, or replaces, a permission of an account
+// along with its linked actions.
+func (e *AuthorizationEvaluator) SetPermission(account AccountName, permission Permission) {
+	name := PermissionName(permission.PermName)
+	e.permissions[PermissionLevel{Actor: account, Permission: name}] = &permission
+
+	for _, link := range permission.LinkedActions {
+		e.LinkAuth(account, link.Account, link.Action, name)
+	}
+}
+
+// LinkAuth maps an action of a contract to the permission of `account`
+// it requires, like the `linkauth` action does. An empty `action`
+// applies to all the actions of the contract.
+func (e *AuthorizationEvaluator) LinkAuth(account AccountName, code AccountName, action ActionName, requirement PermissionName) {
+	e.links[authorizationLink{account: account, code: code, action: action}] = requirement
+}
+
+// AuthorizationReport is the result of the evaluation of a transaction.
+type AuthorizationReport struct {
+	Satisfied bool
+	Actions   []*ActionAuthorizationReport
+
+	// UsedKeys are the provided keys needed to satisfy the
+	// authorizations, like returned by `API.GetRequiredKeys`.
+	UsedKeys []ecc.PublicKey
+
+	// UnusedKeys are the provided keys not needed by any authorization,
+	// which `nodeos` rejects as irrelevant signatures.
+	UnusedKeys []ecc.PublicKey
+}
+
+// MissingKeys lists the keys of the unsatisfied authorizations which
+// were not provided.
+func (r *AuthorizationReport) MissingKeys() []ecc.PublicKey {
+	var keys []ecc.PublicKey
+	for _, action := range r.Actions {
+		keys = appendMissingKeys(keys, action.MissingKeys()...)
+	}
+	return keys
+}
+
+type ActionAuthorizationReport struct {
+	Account        AccountName
+	Name           ActionName
+	Satisfied      bool
+	Authorizations []*PermissionReport
+}
+
+func (r *ActionAuthorizationReport) MissingKeys() []ecc.PublicKey {
+	var keys []ecc.PublicKey
+	for _, authorization := range r.Authorizations {
+		if !authorization.Satisfied {
+			keys = appendMissingKeys(keys, authorization.MissingKeys()...)
+		}
+	}
+	return keys
+}
+
+// PermissionReport is the evaluation of a permission, either declared
+// by an action or referenced by an authority.
+type PermissionReport struct {
+	Permission PermissionLevel
+
+	// MinimumPermission is the permission required by the action, for
+	// declared authorizations only.
+	MinimumPermission PermissionName
+
+	Satisfied bool
+	Authority *AuthorityReport // nil if the permission is unknown or too deep
+	Err       error
+}
+
+func (r *PermissionReport) MissingKeys() []ecc.PublicKey {
+	if r.Authority == nil {
+		return nil
+	}
+	return r.Authority.MissingKeys()
+}
+
+// AuthorityReport details which weights of an authority are satisfied.
+type AuthorityReport struct {
+	Threshold uint32
+	Weight    uint32
+	Satisfied bool
+
+	Keys     []*KeyWeightReport
+	Accounts []*PermissionWeightReport
+	Waits    []*WaitWeightReport
+
+	usedKeys []ecc.PublicKey
+}
+
+type KeyWeightReport struct {
+	KeyWeight
+	Satisfied bool
+}
+
+type PermissionWeightReport struct {
+	Weight uint16
+	*PermissionReport
+}
+
+type WaitWeightReport struct {
+	WaitWeight
+	Satisfied bool
+}
+
+// MissingKeys lists the keys not provided, including the ones of the
+// unsatisfied account permissions.
+func (r *AuthorityReport) MissingKeys() []ecc.PublicKey {
+	var keys []ecc.PublicKey
+	for _, key := range r.Keys {
+		if !key.Satisfied {
+			keys = appendMissingKeys(keys, key.PublicKey)
+		}
+	}
+
+	for _, account := range r.Accounts {
+		if !account.Satisfied {
+			keys = appendMissingKeys(keys, account.MissingKeys()...)
+		}
+	}
+
+	return keys
+}
+
+// EvaluateTransaction evaluates the authorizations of all the actions
+// of a transaction, signed by `keys`. The delay of the transaction
+// satisfies the waits of the authorities.
+func (e *AuthorizationEvaluator) EvaluateTransaction(tx *Transaction, keys []ecc.PublicKey) *AuthorizationReport {
+	delay := time.Duration(tx.DelaySec) * time.Second
+	report := &AuthorizationReport{Satisfied: true}
+
+	used := map[string]bool{}
+	for _, action := range tx.Actions {
+		actionReport := e.EvaluateAction(action, keys, delay)
+		report.Actions = append(report.Actions, actionReport)
+		report.Satisfied = report.Satisfied && actionReport.Satisfied
+
+		for _, authorization := range actionReport.Authorizations {
+			if !authorization.Satisfied {
+				continue
+			}
+
+			for _, key := range authorization.Authority.usedKeys {
+				if !used[key.String()] {
+					used[key.String()] = true
+					report.UsedKeys = append(report.UsedKeys, key)
+				}
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if !used[key.String()] {
+			report.UnusedKeys = append(report.UnusedKeys, key)
+		}
+	}
+
+	return report
+}
+
+// EvaluateAction evaluates the authorizations declared by an action,
+// signed by `keys` and delayed by `delay`.
+func (e *AuthorizationEvaluator) EvaluateAction(action *Action, keys []ecc.PublicKey, delay time.Duration) *ActionAuthorizationReport {
+	report := &ActionAuthorizationReport{
+		Account:   action.Account,
+		Name:      action.Name,
+		Satisfied: len(action.Authorization) > 0,
+	}
+
+	checker := &authorityChecker{
+		evaluator: e,
+		keys:      map[string]bool{},
+		delay:     delay,
+	}
+	for _, key := range keys {
+		checker.keys[key.String()] = true
+	}
+
+	for _, level := range action.Authorization {
+		authorization := e.evaluateDeclaredAuthorization(checker, action, level)
+		report.Authorizations = append(report.Authorizations, authorization)
+		report.Satisfied = report.Satisfied && authorization.Satisfied
+	}
+
+	return report
+}
+
+// EvaluatePermission evaluates the authority of a permission, without
+// any action, signed by `keys` without delay.
+func (e *AuthorizationEvaluator) EvaluatePermission(level PermissionLevel, keys []ecc.PublicKey) *PermissionReport {
+	checker := &authorityChecker{
+		evaluator: e,
+		keys:      map[string]bool{},
+	}
+	for _, key := range keys {
+		checker.keys[key.String()] = true
+	}
+
+	return checker.evaluatePermission(level, 0)
+}
+
+func (e *AuthorizationEvaluator) evaluateDeclaredAuthorization(checker *authorityChecker, action *Action, level PermissionLevel) *PermissionReport {
+	report := checker.evaluatePermission(level, 0)
+	if report.Err != nil {
+		return report
+	}
+
+	minimum, err := e.minimumPermission(action, level)
+	if err != nil {
+		report.Satisfied, report.Err = false, err
+		return report
+	}
+
+	report.MinimumPermission = minimum
+	if !e.satisfies(level, minimum) {
+		report.Satisfied = false
+		report.Err = fmt.Errorf("%w: action %s::%s requires %s@%s or a parent, declared %s@%s", ErrIrrelevantAuthorization, action.Account, action.Name, level.Actor, minimum, level.Actor, level.Permission)
+	}
+
+	return report
+}
+
+// minimumPermission finds the permission of the actor required by the
+// action, following its `linkauth` mappings.
+func (e *AuthorizationEvaluator) minimumPermission(action *Action, level PermissionLevel) (PermissionName, error) {
+	if action.Account == AccountName("eosio") {
+		switch action.Name {
+		case ActN("updateauth"), ActN("deleteauth"), ActN("linkauth"), ActN("unlinkauth"), ActN("canceldelay"):
+			return level.Permission, nil
+		}
+	}
+
+	minimum, found := e.links[authorizationLink{account: level.Actor, code: action.Account, action: action.Name}]
+	if !found {
+		minimum, found = e.links[authorizationLink{account: level.Actor, code: action.Account}]
+	}
+	if !found {
+		minimum = PermissionName("active")
+	}
+
+	if minimum == anyPermission {
+		return level.Permission, nil
+	}
+
+	if e.permissions[PermissionLevel{Actor: level.Actor, Permission: minimum}] == nil {
+		return "", fmt.Errorf("%w: %s@%s", ErrUnknownPermission, level.Actor, minimum)
+	}
+
+	return minimum, nil
+}
+
+// satisfies tells if `level` is the permission `minimum` of the same
+// actor, or one of its parents.
+func (e *AuthorizationEvaluator) satisfies(level PermissionLevel, minimum PermissionName) bool {
+	current := minimum
+	for depth := 0; depth <= len(e.permissions); depth++ {
+		if current == level.Permission {
+			return true
+		}
+
+		permission := e.permissions[PermissionLevel{Actor: level.Actor, Permission: current}]
+		if permission == nil || permission.Parent == "" {
+			return false
+		}
+		current = PermissionName(permission.Parent)
+	}
+
+	return false
+}
+
+type authorityChecker struct {
+	evaluator *AuthorizationEvaluator
+	keys      map[string]bool
+	delay     time.Duration
+}
+
+func (c *authorityChecker) evaluatePermission(level PermissionLevel, depth int) *PermissionReport {
+	report := &PermissionReport{Permission: level}
+
+	permission := c.evaluator.permissions[level]
+	if permission == nil {
+		report.Err = fmt.Errorf("%w: %s@%s", ErrUnknownPermission, level.Actor, level.Permission)
+		return report
+	}
+
+	report.Authority = c.evaluateAuthority(permission.RequiredAuth, depth)
+	report.Satisfied = report.Authority.Satisfied
+	return report
+}
+
+// evaluateAuthority checks every weight of the authority. Like in
+// `nodeos`, the keys used are the ones counted, heaviest weights first,
+// until the threshold is reached.
+func (c *authorityChecker) evaluateAuthority(authority Authority, depth int) *AuthorityReport {
+	report := &AuthorityReport{Threshold: authority.Threshold}
+
+	type weight struct {
+		weight   uint16
+		kind     int
+		keys     []ecc.PublicKey
+		provided bool
+	}
+	var weights []weight
+
+	for _, wait := range authority.Waits {
+		satisfied := c.delay >= time.Duration(wait.WaitSec)*time.Second
+		report.Waits = append(report.Waits, &WaitWeightReport{WaitWeight: wait, Satisfied: satisfied})
+		weights = append(weights, weight{weight: wait.Weight, kind: 0, provided: satisfied})
+	}
+
+	for _, key := range authority.Keys {
+		satisfied := c.keys[key.PublicKey.String()]
+		report.Keys = append(report.Keys, &KeyWeightReport{KeyWeight: key, Satisfied: satisfied})
+		weights = append(weights, weight{weight: key.Weight, kind: 1, keys: []ecc.PublicKey{key.PublicKey}, provided: satisfied})
+	}
+
+	for _, account := range authority.Accounts {
+		accountReport := &PermissionWeightReport{Weight: account.Weight}
+		if depth >= c.evaluator.MaxAuthorityDepth {
+			accountReport.PermissionReport = &PermissionReport{
+				Permission: account.Permission,
+				Err:        fmt.Errorf("maximum authority depth %d reached at %s@%s", c.evaluator.MaxAuthorityDepth, account.Permission.Actor, account.Permission.Permission),
+			}
+		} else {
+			accountReport.PermissionReport = c.evaluatePermission(account.Permission, depth+1)
+		}
+
+		report.Accounts = append(report.Accounts, accountReport)
+
+		w := weight{weight: account.Weight, kind: 2, provided: accountReport.Satisfied}
+		if accountReport.Satisfied {
+			w.keys = accountReport.Authority.usedKeys
+		}
+		weights = append(weights, w)
+	}
+
+	sort.SliceStable(weights, func(i, j int) bool {
+		if weights[i].weight != weights[j].weight {
+			return weights[i].weight > weights[j].weight
+		}
+		return weights[i].kind < weights[j].kind
+	})
+
+	var total uint32
+	var usedKeys []ecc.PublicKey
+	for _, w := range weights {
+		if !w.provided {
+			continue
+		}
+
+		if total < authority.Threshold {
+			usedKeys = append(usedKeys, w.keys...)
+		}
+		total += uint32(w.weight)
+	}
+
+	report.Weight = total
+	report.Satisfied = total >= authority.Threshold
+	if report.Satisfied {
+		report.usedKeys = usedKeys
+	}
+
+	return report
+}
+
+func appendMissingKeys(keys []ecc.PublicKey, missing ...ecc.PublicKey) []ecc.PublicKey {
+	for _, key := range missing {
+		found := false
+		for _, existing := range keys {
+			if existing.String() == key.String() {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
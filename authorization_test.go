@@ -0,0 +1,189 @@
+package eos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eoscanada/eos-go/ecc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPublicKeys(t *testing.T, count int) []ecc.PublicKey {
+	var keys []ecc.PublicKey
+	for i := 0; i < count; i++ {
+		privateKey, err := ecc.NewRandomPrivateKey()
+		require.NoError(t, err)
+		keys = append(keys, privateKey.PublicKey())
+	}
+	return keys
+}
+
+func keyAuthority(key ecc.PublicKey) Authority {
+	return Authority{Threshold: 1, Keys: []KeyWeight{{PublicKey: key, Weight: 1}}}
+}
+
+func testAuthorizationEvaluator(keys []ecc.PublicKey) *AuthorizationEvaluator {
+	e := NewAuthorizationEvaluator()
+	e.AddAccount(&AccountResp{
+		AccountName: "alice",
+		Permissions: []Permission{
+			{PermName: "owner", RequiredAuth: keyAuthority(keys[0])},
+			{PermName: "active", Parent: "owner", RequiredAuth: keyAuthority(keys[1])},
+			{PermName: "transfer", Parent: "active", RequiredAuth: keyAuthority(keys[2]), LinkedActions: []LinkedAction{
+				{Account: "eosio.token", Action: "transfer"},
+			}},
+			{PermName: "other", Parent: "active", RequiredAuth: keyAuthority(keys[3])},
+		},
+	})
+
+	e.SetPermission("bob", Permission{PermName: "active", Parent: "owner", RequiredAuth: Authority{
+		Threshold: 2,
+		Keys:      []KeyWeight{{PublicKey: keys[4], Weight: 1}},
+		Accounts:  []PermissionLevelWeight{{Permission: PermissionLevel{Actor: "alice", Permission: "active"}, Weight: 1}},
+		Waits:     []WaitWeight{{WaitSec: 3600, Weight: 1}},
+	}})
+
+	return e
+}
+
+func testTransferAction(from AccountName, permission PermissionName) *Action {
+	return &Action{
+		Account:       "eosio.token",
+		Name:          "transfer",
+		Authorization: []PermissionLevel{{Actor: from, Permission: permission}},
+	}
+}
+
+func TestAuthorizationEvaluator_Keys(t *testing.T) {
+	keys := newTestPublicKeys(t, 5)
+	e := testAuthorizationEvaluator(keys)
+
+	tx := &Transaction{Actions: []*Action{testTransferAction("alice", "active")}}
+
+	report := e.EvaluateTransaction(tx, []ecc.PublicKey{keys[1], keys[4]})
+	assert.True(t, report.Satisfied)
+	assert.Equal(t, []ecc.PublicKey{keys[1]}, report.UsedKeys)
+	assert.Equal(t, []ecc.PublicKey{keys[4]}, report.UnusedKeys)
+	assert.Empty(t, report.MissingKeys())
+
+	authorization := report.Actions[0].Authorizations[0]
+	assert.Equal(t, PermissionName("transfer"), authorization.MinimumPermission)
+	assert.Equal(t, uint32(1), authorization.Authority.Weight)
+
+	report = e.EvaluateTransaction(tx, nil)
+	assert.False(t, report.Satisfied)
+	assert.Empty(t, report.UsedKeys)
+	assert.Equal(t, []ecc.PublicKey{keys[1]}, report.MissingKeys())
+}
+
+func TestAuthorizationEvaluator_LinkAuth(t *testing.T) {
+	keys := newTestPublicKeys(t, 5)
+	e := testAuthorizationEvaluator(keys)
+
+	tests := []struct {
+		name        string
+		action      *Action
+		key         ecc.PublicKey
+		expectedErr error
+	}{
+		{"linked permission", testTransferAction("alice", "transfer"), keys[2], nil},
+		{"parent of linked permission", testTransferAction("alice", "owner"), keys[0], nil},
+		{"unrelated permission", testTransferAction("alice", "other"), keys[3], ErrIrrelevantAuthorization},
+		{"unknown permission", testTransferAction("alice", "unknown"), keys[3], ErrUnknownPermission},
+		{"default to active", &Action{Account: "eosio.token", Name: "open", Authorization: []PermissionLevel{{Actor: "alice", Permission: "transfer"}}}, keys[2], ErrIrrelevantAuthorization},
+		{"native actions", &Action{Account: "eosio", Name: "updateauth", Authorization: []PermissionLevel{{Actor: "alice", Permission: "other"}}}, keys[3], nil},
+		{"no authorization", &Action{Account: "eosio.token", Name: "transfer"}, keys[2], nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := e.EvaluateAction(test.action, []ecc.PublicKey{test.key}, 0)
+
+			if len(test.action.Authorization) == 0 {
+				assert.False(t, report.Satisfied)
+				return
+			}
+
+			authorization := report.Authorizations[0]
+			if test.expectedErr != nil {
+				assert.False(t, report.Satisfied)
+				assert.ErrorIs(t, authorization.Err, test.expectedErr)
+				return
+			}
+
+			assert.True(t, report.Satisfied)
+			assert.NoError(t, authorization.Err)
+		})
+	}
+
+	e.LinkAuth("alice", "eosio.token", "", "eosio.any")
+	report := e.EvaluateAction(&Action{Account: "eosio.token", Name: "open", Authorization: []PermissionLevel{{Actor: "alice", Permission: "other"}}}, []ecc.PublicKey{keys[3]}, 0)
+	assert.True(t, report.Satisfied)
+}
+
+func TestAuthorizationEvaluator_Delegation(t *testing.T) {
+	keys := newTestPublicKeys(t, 5)
+	e := testAuthorizationEvaluator(keys)
+
+	tx := &Transaction{Actions: []*Action{testTransferAction("bob", "active")}}
+
+	report := e.EvaluateTransaction(tx, []ecc.PublicKey{keys[1], keys[4]})
+	require.True(t, report.Satisfied)
+	assert.ElementsMatch(t, []ecc.PublicKey{keys[1], keys[4]}, report.UsedKeys)
+
+	authority := report.Actions[0].Authorizations[0].Authority
+	assert.Equal(t, uint32(2), authority.Weight)
+	assert.True(t, authority.Accounts[0].Satisfied)
+	assert.True(t, authority.Accounts[0].Authority.Keys[0].Satisfied)
+	assert.False(t, authority.Waits[0].Satisfied)
+
+	report = e.EvaluateTransaction(tx, []ecc.PublicKey{keys[4]})
+	assert.False(t, report.Satisfied)
+	assert.Equal(t, uint32(1), report.Actions[0].Authorizations[0].Authority.Weight)
+	assert.Equal(t, []ecc.PublicKey{keys[1]}, report.MissingKeys())
+
+	// the delay satisfies the wait
+	tx.DelaySec = 3600
+	report = e.EvaluateTransaction(tx, []ecc.PublicKey{keys[4]})
+	assert.True(t, report.Satisfied)
+	assert.Equal(t, []ecc.PublicKey{keys[4]}, report.UsedKeys)
+
+	report = e.EvaluateTransaction(tx, []ecc.PublicKey{keys[1], keys[4]})
+	assert.True(t, report.Satisfied)
+	assert.Len(t, report.UsedKeys, 1, "only the heaviest weights up to the threshold are used")
+}
+
+func TestAuthorizationEvaluator_MaxAuthorityDepth(t *testing.T) {
+	keys := newTestPublicKeys(t, 1)
+
+	e := NewAuthorizationEvaluator()
+	accounts := []AccountName{"account1", "account2", "account3", "account4"}
+	for i, account := range accounts {
+		authority := keyAuthority(keys[0])
+		if i < len(accounts)-1 {
+			authority = Authority{Threshold: 1, Accounts: []PermissionLevelWeight{
+				{Permission: PermissionLevel{Actor: accounts[i+1], Permission: "active"}, Weight: 1},
+			}}
+		}
+		e.SetPermission(account, Permission{PermName: "active", RequiredAuth: authority})
+	}
+
+	action := testTransferAction("account1", "active")
+	assert.True(t, e.EvaluateAction(action, keys, 0).Satisfied)
+
+	e.MaxAuthorityDepth = 2
+	report := e.EvaluateAction(action, keys, 0)
+	assert.False(t, report.Satisfied)
+
+	deepest := report.Authorizations[0].Authority.Accounts[0].Authority.Accounts[0].Authority.Accounts[0]
+	assert.Nil(t, deepest.Authority)
+	assert.Error(t, deepest.Err)
+
+	// cycles stop at the depth limit too
+	e.SetPermission("account4", Permission{PermName: "active", RequiredAuth: Authority{Threshold: 1, Accounts: []PermissionLevelWeight{
+		{Permission: PermissionLevel{Actor: "account1", Permission: "active"}, Weight: 1},
+	}}})
+	e.MaxAuthorityDepth = DefaultMaxAuthorityDepth
+	assert.False(t, e.EvaluateAction(action, keys, time.Hour).Satisfied)
+}
@@ -0,0 +1,398 @@
+package eos
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// BlockStep is the kind of change a `BlockEvent` applies to the chain
+// as seen by a `BlockFollower`.
+type BlockStep int
+
+const (
+	// BlockStepNew is a block seen for the first time, applied on top of
+	// the previously delivered block.
+	BlockStepNew BlockStep = iota
+
+	// BlockStepUndo reverts a previously delivered block that was
+	// forked out. Undo events are delivered from the highest block down.
+	BlockStepUndo
+
+	// BlockStepRedo re-applies a block that was previously undone,
+	// because the chain switched back to its fork.
+	BlockStepRedo
+)
+
+func (s BlockStep) String() string {
+	switch s {
+	case BlockStepNew:
+		return "new"
+	case BlockStepUndo:
+		return "undo"
+	case BlockStepRedo:
+		return "redo"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// BlockCursor identifies the last block delivered by a `BlockFollower`.
+// Persist its `String()` representation to resume following later on
+// with `FollowFromCursor`.
+type BlockCursor struct {
+	BlockNum uint32
+	BlockID  Checksum256
+}
+
+func (c *BlockCursor) String() string {
+	return fmt.Sprintf("%d:%s", c.BlockNum, c.BlockID)
+}
+
+// ParseBlockCursor reads a cursor in the format produced by
+// `BlockCursor.String()`.
+func ParseBlockCursor(cursor string) (*BlockCursor, error) {
+	parts := strings.Split(cursor, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid block cursor %q, expected <block_num>:<block_id>", cursor)
+	}
+
+	blockNum, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block cursor %q block num: %w", cursor, err)
+	}
+
+	blockID, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid block cursor %q block id: %w", cursor, err)
+	}
+
+	return &BlockCursor{BlockNum: uint32(blockNum), BlockID: blockID}, nil
+}
+
+// maxPendingBlockEvents bounds the number of blocks fetched in a single
+// poll, so catching up from far behind does not buffer the whole range.
+const maxPendingBlockEvents = 100
+
+// BlockEvent is emitted by `BlockFollower.Next`.
+type BlockEvent struct {
+	Step     BlockStep
+	BlockNum uint32
+	BlockID  Checksum256
+
+	// Block is the full block, as returned by `GetBlockByNum`: the
+	// `SignedBlock` along with its ID and number. For undo events of
+	// blocks delivered before the follower was resumed from a cursor, it
+	// is fetched by ID from the node's fork database.
+	Block *BlockResp
+
+	// Cursor points to the last block applied once this event is
+	// processed. For an undo event, that is the parent of the undone block.
+	Cursor *BlockCursor
+}
+
+// BlockFollower polls a node through `GetInfo` and `GetBlockByNum` and
+// yields blocks in order, detecting micro-forks by following the
+// `Previous` block IDs.
+type BlockFollower struct {
+	api *API
+
+	irreversibleOnly bool
+	pollInterval     time.Duration
+	startBlockNum    uint32
+	startCursor      *BlockCursor
+
+	// segment holds the delivered blocks that can still be forked out,
+	// last one being the current head of the follower.
+	segment []*followedBlock
+	undone  map[string]*BlockResp
+	pending []*BlockEvent
+	lib     uint32
+	started bool
+}
+
+type followedBlock struct {
+	num      uint32
+	id       Checksum256
+	previous Checksum256
+	block    *BlockResp
+}
+
+type BlockFollowerOption interface {
+	apply(f *BlockFollower)
+}
+
+type blockFollowerOptionFunc func(f *BlockFollower)
+
+func (o blockFollowerOptionFunc) apply(f *BlockFollower) {
+	o(f)
+}
+
+// FollowIrreversibleOnly makes the follower deliver only blocks that
+// are irreversible (up to `LastIrreversibleBlockNum`). No undo events
+// are ever emitted in this mode: resuming from a cursor above the last
+// irreversible block, or from one which is not part of the irreversible
+// chain, fails instead.
+func FollowIrreversibleOnly() BlockFollowerOption {
+	return blockFollowerOptionFunc(func(f *BlockFollower) {
+		f.irreversibleOnly = true
+	})
+}
+
+// FollowPollInterval configures the delay between two `GetInfo` calls
+// once the follower has caught up with the chain, defaults to 500ms.
+func FollowPollInterval(interval time.Duration) BlockFollowerOption {
+	return blockFollowerOptionFunc(func(f *BlockFollower) {
+		f.pollInterval = interval
+	})
+}
+
+// FollowFromBlockNum starts following at the given block (inclusive).
+// Without it, the follower starts at the head (or last irreversible)
+// block at the time of the first call.
+func FollowFromBlockNum(blockNum uint32) BlockFollowerOption {
+	return blockFollowerOptionFunc(func(f *BlockFollower) {
+		f.startBlockNum = blockNum
+	})
+}
+
+// FollowFromCursor resumes following right after the block identified
+// by the cursor. If that block was forked out in the meantime, undo
+// events are emitted first.
+func FollowFromCursor(cursor *BlockCursor) BlockFollowerOption {
+	return blockFollowerOptionFunc(func(f *BlockFollower) {
+		f.startCursor = cursor
+	})
+}
+
+func NewBlockFollower(api *API, opts ...BlockFollowerOption) *BlockFollower {
+	f := &BlockFollower{
+		api:          api,
+		pollInterval: 500 * time.Millisecond,
+		undone:       make(map[string]*BlockResp),
+	}
+
+	for _, opt := range opts {
+		opt.apply(f)
+	}
+
+	return f
+}
+
+// Cursor returns the cursor of the last delivered block. Before any
+// block is delivered, it points to the parent of the first block to
+// deliver, or is nil if the follower did not start yet.
+func (f *BlockFollower) Cursor() *BlockCursor {
+	if len(f.segment) == 0 {
+		return f.startCursor
+	}
+
+	head := f.segment[len(f.segment)-1]
+	return &BlockCursor{BlockNum: head.num, BlockID: head.id}
+}
+
+// Next blocks until the next event is available, or the context is
+// done.
+func (f *BlockFollower) Next(ctx context.Context) (*BlockEvent, error) {
+	for {
+		if len(f.pending) > 0 {
+			event := f.pending[0]
+			f.pending = f.pending[1:]
+			return event, nil
+		}
+
+		if err := f.poll(ctx); err != nil {
+			return nil, err
+		}
+
+		if len(f.pending) > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(f.pollInterval):
+		}
+	}
+}
+
+// poll fetches blocks up to the current target and queues the
+// resulting events.
+func (f *BlockFollower) poll(ctx context.Context) error {
+	info, err := f.api.GetInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("get info: %w", err)
+	}
+
+	target := info.HeadBlockNum
+	if f.irreversibleOnly {
+		target = info.LastIrreversibleBlockNum
+	}
+	f.lib = info.LastIrreversibleBlockNum
+
+	if !f.started {
+		if err := f.start(ctx, target); err != nil {
+			return err
+		}
+		f.started = true
+	}
+
+	// A head resumed from a cursor must first be checked against the
+	// chain, it could have been forked out while we were away.
+	for f.head().block == nil && f.head().num <= target {
+		head := f.head()
+		block, err := f.api.GetBlockByNum(ctx, head.num)
+		if err != nil {
+			return fmt.Errorf("get cursor block %d: %w", head.num, err)
+		}
+
+		if bytes.Equal(block.ID, head.id) {
+			head.previous = block.Previous
+			head.block = block
+			break
+		}
+
+		if f.irreversibleOnly {
+			return fmt.Errorf("cursor block %d (%s) is not irreversible block %s", head.num, head.id, block.ID)
+		}
+
+		if err := f.undoHead(ctx); err != nil {
+			return err
+		}
+	}
+
+	for f.head().num < target && len(f.pending) < maxPendingBlockEvents {
+		nextNum := f.head().num + 1
+		block, err := f.api.GetBlockByNum(ctx, nextNum)
+		if err != nil {
+			return fmt.Errorf("get block %d: %w", nextNum, err)
+		}
+
+		if err := f.apply(ctx, block); err != nil {
+			return err
+		}
+	}
+
+	f.prune()
+	return nil
+}
+
+func (f *BlockFollower) start(ctx context.Context, target uint32) error {
+	if f.startCursor != nil {
+		if f.irreversibleOnly && f.startCursor.BlockNum > target {
+			return fmt.Errorf("cannot resume following irreversible blocks from cursor block %d, above last irreversible block %d", f.startCursor.BlockNum, target)
+		}
+
+		f.segment = []*followedBlock{{num: f.startCursor.BlockNum, id: f.startCursor.BlockID}}
+		return nil
+	}
+
+	startBlockNum := f.startBlockNum
+	if startBlockNum == 0 {
+		startBlockNum = target
+	}
+
+	if startBlockNum <= 1 {
+		return fmt.Errorf("cannot start following at block %d, the first block has no parent", startBlockNum)
+	}
+
+	// Seed the segment with the parent of the first block to deliver, so
+	// the first fetched block can be linked to it.
+	parent, err := f.api.GetBlockByNum(ctx, startBlockNum-1)
+	if err != nil {
+		return fmt.Errorf("get start block parent %d: %w", startBlockNum-1, err)
+	}
+
+	f.segment = []*followedBlock{{num: parent.BlockNum, id: parent.ID, previous: parent.Previous, block: parent}}
+	return nil
+}
+
+func (f *BlockFollower) head() *followedBlock {
+	return f.segment[len(f.segment)-1]
+}
+
+func (f *BlockFollower) apply(ctx context.Context, block *BlockResp) error {
+	head := f.head()
+	if bytes.Equal(block.Previous, head.id) {
+		step := BlockStepNew
+		if _, found := f.undone[block.ID.String()]; found {
+			step = BlockStepRedo
+			delete(f.undone, block.ID.String())
+		}
+
+		f.segment = append(f.segment, &followedBlock{num: block.BlockNum, id: block.ID, previous: block.Previous, block: block})
+		f.queue(step, block.BlockNum, block.ID, block)
+		return nil
+	}
+
+	if f.irreversibleOnly && head.block != nil {
+		return fmt.Errorf("irreversible block %d (%s) does not link to previous block %s", block.BlockNum, block.ID, head.id)
+	}
+
+	if head.num <= f.lib && head.block != nil {
+		return fmt.Errorf("fork detected at block %d (%s) below last irreversible block %d", block.BlockNum, block.ID, f.lib)
+	}
+
+	// Our head was forked out, undo it and try again to link from its
+	// parent on the next iteration.
+	return f.undoHead(ctx)
+}
+
+func (f *BlockFollower) undoHead(ctx context.Context) error {
+	head := f.head()
+	if head.previous == nil {
+		resp, err := f.api.GetBlockByID(ctx, head.id.String())
+		if err != nil {
+			return fmt.Errorf("get forked block %d (%s) to find its parent: %w", head.num, head.id, err)
+		}
+		head.previous = resp.Previous
+		head.block = resp
+	}
+
+	zlog.Debug("undoing forked block", zap.Uint32("block_num", head.num), zap.Stringer("block_id", head.id))
+
+	f.segment = f.segment[:len(f.segment)-1]
+	if len(f.segment) == 0 {
+		f.segment = []*followedBlock{{num: head.num - 1, id: head.previous}}
+	}
+
+	if head.block != nil {
+		f.undone[head.id.String()] = head.block
+	}
+	f.queue(BlockStepUndo, head.num, head.id, head.block)
+
+	return nil
+}
+
+func (f *BlockFollower) queue(step BlockStep, blockNum uint32, blockID Checksum256, block *BlockResp) {
+	f.pending = append(f.pending, &BlockEvent{
+		Step:     step,
+		BlockNum: blockNum,
+		BlockID:  blockID,
+		Block:    block,
+		Cursor:   f.Cursor(),
+	})
+}
+
+// prune drops the blocks that became irreversible, they cannot be
+// forked out anymore. The head is always kept.
+func (f *BlockFollower) prune() {
+	cut := 0
+	for cut < len(f.segment)-1 && f.segment[cut].num < f.lib {
+		cut++
+	}
+	f.segment = f.segment[cut:]
+
+	for id, block := range f.undone {
+		if block.BlockNum <= f.lib {
+			delete(f.undone, id)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package eos
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testChainBlock struct {
+	num      uint32
+	id       Checksum256
+	previous Checksum256
+}
+
+// testChain simulates the `get_info` and `get_block` endpoints of a
+// node, allowing to switch between forks.
+type testChain struct {
+	lock      sync.Mutex
+	blocks    map[string]*testChainBlock
+	canonical map[uint32]*testChainBlock
+	head      uint32
+	lib       uint32
+}
+
+func testBlockID(num uint32, fork byte) Checksum256 {
+	id := make(Checksum256, 32)
+	binary.BigEndian.PutUint32(id, num)
+	id[4] = fork
+	return id
+}
+
+func newTestChain(head, lib uint32) *testChain {
+	c := &testChain{blocks: map[string]*testChainBlock{}, canonical: map[uint32]*testChainBlock{}}
+	c.extend(1, head, 0)
+	c.lib = lib
+	return c
+}
+
+// extend builds blocks `from` through `to` on fork `fork`, linking the
+// first one to the current canonical block `from - 1`.
+func (c *testChain) extend(from, to uint32, fork byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for num := from; num <= to; num++ {
+		block := &testChainBlock{num: num, id: testBlockID(num, fork)}
+		if parent := c.canonical[num-1]; parent != nil {
+			block.previous = parent.id
+		} else {
+			block.previous = make(Checksum256, 32)
+		}
+
+		c.blocks[block.id.String()] = block
+		c.canonical[num] = block
+	}
+
+	for num := to + 1; num <= c.head; num++ {
+		delete(c.canonical, num)
+	}
+	c.head = to
+}
+
+func (c *testChain) setLIB(lib uint32) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.lib = lib
+}
+
+func (c *testChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/chain/get_info":
+		json.NewEncoder(w).Encode(M{"head_block_num": c.head, "last_irreversible_block_num": c.lib})
+	case "/v1/chain/get_block":
+		var params struct {
+			BlockNumOrID string `json:"block_num_or_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&params)
+
+		var block *testChainBlock
+		if num, err := strconv.ParseUint(params.BlockNumOrID, 10, 32); err == nil {
+			block = c.canonical[uint32(num)]
+		} else {
+			block = c.blocks[params.BlockNumOrID]
+		}
+
+		if block == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"code":400,"message":"Invalid Request","error":{"code":3100002,"name":"unknown_block_exception"}}`)
+			return
+		}
+
+		json.NewEncoder(w).Encode(M{"id": block.id, "block_num": block.num, "previous": block.previous, "timestamp": "2018-06-01T12:00:00.000"})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestFollower(t *testing.T, chain *testChain, opts ...BlockFollowerOption) *BlockFollower {
+	server := httptest.NewServer(chain)
+	t.Cleanup(server.Close)
+
+	return NewBlockFollower(New(server.URL), append([]BlockFollowerOption{FollowPollInterval(time.Millisecond)}, opts...)...)
+}
+
+func nextEvents(t *testing.T, f *BlockFollower, count int) (out []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < count; i++ {
+		event, err := f.Next(ctx)
+		require.NoError(t, err)
+		out = append(out, fmt.Sprintf("%s %d/%d", event.Step, event.BlockNum, event.BlockID[4]))
+	}
+	return
+}
+
+func TestBlockFollower_New(t *testing.T) {
+	chain := newTestChain(10, 5)
+	f := newTestFollower(t, chain, FollowFromBlockNum(8))
+
+	assert.Equal(t, []string{"new 8/0", "new 9/0", "new 10/0"}, nextEvents(t, f, 3))
+
+	chain.extend(11, 12, 0)
+	assert.Equal(t, []string{"new 11/0", "new 12/0"}, nextEvents(t, f, 2))
+	assert.Equal(t, "12:0000000c00000000000000000000000000000000000000000000000000000000", f.Cursor().String())
+}
+
+func TestBlockFollower_ForkUndoRedo(t *testing.T) {
+	chain := newTestChain(10, 5)
+	f := newTestFollower(t, chain, FollowFromBlockNum(9))
+
+	assert.Equal(t, []string{"new 9/0", "new 10/0"}, nextEvents(t, f, 2))
+
+	// Switch to fork 1 from block 9
+	chain.extend(9, 11, 1)
+	assert.Equal(t, []string{"undo 10/0", "undo 9/0", "new 9/1", "new 10/1", "new 11/1"}, nextEvents(t, f, 5))
+
+	// Switch back to fork 0, which is now longer
+	chain.extend(9, 10, 0)
+	chain.extend(11, 12, 0)
+	assert.Equal(t, []string{"undo 11/1", "undo 10/1", "undo 9/1", "redo 9/0", "redo 10/0", "new 11/0", "new 12/0"}, nextEvents(t, f, 7))
+}
+
+func TestBlockFollower_IrreversibleOnly(t *testing.T) {
+	chain := newTestChain(10, 5)
+	f := newTestFollower(t, chain, FollowIrreversibleOnly(), FollowFromBlockNum(4))
+
+	assert.Equal(t, []string{"new 4/0", "new 5/0"}, nextEvents(t, f, 2))
+
+	chain.extend(9, 12, 1)
+	chain.setLIB(7)
+	assert.Equal(t, []string{"new 6/0", "new 7/0"}, nextEvents(t, f, 2))
+}
+
+func TestBlockFollower_ResumeFromForkedCursor(t *testing.T) {
+	chain := newTestChain(10, 5)
+	chain.extend(9, 10, 1)
+
+	cursor, err := ParseBlockCursor("10:" + testBlockID(10, 0).String())
+	require.NoError(t, err)
+
+	f := newTestFollower(t, chain, FollowFromCursor(cursor))
+	assert.Equal(t, []string{"undo 10/0", "undo 9/0", "new 9/1", "new 10/1"}, nextEvents(t, f, 4))
+}
+
+func TestBlockFollower_IrreversibleOnlyResumeFromReversibleCursor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	chain := newTestChain(10, 5)
+	cursor, err := ParseBlockCursor("8:" + testBlockID(8, 0).String())
+	require.NoError(t, err)
+
+	f := newTestFollower(t, chain, FollowIrreversibleOnly(), FollowFromCursor(cursor))
+	_, err = f.Next(ctx)
+	assert.Error(t, err)
+
+	chain.extend(8, 10, 1)
+	chain.setLIB(9)
+	_, err = f.Next(ctx)
+	assert.Error(t, err)
+
+	cursor, err = ParseBlockCursor("8:" + testBlockID(8, 1).String())
+	require.NoError(t, err)
+
+	f = newTestFollower(t, chain, FollowIrreversibleOnly(), FollowFromCursor(cursor))
+	assert.Equal(t, []string{"new 9/1"}, nextEvents(t, f, 1))
+}
+
+func TestParseBlockCursor(t *testing.T) {
+	_, err := ParseBlockCursor("10")
+	assert.Error(t, err)
+
+	_, err = ParseBlockCursor("abc:00")
+	assert.Error(t, err)
+
+	cursor, err := ParseBlockCursor("10:0000000a")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), cursor.BlockNum)
+	assert.Equal(t, "10:0000000a", cursor.String())
+}
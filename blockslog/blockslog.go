@@ -1,79 +1,159 @@
+// Package blockslog reads and writes the irreversible blocks log of
+// `nodeos` (`blocks.log`) along with its index (`blocks.index`).
+//
+// The log starts with a header (version, first block number, genesis
+// state or chain ID), followed by one entry per block, each entry
+// being the packed `signed_block` followed by the `uint64` position of
+// the start of the entry in the file. The index is the list of those
+// positions, one `uint64` per block, starting at the first block.
+//
+// Versions 1 to 4 are supported. Version 4 entries, written by `nodeos`
+// 2.1, start with their size and the compression of the context-free
+// data they hold, and hold the block in its prunable format, converted
+// from and to `eos.SignedBlock`.
+//
+// Logs whose oldest blocks were pruned (`block-log-retain-blocks`) are
+// flagged in the high bit of their version, and end with the number of
+// blocks they still hold, the pruned entries being zeroed.
 package blockslog
 
 import (
-	"encoding/binary"
-	"encoding/hex"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 
 	"github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/ecc"
 )
 
-func Process(filename string) error {
-	fl, err := os.Open(filename)
-	if err != nil {
-		return err
-	}
+const (
+	// MinSupportedVersion is the oldest log version that can be read.
+	MinSupportedVersion uint32 = 1
 
-	versionData := make([]byte, 4)
-	_, err = fl.Read(versionData)
-	if err != nil {
-		return err
-	}
+	// MaxSupportedVersion is the most recent log version that can be
+	// read and written, the one of `nodeos` 2.1.
+	MaxSupportedVersion uint32 = 4
 
-	version := versionData[0]
+	// DefaultVersion is the version of the logs written when none is
+	// given, the most widely read one.
+	DefaultVersion uint32 = 3
 
-	fmt.Println("Version", version)
+	// prunableBlockVersion is the first version whose entries start
+	// with their size and compression, holding prunable blocks.
+	prunableBlockVersion uint32 = 4
 
-	firstBlockData := []byte{1, 0, 0, 0}
+	// prunedVersionFlag flags, in the version, the logs whose oldest
+	// blocks were pruned.
+	prunedVersionFlag uint32 = 1 << 31
 
-	if version > 1 {
-		fmt.Println("Reading first block")
-		_, err = fl.Read(firstBlockData)
-		if err != nil {
-			return err
-		}
-	}
+	// totem separates the header from the first entry, starting with
+	// version 2.
+	totem uint64 = 0xffffffffffffffff
+)
 
-	firstBlockNum := binary.LittleEndian.Uint32(firstBlockData)
-	fmt.Println("First block", firstBlockNum)
+var ErrUnsupportedVersion = errors.New("unsupported blocks log version")
 
-	// Certain conditions where the genesis state is written:
-	// bool block_log::contains_genesis_state(uint32_t version, uint32_t first_block_num) {
-	//    return version <= 2 || first_block_num == 1;
-	// }
+// ErrBlockNotFound is returned when reading a block outside the range
+// of the log.
+var ErrBlockNotFound = errors.New("block not found in blocks log")
 
-	chainID := make([]byte, 32)
-	if version >= 3 && firstBlockNum > 1 {
-		fmt.Println("Reading Chain ID")
-		_, err = fl.Read(chainID)
-		if err != nil {
-			return err
-		}
+// Header is the header of a blocks log.
+type Header struct {
+	Version       uint32
+	FirstBlockNum uint32
+
+	// Pruned tells whether the oldest blocks of the log were pruned, in
+	// which case `FirstBlockNum` is the first block the log ever held,
+	// not the first one it still holds.
+	Pruned bool
+
+	// Genesis is the genesis state of the chain, present for versions 1
+	// and 2, and for version 3 when the log starts at block 1.
+	Genesis *GenesisState
+
+	// ChainID is read from the header for version 3 when the log does
+	// not start at block 1, otherwise computed from `Genesis`.
+	ChainID eos.Checksum256
+}
+
+// ContainsGenesisState tells whether the header stores the genesis
+// state (otherwise it stores the chain ID).
+func (h *Header) ContainsGenesisState() bool {
+	return containsGenesisState(h.Version, h.FirstBlockNum)
+}
+
+func containsGenesisState(version uint32, firstBlockNum uint32) bool {
+	return version <= 2 || firstBlockNum == 1
+}
+
+type GenesisState struct {
+	InitialTimestamp     eos.TimePoint `json:"initial_timestamp"`
+	InitialKey           ecc.PublicKey `json:"initial_key"`
+	InitialConfiguration ChainConfig   `json:"initial_configuration"`
+}
+
+// ChainID computes the chain ID, which is the hash of the packed
+// genesis state.
+func (g *GenesisState) ChainID() (eos.Checksum256, error) {
+	data, err := eos.MarshalBinary(g)
+	if err != nil {
+		return nil, fmt.Errorf("pack genesis state: %w", err)
 	}
 
-	fmt.Println("Chain ID", hex.EncodeToString(chainID))
+	hash := sha256.Sum256(data)
+	return hash[:], nil
+}
 
-	if version != 1 {
-		totem := make([]byte, 8)
-		_, err := fl.Read(totem)
-		if err != nil {
-			return err
-		}
-		fmt.Println("Totem", totem)
+// NewGenesisStateFromJSON reads a `genesis.json` file as used by
+// `nodeos`.
+func NewGenesisStateFromJSON(r io.Reader) (*GenesisState, error) {
+	var genesis GenesisState
+	if err := json.NewDecoder(r).Decode(&genesis); err != nil {
+		return nil, fmt.Errorf("decode genesis state: %w", err)
 	}
 
-	for i := 0 ; i < 5; i++{
-		cnt := make([]byte, 1000000)
+	return &genesis, nil
+}
 
-		prevPos, err := fl.Seek(0, os.SEEK_CUR)
-		if err != nil {
-			return err
-		}
+type ChainConfig struct {
+	MaxBlockNetUsage               uint64 `json:"max_block_net_usage"`
+	TargetBlockNetUsagePct         uint32 `json:"target_block_net_usage_pct"`
+	MaxTransactionNetUsage         uint32 `json:"max_transaction_net_usage"`
+	BasePerTransactionNetUsage     uint32 `json:"base_per_transaction_net_usage"`
+	NetUsageLeeway                 uint32 `json:"net_usage_leeway"`
+	ContextFreeDiscountNetUsageNum uint32 `json:"context_free_discount_net_usage_num"`
+	ContextFreeDiscountNetUsageDen uint32 `json:"context_free_discount_net_usage_den"`
+	MaxBlockCPUUsage               uint32 `json:"max_block_cpu_usage"`
+	TargetBlockCPUUsagePct         uint32 `json:"target_block_cpu_usage_pct"`
+	MaxTransactionCPUUsage         uint32 `json:"max_transaction_cpu_usage"`
+	MinTransactionCPUUsage         uint32 `json:"min_transaction_cpu_usage"`
+	MaxTransactionLifetime         uint32 `json:"max_transaction_lifetime"`
+	DeferredTrxExpirationWindow    uint32 `json:"deferred_trx_expiration_window"`
+	MaxTransactionDelay            uint32 `json:"max_transaction_delay"`
+	MaxInlineActionSize            uint32 `json:"max_inline_action_size"`
+	MaxInlineActionDepth           uint16 `json:"max_inline_action_depth"`
+	MaxAuthorityDepth              uint16 `json:"max_authority_depth"`
+}
+
+// Process prints the header and the first blocks of a blocks log.
+//
+// Deprecated: Use NewReader instead.
+func Process(filename string) error {
+	reader, err := NewReader(filename)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
 
-		_, err = fl.Read(cnt)
+	fmt.Println("Version", reader.Header.Version)
+	fmt.Println("First block", reader.Header.FirstBlockNum)
+	fmt.Println("Chain ID", reader.Header.ChainID)
+
+	for i := 0; i < 5; i++ {
+		block, err := reader.Next()
 		if err == io.EOF {
 			break
 		}
@@ -81,25 +161,29 @@ func Process(filename string) error {
 			return err
 		}
 
-		d := eos.NewDecoder(cnt)
-		var block *eos.SignedBlock
-
-		if err := d.Decode(&block); err != nil {
-			return fmt.Errorf("decoding signed block: %w", err)
-		}
-
 		jsonStr, err := json.Marshal(block)
 		if err != nil {
 			return err
 		}
 
 		fmt.Println(string(jsonStr))
+	}
 
-		if _, err = fl.Seek(prevPos+int64(d.LastPos())+8, os.SEEK_SET); err != nil {
-			return err
-		}
-		fmt.Println("Last pos", d.LastPos())
+	return nil
+}
 
+// IndexPath returns the path of the index file next to a blocks log,
+// `blocks.index` for `blocks.log`.
+func IndexPath(logPath string) string {
+	ext := ".log"
+	if len(logPath) >= len(ext) && logPath[len(logPath)-len(ext):] == ext {
+		return logPath[:len(logPath)-len(ext)] + ".index"
 	}
-	return nil
+
+	return logPath + ".index"
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
@@ -1,13 +1,519 @@
 package blockslog
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/ecc"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestMe(t *testing.T) {
-	t.Skip("Update me so that it's not tied to a particular machine!")
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
 
-	require.NoError(t, Process("/some/path/blocks.log"))
+func testGenesis(t *testing.T) *GenesisState {
+	key, err := ecc.NewPublicKey("EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV")
+	require.NoError(t, err)
+
+	return &GenesisState{
+		InitialTimestamp: eos.TimePoint(1528445288888000),
+		InitialKey:       key,
+		InitialConfiguration: ChainConfig{
+			MaxBlockNetUsage:               1048576,
+			TargetBlockNetUsagePct:         1000,
+			MaxTransactionNetUsage:         524288,
+			BasePerTransactionNetUsage:     12,
+			NetUsageLeeway:                 500,
+			ContextFreeDiscountNetUsageNum: 20,
+			ContextFreeDiscountNetUsageDen: 100,
+			MaxBlockCPUUsage:               200000,
+			TargetBlockCPUUsagePct:         1000,
+			MaxTransactionCPUUsage:         150000,
+			MinTransactionCPUUsage:         100,
+			MaxTransactionLifetime:         3600,
+			DeferredTrxExpirationWindow:    600,
+			MaxTransactionDelay:            3888000,
+			MaxInlineActionSize:            4096,
+			MaxInlineActionDepth:           4,
+			MaxAuthorityDepth:              6,
+		},
+	}
+}
+
+// testBlocks builds a chain of `count` blocks starting at block 1,
+// signed by the well-known development key.
+func testBlocks(t *testing.T, count int) []*eos.SignedBlock {
+	key, err := ecc.NewPrivateKey("5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3")
+	require.NoError(t, err)
+
+	var blocks []*eos.SignedBlock
+	previous := eos.Checksum256(make([]byte, 32))
+	for i := 0; i < count; i++ {
+		block := &eos.SignedBlock{}
+		block.Timestamp = eos.BlockTimestamp{Time: time.Date(2018, 6, 8, 8, 8, 8, 500000000, time.UTC).Add(time.Duration(i) * 500 * time.Millisecond)}
+		block.Producer = "eosio"
+		block.Previous = previous
+		block.TransactionMRoot = make([]byte, 32)
+		block.ActionMRoot = make([]byte, 32)
+		block.HeaderExtensions = []*eos.Extension{}
+		block.Transactions = []eos.TransactionReceipt{}
+		block.BlockExtensions = []*eos.Extension{}
+
+		id, err := block.BlockID()
+		require.NoError(t, err)
+
+		digest := sha256.Sum256(id)
+		block.ProducerSignature, err = key.Sign(digest[:])
+		require.NoError(t, err)
+
+		blocks = append(blocks, block)
+		previous = id
+	}
+
+	return blocks
+}
+
+func writeTestLog(t *testing.T, logPath string, header *Header, blocks []*eos.SignedBlock) {
+	writer, err := NewWriter(logPath, header)
+	require.NoError(t, err)
+
+	for _, block := range blocks {
+		require.NoError(t, writer.AppendBlock(block))
+	}
+	require.NoError(t, writer.Close())
+}
+
+// assertGolden compares a file produced by a test with its golden
+// version in `testdata`, updating the later with `go test -update`.
+func assertGolden(t *testing.T, path string, golden string) {
+	actual, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	golden = filepath.Join("testdata", golden)
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(golden, actual, 0644))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(expected, actual), "%s differs from golden file %s", path, golden)
+}
+
+// pruneTestLog prunes the blocks before `firstBlockNum` from a log the
+// way `nodeos` does, flagging its version, zeroing the pruned entries
+// and appending the number of blocks left. The index is left as is.
+func pruneTestLog(t *testing.T, logPath string, firstBlockNum uint32) {
+	reader, err := NewReader(logPath)
+	require.NoError(t, err)
+
+	start, err := reader.position(reader.FirstBlockNum())
+	require.NoError(t, err)
+	end, err := reader.position(firstBlockNum)
+	require.NoError(t, err)
+	retainedCount := reader.LastBlockNum() + 1 - firstBlockNum
+	require.NoError(t, reader.Close())
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	data[3] |= 0x80
+	copy(data[start:end], make([]byte, end-start))
+	data = append(data, encodeUint32(retainedCount)...)
+	require.NoError(t, os.WriteFile(logPath, data, 0644))
+}
+
+func copyGolden(t *testing.T, dir string, golden string) string {
+	data, err := os.ReadFile(filepath.Join("testdata", golden))
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, golden)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestWriter_Golden(t *testing.T) {
+	dir := t.TempDir()
+	blocks := testBlocks(t, 5)
+
+	tests := []struct {
+		name   string
+		header *Header
+	}{
+		{"v1", &Header{Version: 1, Genesis: testGenesis(t)}},
+		{"v2", &Header{Version: 2, Genesis: testGenesis(t)}},
+		{"v3", &Header{Version: 3, Genesis: testGenesis(t)}},
+		{"v4", &Header{Version: 4, Genesis: testGenesis(t)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logPath := filepath.Join(dir, test.name+".blocks.log")
+			writeTestLog(t, logPath, test.header, blocks)
+
+			assertGolden(t, logPath, test.name+".blocks.log")
+			assertGolden(t, IndexPath(logPath), test.name+".blocks.index")
+		})
+	}
+
+	t.Run("v3-trimmed", func(t *testing.T) {
+		logPath := filepath.Join(dir, "v3-trimmed.blocks.log")
+		require.NoError(t, Trim(filepath.Join(dir, "v2.blocks.log"), logPath, 3, 4))
+
+		assertGolden(t, logPath, "v3-trimmed.blocks.log")
+		assertGolden(t, IndexPath(logPath), "v3-trimmed.blocks.index")
+	})
+
+	t.Run("v4-pruned", func(t *testing.T) {
+		logPath := copyGolden(t, dir, "v4.blocks.log")
+		copyGolden(t, dir, "v4.blocks.index")
+		pruneTestLog(t, logPath, 3)
+
+		assertGolden(t, logPath, "v4-pruned.blocks.log")
+		assertGolden(t, IndexPath(logPath), "v4-pruned.blocks.index")
+	})
+
+	t.Run("mainnet-273283700-v4", func(t *testing.T) {
+		reader, err := NewReader(filepath.Join("testdata", "mainnet-273283700.blocks.log"))
+		require.NoError(t, err)
+		defer reader.Close()
+
+		block, err := reader.ReadBlock(273283700)
+		require.NoError(t, err)
+
+		logPath := filepath.Join(dir, "mainnet-273283700-v4.blocks.log")
+		writeTestLog(t, logPath, &Header{Version: 4, FirstBlockNum: 273283700, ChainID: reader.Header.ChainID}, []*eos.SignedBlock{block})
+
+		assertGolden(t, logPath, "mainnet-273283700-v4.blocks.log")
+		assertGolden(t, IndexPath(logPath), "mainnet-273283700-v4.blocks.index")
+	})
+}
+
+func TestReader_Golden(t *testing.T) {
+	blocks := testBlocks(t, 5)
+	genesis := testGenesis(t)
+
+	chainID, err := genesis.ChainID()
+	require.NoError(t, err)
+
+	tests := []struct {
+		log           string
+		version       uint32
+		firstBlockNum uint32
+		lastBlockNum  uint32
+		withGenesis   bool
+	}{
+		{"v1.blocks.log", 1, 1, 5, true},
+		{"v2.blocks.log", 2, 1, 5, true},
+		{"v3.blocks.log", 3, 1, 5, true},
+		{"v3-trimmed.blocks.log", 3, 3, 4, false},
+		{"v4.blocks.log", 4, 1, 5, true},
+		{"v4-pruned.blocks.log", 4, 3, 5, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.log, func(t *testing.T) {
+			reader, err := NewReader(filepath.Join("testdata", test.log))
+			require.NoError(t, err)
+			defer reader.Close()
+
+			assert.Equal(t, test.version, reader.Header.Version)
+			assert.Equal(t, test.firstBlockNum, reader.FirstBlockNum())
+			assert.Equal(t, test.lastBlockNum, reader.LastBlockNum())
+			assert.Equal(t, chainID, reader.Header.ChainID)
+			if test.withGenesis {
+				assert.Equal(t, genesis, reader.Header.Genesis)
+			} else {
+				assert.Nil(t, reader.Header.Genesis)
+			}
+
+			for blockNum := test.firstBlockNum; blockNum <= test.lastBlockNum; blockNum++ {
+				block, err := reader.Next()
+				require.NoError(t, err)
+				assertSameBlock(t, blocks[blockNum-1], block)
+			}
+
+			_, err = reader.Next()
+			assert.Equal(t, io.EOF, err)
+
+			block, err := reader.ReadBlock(test.lastBlockNum)
+			require.NoError(t, err)
+			assertSameBlock(t, blocks[test.lastBlockNum-1], block)
+
+			_, err = reader.ReadBlock(test.lastBlockNum + 1)
+			assert.ErrorIs(t, err, ErrBlockNotFound)
+		})
+	}
+}
+
+func assertSameBlock(t *testing.T, expected *eos.SignedBlock, actual *eos.SignedBlock) {
+	t.Helper()
+
+	expectedID, err := expected.BlockID()
+	require.NoError(t, err)
+	actualID, err := actual.BlockID()
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedID, actualID)
+	assert.Equal(t, expected.ProducerSignature.String(), actual.ProducerSignature.String())
+}
+
+func TestReader_WithoutIndex(t *testing.T) {
+	logPath := copyGolden(t, t.TempDir(), "v2.blocks.log")
+
+	reader, err := NewReader(logPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	assert.Nil(t, reader.index)
+	assert.Equal(t, uint32(5), reader.LastBlockNum())
+
+	block, err := reader.ReadBlock(3)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), block.BlockNumber())
+}
+
+func TestReader_Pruned(t *testing.T) {
+	dir := t.TempDir()
+	blocks := testBlocks(t, 6)
+
+	// Without index, the first block is found from the last one.
+	logPath := copyGolden(t, dir, "v4-pruned.blocks.log")
+
+	reader, err := NewReader(logPath)
+	require.NoError(t, err)
+
+	assert.Nil(t, reader.index)
+	assert.True(t, reader.Header.Pruned)
+	assert.Equal(t, uint32(1), reader.Header.FirstBlockNum)
+	assert.Equal(t, uint32(3), reader.FirstBlockNum())
+	assert.Equal(t, uint32(5), reader.LastBlockNum())
+	assert.Equal(t, uint32(3), reader.BlockCount())
+
+	_, err = reader.ReadBlock(2)
+	assert.ErrorIs(t, err, ErrBlockNotFound)
+
+	block, err := reader.ReadBlock(3)
+	require.NoError(t, err)
+	assertSameBlock(t, blocks[2], block)
+	require.NoError(t, reader.Close())
+
+	writer, err := OpenWriter(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(6), writer.NextBlockNum())
+	require.NoError(t, writer.AppendBlock(blocks[5]))
+	require.NoError(t, writer.Close())
+
+	reader, err = NewReader(logPath)
+	require.NoError(t, err)
+
+	assert.NotNil(t, reader.index)
+	assert.Equal(t, uint32(3), reader.FirstBlockNum())
+	assert.Equal(t, uint32(6), reader.LastBlockNum())
+
+	block, err = reader.ReadBlock(6)
+	require.NoError(t, err)
+	assertSameBlock(t, blocks[5], block)
+	require.NoError(t, reader.Close())
+
+	// Truncating every block left keeps the log pruned up to block 2.
+	require.NoError(t, Truncate(logPath, 2))
+
+	writer, err = OpenWriter(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), writer.NextBlockNum())
+	require.NoError(t, writer.AppendBlock(blocks[2]))
+	require.NoError(t, writer.Close())
+
+	reader, err = NewReader(logPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	assert.Equal(t, uint32(3), reader.FirstBlockNum())
+	assert.Equal(t, uint32(3), reader.LastBlockNum())
+
+	block, err = reader.ReadBlock(3)
+	require.NoError(t, err)
+	assertSameBlock(t, blocks[2], block)
+}
+
+// TestReader_PrunableTransactions reads a version 4 block whose
+// transactions have their context-free data as zlib compressed
+// segments, or pruned.
+func TestReader_PrunableTransactions(t *testing.T) {
+	block := testBlocks(t, 1)[0]
+
+	tx := eos.NewSignedTransaction(&eos.Transaction{TransactionHeader: eos.TransactionHeader{Expiration: eos.JSONTime{Time: time.Date(2018, 6, 8, 8, 10, 0, 0, time.UTC)}}})
+	tx.ContextFreeData = []eos.HexBytes{{0x01}, {0x02, 0x03}}
+	packed, err := tx.Pack(eos.CompressionZlib)
+	require.NoError(t, err)
+
+	// The ID of a transaction is the digest of its uncompressed form.
+	uncompressed, err := tx.Pack(eos.CompressionNone)
+	require.NoError(t, err)
+	expectedID, err := uncompressed.ID()
+	require.NoError(t, err)
+
+	var segments []*contextFreeSegment
+	for _, data := range tx.ContextFreeData {
+		compressed, err := deflate(data)
+		require.NoError(t, err)
+		segments = append(segments, &contextFreeSegment{Data: compressed})
+	}
+
+	prunable := newPrunableBlock(block)
+	prunable.PruneState = pruneStateComplete
+	prunable.Transactions = []*prunableTransactionReceipt{
+		{Packed: &prunablePackedTransaction{
+			Compression:         eos.CompressionZlib,
+			PrunableType:        prunableFull,
+			ContextFreeSegments: segments,
+			PackedTransaction:   packed.PackedTransaction,
+		}},
+		{Packed: &prunablePackedTransaction{
+			Compression:       eos.CompressionZlib,
+			PrunableType:      prunableSignaturesOnly,
+			Digest:            make([]byte, 32),
+			PackedTransaction: packed.PackedTransaction,
+		}},
+	}
+
+	data, err := eos.MarshalBinary(prunable)
+	require.NoError(t, err)
+
+	logPath := filepath.Join(t.TempDir(), "blocks.log")
+	writer, err := NewWriter(logPath, &Header{Version: 4, Genesis: testGenesis(t)})
+	require.NoError(t, err)
+	require.NoError(t, writer.appendEntry(data, segmentCompressionZlib))
+	require.NoError(t, writer.Close())
+
+	reader, err := NewReader(logPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	actual, err := reader.ReadBlock(1)
+	require.NoError(t, err)
+	assertSameBlock(t, block, actual)
+	require.Len(t, actual.Transactions, 2)
+
+	full := actual.Transactions[0].Transaction
+	assert.Equal(t, expectedID, full.ID)
+	expectedCFD, err := inflate(packed.PackedContextFreeData)
+	require.NoError(t, err)
+	actualCFD, err := inflate(full.Packed.PackedContextFreeData)
+	require.NoError(t, err)
+	assert.Equal(t, expectedCFD, actualCFD)
+
+	pruned := actual.Transactions[1].Transaction
+	assert.Equal(t, expectedID, pruned.ID)
+	assert.Empty(t, pruned.Packed.PackedContextFreeData)
+}
+
+func TestRebuildIndex(t *testing.T) {
+	dir := t.TempDir()
+	logPath := copyGolden(t, dir, "v3.blocks.log")
+
+	// Corrupted index, referencing only the first blocks
+	index, err := os.ReadFile(filepath.Join("testdata", "v3.blocks.index"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(IndexPath(logPath), index[:16], 0644))
+
+	require.NoError(t, RebuildIndexIfInvalid(logPath))
+	assertGolden(t, IndexPath(logPath), "v3.blocks.index")
+}
+
+func TestWriter_AppendAndTruncate(t *testing.T) {
+	dir := t.TempDir()
+	blocks := testBlocks(t, 5)
+
+	logPath := filepath.Join(dir, "blocks.log")
+	writeTestLog(t, logPath, &Header{Genesis: testGenesis(t)}, blocks[:3])
+
+	writer, err := OpenWriter(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4), writer.NextBlockNum())
+	assert.Error(t, writer.AppendBlock(blocks[4]))
+	require.NoError(t, writer.AppendBlock(blocks[3]))
+	require.NoError(t, writer.AppendBlock(blocks[4]))
+	require.NoError(t, writer.Close())
+
+	assertGolden(t, logPath, "v3.blocks.log")
+	assertGolden(t, IndexPath(logPath), "v3.blocks.index")
+
+	require.NoError(t, Truncate(logPath, 2))
+
+	reader, err := NewReader(logPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	assert.NotNil(t, reader.index)
+	assert.Equal(t, uint32(2), reader.LastBlockNum())
+
+	block, err := reader.ReadBlock(2)
+	require.NoError(t, err)
+	assertSameBlock(t, blocks[1], block)
+}
+
+func TestNewReader_UnsupportedVersion(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "blocks.log")
+	require.NoError(t, os.WriteFile(logPath, []byte{0x05, 0x00, 0x00, 0x00}, 0644))
+
+	_, err := NewReader(logPath)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	// pruned log flag
+	require.NoError(t, os.WriteFile(logPath, []byte{0x05, 0x00, 0x00, 0x80}, 0644))
+
+	_, err = NewReader(logPath)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+// TestReader_MainnetBlock reads logs holding the EOS mainnet block
+// 273283700, packed from the `get_block` response of `nodeos` in
+// `testdata/mock_server`, checking its ID against the one `nodeos`
+// reported.
+func TestReader_MainnetBlock(t *testing.T) {
+	t.Run("v3", func(t *testing.T) {
+		testReaderMainnetBlock(t, "mainnet-273283700.blocks.log", 3)
+	})
+	t.Run("v4", func(t *testing.T) {
+		testReaderMainnetBlock(t, "mainnet-273283700-v4.blocks.log", 4)
+	})
+}
+
+func testReaderMainnetBlock(t *testing.T, log string, version uint32) {
+	reader, err := NewReader(filepath.Join("testdata", log))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	assert.Equal(t, version, reader.Header.Version)
+	assert.Equal(t, "aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906", reader.Header.ChainID.String())
+	assert.Nil(t, reader.Header.Genesis)
+	assert.Equal(t, uint32(273283700), reader.FirstBlockNum())
+	assert.Equal(t, uint32(273283700), reader.LastBlockNum())
+
+	block, err := reader.ReadBlock(273283700)
+	require.NoError(t, err)
+
+	id, err := block.BlockID()
+	require.NoError(t, err)
+	assert.Equal(t, "1049fa74670c8d45c83cfd6b54683edb186b5205bf84c66141afe55765499f7c", id.String())
+	assert.Equal(t, eos.AccountName("eosiosg11111"), block.Producer)
+	require.Len(t, block.Transactions, 1)
+
+	txID, err := block.Transactions[0].Transaction.Packed.ID()
+	require.NoError(t, err)
+	assert.Equal(t, "3b842c3b6eb260028a51bc9c4b1cf9587b393a0607b45dafd7c5279c200c3e24", txID.String())
+}
+
+func TestIndexPath(t *testing.T) {
+	assert.Equal(t, "/data/blocks/blocks.index", IndexPath("/data/blocks/blocks.log"))
+	assert.Equal(t, "blocks.index", IndexPath("blocks.log"))
+	assert.Equal(t, "blocks.index", IndexPath("blocks"))
 }
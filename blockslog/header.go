@@ -0,0 +1,138 @@
+package blockslog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/eoscanada/eos-go"
+)
+
+// readHeader reads the header at the start of `r`, returning it along
+// with its size in bytes, which is the position of the first entry.
+func readHeader(r io.Reader) (*Header, int64, error) {
+	counter := &countingReader{r: r}
+
+	var version uint32
+	if err := binary.Read(counter, binary.LittleEndian, &version); err != nil {
+		return nil, 0, fmt.Errorf("read version: %w", err)
+	}
+
+	pruned := version&prunedVersionFlag != 0
+	version &^= prunedVersionFlag
+
+	if version < MinSupportedVersion || version > MaxSupportedVersion {
+		return nil, 0, fmt.Errorf("%w: %d, supported versions are %d to %d", ErrUnsupportedVersion, version, MinSupportedVersion, MaxSupportedVersion)
+	}
+
+	header := &Header{Version: version, FirstBlockNum: 1, Pruned: pruned}
+	if version > 1 {
+		if err := binary.Read(counter, binary.LittleEndian, &header.FirstBlockNum); err != nil {
+			return nil, 0, fmt.Errorf("read first block num: %w", err)
+		}
+	}
+
+	if header.ContainsGenesisState() {
+		genesis, err := readGenesisState(counter)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		header.Genesis = genesis
+		if header.ChainID, err = genesis.ChainID(); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		header.ChainID = make(eos.Checksum256, 32)
+		if _, err := io.ReadFull(counter, header.ChainID); err != nil {
+			return nil, 0, fmt.Errorf("read chain id: %w", err)
+		}
+	}
+
+	if version > 1 {
+		var value uint64
+		if err := binary.Read(counter, binary.LittleEndian, &value); err != nil {
+			return nil, 0, fmt.Errorf("read totem: %w", err)
+		}
+
+		if value != totem {
+			return nil, 0, fmt.Errorf("invalid totem %x, expected %x", value, totem)
+		}
+	}
+
+	return header, counter.count, nil
+}
+
+// readGenesisState reads a packed genesis state, which has a fixed
+// size except for its public key.
+func readGenesisState(r io.Reader) (*GenesisState, error) {
+	// initial_timestamp (8) + initial_key type (1) + K1/R1 key (33) +
+	// chain_config (68)
+	data := make([]byte, 8+1+33+68)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("read genesis state: %w", err)
+	}
+
+	var genesis GenesisState
+	if err := eos.UnmarshalBinary(data, &genesis); err != nil {
+		return nil, fmt.Errorf("decode genesis state: %w", err)
+	}
+
+	return &genesis, nil
+}
+
+func encodeHeader(header *Header) ([]byte, error) {
+	if header.Version < MinSupportedVersion || header.Version > MaxSupportedVersion {
+		return nil, fmt.Errorf("%w: %d, supported versions are %d to %d", ErrUnsupportedVersion, header.Version, MinSupportedVersion, MaxSupportedVersion)
+	}
+
+	if header.Version == 1 && header.FirstBlockNum != 1 {
+		return nil, fmt.Errorf("version 1 blocks log must start at block 1, got %d", header.FirstBlockNum)
+	}
+
+	version := header.Version
+	if header.Pruned {
+		version |= prunedVersionFlag
+	}
+
+	buffer := &bytes.Buffer{}
+	binary.Write(buffer, binary.LittleEndian, version)
+	if header.Version > 1 {
+		binary.Write(buffer, binary.LittleEndian, header.FirstBlockNum)
+	}
+
+	if header.ContainsGenesisState() {
+		if header.Genesis == nil {
+			return nil, fmt.Errorf("genesis state is required for version %d starting at block %d", header.Version, header.FirstBlockNum)
+		}
+
+		data, err := eos.MarshalBinary(header.Genesis)
+		if err != nil {
+			return nil, fmt.Errorf("pack genesis state: %w", err)
+		}
+		buffer.Write(data)
+	} else {
+		if len(header.ChainID) != 32 {
+			return nil, fmt.Errorf("chain id is required for version %d starting at block %d", header.Version, header.FirstBlockNum)
+		}
+		buffer.Write(header.ChainID)
+	}
+
+	if header.Version > 1 {
+		binary.Write(buffer, binary.LittleEndian, totem)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+type countingReader struct {
+	r     io.Reader
+	count int64
+}
+
+func (c *countingReader) Read(p []byte) (n int, err error) {
+	n, err = c.r.Read(p)
+	c.count += int64(n)
+	return
+}
@@ -0,0 +1,376 @@
+package blockslog
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/ecc"
+)
+
+// Version 4 logs hold the blocks in the `signed_block` format of
+// `nodeos` 2.1, where each transaction keeps its signatures and
+// context-free data in a `prunable_data` variant, so they can be pruned
+// in place. They are converted from and to the `eos.SignedBlock` of the
+// previous versions.
+
+// prune states of a version 4 block, `signed_block::prune_state_type`
+const (
+	pruneStateIncomplete uint8 = iota
+	pruneStateComplete
+	pruneStateCompleteLegacy
+)
+
+// types of the `prunable_data` variant of a version 4 transaction
+const (
+	prunableFullLegacy uint32 = iota
+	prunableNone
+	prunableSignaturesOnly
+	prunablePartial
+	prunableFull
+)
+
+// compressions of the context-free segments of a version 4 entry,
+// `packed_transaction::cf_compression_type`
+const (
+	segmentCompressionNone uint8 = iota
+	segmentCompressionZlib
+)
+
+type prunableBlock struct {
+	eos.SignedBlockHeader
+	PruneState      uint8
+	Transactions    []*prunableTransactionReceipt
+	BlockExtensions []*eos.Extension
+}
+
+// prunableTransactionReceipt holds either the ID of a transaction or
+// the transaction itself.
+type prunableTransactionReceipt struct {
+	eos.TransactionReceiptHeader
+	ID     eos.Checksum256
+	Packed *prunablePackedTransaction
+}
+
+type prunablePackedTransaction struct {
+	Compression  eos.CompressionType
+	PrunableType uint32
+
+	// Signatures of every prunable data type but `none`.
+	Signatures []ecc.Signature
+	// Digest is the digest of the pruned data for `none`, and the merkle
+	// root of the pruned context-free data for `signatures_only`.
+	Digest eos.Checksum256
+	// PackedContextFreeData of `full_legacy`.
+	PackedContextFreeData []byte
+	// ContextFreeSegments of `partial` and `full`.
+	ContextFreeSegments []*contextFreeSegment
+
+	PackedTransaction eos.HexBytes
+}
+
+// contextFreeSegment is a context-free data segment, or its digest
+// once pruned.
+type contextFreeSegment struct {
+	Digest eos.Checksum256
+	Data   []byte
+}
+
+func (r *prunableTransactionReceipt) UnmarshalBinary(decoder *eos.Decoder) error {
+	if err := decoder.Decode(&r.TransactionReceiptHeader); err != nil {
+		return fmt.Errorf("transaction receipt header: %w", err)
+	}
+
+	trxType, err := decoder.ReadUvarint32()
+	if err != nil {
+		return fmt.Errorf("transaction receipt type: %w", err)
+	}
+
+	switch trxType {
+	case 0:
+		r.ID, err = decoder.ReadChecksum256()
+		return err
+	case 1:
+		r.Packed = &prunablePackedTransaction{}
+		return r.Packed.UnmarshalBinary(decoder)
+	default:
+		return fmt.Errorf("invalid transaction receipt type %d", trxType)
+	}
+}
+
+func (r *prunableTransactionReceipt) MarshalBinary(encoder *eos.Encoder) error {
+	if err := encoder.Encode(r.TransactionReceiptHeader); err != nil {
+		return err
+	}
+
+	if r.Packed == nil {
+		if err := encoder.Encode(eos.Varuint32(0)); err != nil {
+			return err
+		}
+		return encoder.Encode(r.ID)
+	}
+
+	if err := encoder.Encode(eos.Varuint32(1)); err != nil {
+		return err
+	}
+	return encoder.Encode(r.Packed)
+}
+
+func (p *prunablePackedTransaction) UnmarshalBinary(decoder *eos.Decoder) (err error) {
+	compression, err := decoder.ReadByte()
+	if err != nil {
+		return fmt.Errorf("compression: %w", err)
+	}
+	p.Compression = eos.CompressionType(compression)
+
+	if p.PrunableType, err = decoder.ReadUvarint32(); err != nil {
+		return fmt.Errorf("prunable data type: %w", err)
+	}
+
+	if p.PrunableType != prunableNone {
+		if err := decoder.Decode(&p.Signatures); err != nil {
+			return fmt.Errorf("signatures: %w", err)
+		}
+	}
+
+	switch p.PrunableType {
+	case prunableFullLegacy:
+		if p.PackedContextFreeData, err = decoder.ReadByteArray(); err != nil {
+			return fmt.Errorf("packed context free data: %w", err)
+		}
+	case prunableNone, prunableSignaturesOnly:
+		if p.Digest, err = decoder.ReadChecksum256(); err != nil {
+			return fmt.Errorf("prunable data digest: %w", err)
+		}
+	case prunablePartial, prunableFull:
+		count, err := decoder.ReadUvarint32()
+		if err != nil {
+			return fmt.Errorf("context free segments count: %w", err)
+		}
+
+		for i := uint32(0); i < count; i++ {
+			segmentType := uint32(1)
+			if p.PrunableType == prunablePartial {
+				if segmentType, err = decoder.ReadUvarint32(); err != nil {
+					return fmt.Errorf("context free segment type: %w", err)
+				}
+			}
+
+			segment := &contextFreeSegment{}
+			switch segmentType {
+			case 0:
+				segment.Digest, err = decoder.ReadChecksum256()
+			case 1:
+				segment.Data, err = decoder.ReadByteArray()
+			default:
+				return fmt.Errorf("invalid context free segment type %d", segmentType)
+			}
+			if err != nil {
+				return fmt.Errorf("context free segment: %w", err)
+			}
+
+			p.ContextFreeSegments = append(p.ContextFreeSegments, segment)
+		}
+	default:
+		return fmt.Errorf("invalid prunable data type %d", p.PrunableType)
+	}
+
+	if p.PackedTransaction, err = decoder.ReadByteArray(); err != nil {
+		return fmt.Errorf("packed transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (p *prunablePackedTransaction) MarshalBinary(encoder *eos.Encoder) error {
+	if err := encoder.Encode(p.Compression); err != nil {
+		return err
+	}
+	if err := encoder.Encode(eos.Varuint32(p.PrunableType)); err != nil {
+		return err
+	}
+
+	if p.PrunableType != prunableNone {
+		if err := encoder.Encode(p.Signatures); err != nil {
+			return err
+		}
+	}
+
+	switch p.PrunableType {
+	case prunableFullLegacy:
+		if err := encoder.Encode(p.PackedContextFreeData); err != nil {
+			return err
+		}
+	case prunableNone, prunableSignaturesOnly:
+		if err := encoder.Encode(p.Digest); err != nil {
+			return err
+		}
+	case prunablePartial, prunableFull:
+		if err := encoder.Encode(eos.Varuint32(len(p.ContextFreeSegments))); err != nil {
+			return err
+		}
+
+		for _, segment := range p.ContextFreeSegments {
+			pruned := segment.Data == nil
+			if p.PrunableType == prunablePartial {
+				segmentType := eos.Varuint32(1)
+				if pruned {
+					segmentType = 0
+				}
+				if err := encoder.Encode(segmentType); err != nil {
+					return err
+				}
+			} else if pruned {
+				return fmt.Errorf("full prunable data cannot hold a pruned context free segment")
+			}
+
+			var err error
+			if pruned {
+				err = encoder.Encode(segment.Digest)
+			} else {
+				err = encoder.Encode(segment.Data)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("invalid prunable data type %d", p.PrunableType)
+	}
+
+	return encoder.Encode(p.PackedTransaction)
+}
+
+// newPrunableBlock converts a block to the version 4 format, keeping
+// the signatures and context-free data of its transactions as they are
+// (`full_legacy`).
+func newPrunableBlock(block *eos.SignedBlock) *prunableBlock {
+	out := &prunableBlock{
+		SignedBlockHeader: block.SignedBlockHeader,
+		PruneState:        pruneStateCompleteLegacy,
+		BlockExtensions:   block.BlockExtensions,
+	}
+
+	for _, receipt := range block.Transactions {
+		converted := &prunableTransactionReceipt{TransactionReceiptHeader: receipt.TransactionReceiptHeader, ID: receipt.Transaction.ID}
+		if packed := receipt.Transaction.Packed; packed != nil {
+			converted.Packed = &prunablePackedTransaction{
+				Compression:           packed.Compression,
+				PrunableType:          prunableFullLegacy,
+				Signatures:            packed.Signatures,
+				PackedContextFreeData: packed.PackedContextFreeData,
+				PackedTransaction:     packed.PackedTransaction,
+			}
+		}
+
+		out.Transactions = append(out.Transactions, converted)
+	}
+
+	return out
+}
+
+// signedBlock converts the block to an `eos.SignedBlock`. The
+// context-free segments are compressed with `segmentCompression`.
+//
+// Pruned data cannot be converted: transactions whose context-free data
+// was pruned have none, and no signatures either when those were
+// pruned too (`none` prunable data).
+func (b *prunableBlock) signedBlock(segmentCompression uint8) (*eos.SignedBlock, error) {
+	out := &eos.SignedBlock{
+		SignedBlockHeader: b.SignedBlockHeader,
+		Transactions:      make([]eos.TransactionReceipt, 0, len(b.Transactions)),
+		BlockExtensions:   b.BlockExtensions,
+	}
+
+	for i, receipt := range b.Transactions {
+		converted := eos.TransactionReceipt{TransactionReceiptHeader: receipt.TransactionReceiptHeader}
+		if receipt.Packed == nil {
+			converted.Transaction.ID = receipt.ID
+		} else {
+			packed, err := receipt.Packed.legacy(segmentCompression)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %d: %w", i, err)
+			}
+
+			id, err := packed.ID()
+			if err != nil {
+				return nil, fmt.Errorf("transaction %d id: %w", i, err)
+			}
+			converted.Transaction = eos.TransactionWithID{ID: id, Packed: packed}
+		}
+
+		out.Transactions = append(out.Transactions, converted)
+	}
+
+	return out, nil
+}
+
+// legacy converts the transaction to the packed transaction of the
+// previous versions, packing back its context-free segments.
+func (p *prunablePackedTransaction) legacy(segmentCompression uint8) (*eos.PackedTransaction, error) {
+	out := &eos.PackedTransaction{
+		Signatures:        p.Signatures,
+		Compression:       p.Compression,
+		PackedTransaction: p.PackedTransaction,
+	}
+
+	switch p.PrunableType {
+	case prunableFullLegacy:
+		out.PackedContextFreeData = p.PackedContextFreeData
+	case prunableFull:
+		if len(p.ContextFreeSegments) == 0 {
+			break
+		}
+
+		segments := make([][]byte, 0, len(p.ContextFreeSegments))
+		for _, segment := range p.ContextFreeSegments {
+			data := segment.Data
+			if segmentCompression == segmentCompressionZlib {
+				var err error
+				if data, err = inflate(data); err != nil {
+					return nil, fmt.Errorf("context free segment: %w", err)
+				}
+			}
+			segments = append(segments, data)
+		}
+
+		packed, err := eos.MarshalBinary(segments)
+		if err != nil {
+			return nil, fmt.Errorf("pack context free data: %w", err)
+		}
+
+		if p.Compression == eos.CompressionZlib {
+			if packed, err = deflate(packed); err != nil {
+				return nil, fmt.Errorf("compress context free data: %w", err)
+			}
+		}
+		out.PackedContextFreeData = packed
+	}
+
+	return out, nil
+}
+
+func inflate(data []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return ioutil.ReadAll(reader)
+}
+
+func deflate(data []byte) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	writer := zlib.NewWriter(buffer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,415 @@
+package blockslog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eoscanada/eos-go"
+	"go.uber.org/zap"
+)
+
+// Reader gives sequential and random access to the blocks of a blocks
+// log. Random access uses the `blocks.index` file next to the log when
+// it is present and consistent with the log, otherwise an index is
+// built in memory by walking the log backwards.
+//
+// Only the blocks still held by pruned logs can be read, from
+// `FirstBlockNum`.
+type Reader struct {
+	Header *Header
+
+	logPath       string
+	log           *os.File
+	logSize       int64
+	entriesOffset int64
+	// entriesEnd is the end of the last entry, followed by the number
+	// of blocks held for pruned logs.
+	entriesEnd    int64
+	retainedCount uint32
+
+	index     *os.File
+	positions []uint64
+
+	firstBlockNum uint32
+	blockCount    uint32
+	nextBlockNum  uint32
+}
+
+// NewReader opens a blocks log for reading, along with its index.
+func NewReader(logPath string) (out *Reader, err error) {
+	return newReader(logPath, true)
+}
+
+// newReader opens a blocks log, ignoring its index unless `useIndex`.
+func newReader(logPath string, useIndex bool) (out *Reader, err error) {
+	r := &Reader{logPath: logPath}
+
+	r.log, err = os.Open(logPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			r.Close()
+		}
+	}()
+
+	stat, err := r.log.Stat()
+	if err != nil {
+		return nil, err
+	}
+	r.logSize = stat.Size()
+
+	r.Header, r.entriesOffset, err = readHeader(r.log)
+	if err != nil {
+		return nil, fmt.Errorf("read header of %s: %w", logPath, err)
+	}
+
+	r.entriesEnd = r.logSize
+	if r.Header.Pruned {
+		if r.logSize < r.entriesOffset+4 {
+			return nil, fmt.Errorf("corrupted blocks log, pruned log %s has no block count", logPath)
+		}
+
+		if r.retainedCount, err = readUint32At(r.log, r.logSize-4); err != nil {
+			return nil, err
+		}
+		r.entriesEnd -= 4
+	}
+
+	r.firstBlockNum = r.Header.FirstBlockNum
+	if err := r.loadIndex(IndexPath(logPath), useIndex); err != nil {
+		return nil, err
+	}
+	r.nextBlockNum = r.firstBlockNum
+
+	return r, nil
+}
+
+func (r *Reader) Close() error {
+	if r.index != nil {
+		r.index.Close()
+	}
+	return r.log.Close()
+}
+
+// FirstBlockNum is the number of the first block of the log, the
+// first one not pruned for pruned logs.
+func (r *Reader) FirstBlockNum() uint32 {
+	return r.firstBlockNum
+}
+
+// LastBlockNum is the number of the last block of the log, which is
+// `FirstBlockNum() - 1` when the log is empty.
+func (r *Reader) LastBlockNum() uint32 {
+	return r.firstBlockNum + r.blockCount - 1
+}
+
+// BlockCount is the number of blocks in the log.
+func (r *Reader) BlockCount() uint32 {
+	return r.blockCount
+}
+
+// Next reads the next block sequentially, starting at the first block
+// of the log (see `Seek`). It returns `io.EOF` past the last block.
+func (r *Reader) Next() (*eos.SignedBlock, error) {
+	if r.nextBlockNum > r.LastBlockNum() {
+		return nil, io.EOF
+	}
+
+	block, err := r.ReadBlock(r.nextBlockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	r.nextBlockNum++
+	return block, nil
+}
+
+// Seek positions the reader so that the next call to `Next` returns
+// block `blockNum`.
+func (r *Reader) Seek(blockNum uint32) error {
+	if blockNum < r.FirstBlockNum() || blockNum > r.LastBlockNum()+1 {
+		return fmt.Errorf("%w: block %d, log contains blocks %d to %d", ErrBlockNotFound, blockNum, r.FirstBlockNum(), r.LastBlockNum())
+	}
+
+	r.nextBlockNum = blockNum
+	return nil
+}
+
+// ReadBlock reads and decodes block `blockNum`.
+func (r *Reader) ReadBlock(blockNum uint32) (*eos.SignedBlock, error) {
+	data, segmentCompression, err := r.readEntry(blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := decodeBlock(r.Header.Version, data, segmentCompression)
+	if err != nil {
+		return nil, fmt.Errorf("decode block %d: %w", blockNum, err)
+	}
+
+	if block.BlockNumber() != blockNum {
+		return nil, fmt.Errorf("corrupted blocks log, expected block %d at its position but found block %d", blockNum, block.BlockNumber())
+	}
+
+	return block, nil
+}
+
+// ReadBlockBytes reads block `blockNum` as packed in the log, in its
+// prunable format for version 4 logs.
+func (r *Reader) ReadBlockBytes(blockNum uint32) ([]byte, error) {
+	data, _, err := r.readEntry(blockNum)
+	return data, err
+}
+
+// readEntry reads the packed block of the entry of block `blockNum`,
+// along with the compression of its context-free segments for version
+// 4 logs.
+func (r *Reader) readEntry(blockNum uint32) (data []byte, segmentCompression uint8, err error) {
+	start, end, err := r.entryBounds(blockNum)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entry := make([]byte, end-start)
+	if _, err := r.log.ReadAt(entry, start); err != nil {
+		return nil, 0, fmt.Errorf("read block %d: %w", blockNum, err)
+	}
+
+	if r.Header.Version < prunableBlockVersion {
+		return entry, segmentCompressionNone, nil
+	}
+
+	// The size covers the whole entry, up to its trailing position.
+	if len(entry) < 5 || int64(binary.LittleEndian.Uint32(entry)) != end-start+8 {
+		return nil, 0, fmt.Errorf("corrupted blocks log, invalid size of the entry of block %d", blockNum)
+	}
+
+	return entry[5:], entry[4], nil
+}
+
+// decodeBlock decodes a block packed in a log of version `version`.
+func decodeBlock(version uint32, data []byte, segmentCompression uint8) (*eos.SignedBlock, error) {
+	if version < prunableBlockVersion {
+		block := &eos.SignedBlock{}
+		if err := eos.UnmarshalBinary(data, block); err != nil {
+			return nil, err
+		}
+		return block, nil
+	}
+
+	block := &prunableBlock{}
+	if err := eos.UnmarshalBinary(data, block); err != nil {
+		return nil, err
+	}
+	return block.signedBlock(segmentCompression)
+}
+
+// entryBounds returns the position of the entry in the file, excluding
+// its trailing position.
+func (r *Reader) entryBounds(blockNum uint32) (start int64, end int64, err error) {
+	if blockNum < r.FirstBlockNum() || blockNum > r.LastBlockNum() {
+		return 0, 0, fmt.Errorf("%w: block %d, log contains blocks %d to %d", ErrBlockNotFound, blockNum, r.FirstBlockNum(), r.LastBlockNum())
+	}
+
+	position, err := r.position(blockNum)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	next := uint64(r.entriesEnd)
+	if blockNum < r.LastBlockNum() {
+		if next, err = r.position(blockNum + 1); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if next < position+8 {
+		return 0, 0, fmt.Errorf("corrupted blocks log, invalid positions %d and %d for block %d", position, next, blockNum)
+	}
+
+	return int64(position), int64(next) - 8, nil
+}
+
+// position returns the position in the log of the entry of block
+// `blockNum`, which must be within the log.
+func (r *Reader) position(blockNum uint32) (uint64, error) {
+	if r.index == nil {
+		return r.positions[blockNum-r.firstBlockNum], nil
+	}
+
+	// The index starts at the first block the log ever held.
+	offset := blockNum - r.Header.FirstBlockNum
+	buf := make([]byte, 8)
+	if _, err := r.index.ReadAt(buf, int64(offset)*8); err != nil {
+		return 0, fmt.Errorf("read index of block %d: %w", blockNum, err)
+	}
+
+	return binary.LittleEndian.Uint64(buf), nil
+}
+
+func (r *Reader) loadIndex(indexPath string, useIndex bool) error {
+	if useIndex && fileExists(indexPath) {
+		index, err := os.Open(indexPath)
+		if err != nil {
+			return err
+		}
+
+		count, err := r.validateIndex(index)
+		if err == nil {
+			r.index = index
+			r.blockCount = count
+			if r.Header.Pruned {
+				r.blockCount = r.retainedCount
+				r.firstBlockNum = r.Header.FirstBlockNum + count - r.retainedCount
+			}
+			return nil
+		}
+		index.Close()
+
+		zlog.Info("blocks index is not usable, building it in memory", zap.String("index", indexPath), zap.Error(err))
+	}
+
+	limit := -1
+	if r.Header.Pruned {
+		limit = int(r.retainedCount)
+	}
+
+	positions, err := scanPositions(r.log, r.entriesEnd, r.entriesOffset, limit)
+	if err != nil {
+		return err
+	}
+
+	r.positions = positions
+	r.blockCount = uint32(len(positions))
+
+	// Without index, the first block held by a pruned log is only known
+	// from the number of the last one.
+	if r.Header.Pruned && len(positions) > 0 {
+		lastBlockNum, err := r.readBlockNum(positions[len(positions)-1])
+		if err != nil {
+			return err
+		}
+		r.firstBlockNum = lastBlockNum - r.blockCount + 1
+	}
+
+	return nil
+}
+
+// readBlockNum reads the number of the block of the entry at
+// `position`, from the ID of its previous block.
+func (r *Reader) readBlockNum(position uint64) (uint32, error) {
+	// timestamp (4) + producer (8) + confirmed (2)
+	offset := int64(position) + 14
+	if r.Header.Version >= prunableBlockVersion {
+		offset += 5
+	}
+
+	buf := make([]byte, 4)
+	if _, err := r.log.ReadAt(buf, offset); err != nil {
+		return 0, fmt.Errorf("read block number at %d: %w", position, err)
+	}
+
+	return binary.BigEndian.Uint32(buf) + 1, nil
+}
+
+// validateIndex checks that an index matches the log, returning the
+// number of blocks it references.
+func (r *Reader) validateIndex(index *os.File) (uint32, error) {
+	stat, err := index.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	if stat.Size()%8 != 0 {
+		return 0, fmt.Errorf("index size %d is not a multiple of 8", stat.Size())
+	}
+
+	count := stat.Size() / 8
+	if count < int64(r.retainedCount) {
+		return 0, fmt.Errorf("index references %d blocks but the pruned log holds %d", count, r.retainedCount)
+	}
+
+	if count == 0 {
+		if r.entriesEnd != r.entriesOffset {
+			return 0, fmt.Errorf("index is empty but the log is not")
+		}
+		return 0, nil
+	}
+
+	// The entries of pruned logs holding no block anymore are zeroed.
+	if r.Header.Pruned && r.retainedCount == 0 {
+		return uint32(count), nil
+	}
+
+	lastInLog, err := readUint64At(r.log, r.entriesEnd-8)
+	if err != nil {
+		return 0, err
+	}
+
+	lastInIndex, err := readUint64At(index, stat.Size()-8)
+	if err != nil {
+		return 0, err
+	}
+
+	if lastInLog != lastInIndex {
+		return 0, fmt.Errorf("last block position %d in index does not match %d in log", lastInIndex, lastInLog)
+	}
+
+	return uint32(count), nil
+}
+
+// scanPositions finds the position of every entry, walking the log
+// from the end of its entries since each entry finishes with its own
+// position. It stops after `limit` entries when not negative.
+func scanPositions(log io.ReaderAt, entriesEnd int64, entriesOffset int64, limit int) ([]uint64, error) {
+	var reversed []uint64
+
+	end := entriesEnd
+	for end > entriesOffset && (limit < 0 || len(reversed) < limit) {
+		if end-8 < entriesOffset {
+			return nil, fmt.Errorf("corrupted blocks log, truncated entry ending at %d", end)
+		}
+
+		position, err := readUint64At(log, end-8)
+		if err != nil {
+			return nil, err
+		}
+
+		if int64(position) < entriesOffset || int64(position) >= end-8 {
+			return nil, fmt.Errorf("corrupted blocks log, invalid entry position %d before %d", position, end)
+		}
+
+		reversed = append(reversed, position)
+		end = int64(position)
+	}
+
+	positions := make([]uint64, len(reversed))
+	for i, position := range reversed {
+		positions[len(reversed)-1-i] = position
+	}
+
+	return positions, nil
+}
+
+func readUint32At(r io.ReaderAt, offset int64) (uint32, error) {
+	buf := make([]byte, 4)
+	if _, err := r.ReadAt(buf, offset); err != nil {
+		return 0, fmt.Errorf("read at %d: %w", offset, err)
+	}
+
+	return binary.LittleEndian.Uint32(buf), nil
+}
+
+func readUint64At(r io.ReaderAt, offset int64) (uint64, error) {
+	buf := make([]byte, 8)
+	if _, err := r.ReadAt(buf, offset); err != nil {
+		return 0, fmt.Errorf("read at %d: %w", offset, err)
+	}
+
+	return binary.LittleEndian.Uint64(buf), nil
+}
@@ -0,0 +1,374 @@
+package blockslog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eoscanada/eos-go"
+)
+
+// Writer appends blocks to a blocks log, keeping its index up to date.
+type Writer struct {
+	Header *Header
+
+	log   *os.File
+	index *os.File
+	// entriesEnd is the end of the last entry, followed by the number
+	// of blocks held for pruned logs.
+	entriesEnd    int64
+	retainedCount uint32
+	nextBlockNum  uint32
+}
+
+// NewWriter creates a new blocks log, and its index, with the given
+// header. It fails if the log already exists. A zero `Version` is
+// replaced by `DefaultVersion` and a zero `FirstBlockNum` by 1.
+func NewWriter(logPath string, header *Header) (out *Writer, err error) {
+	h := *header
+	if h.Version == 0 {
+		h.Version = DefaultVersion
+	}
+	if h.FirstBlockNum == 0 {
+		h.FirstBlockNum = 1
+	}
+
+	data, err := encodeHeader(&h)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.Genesis != nil {
+		if h.ChainID, err = h.Genesis.ChainID(); err != nil {
+			return nil, err
+		}
+	}
+
+	w := &Writer{Header: &h, nextBlockNum: h.FirstBlockNum}
+	w.log, err = os.OpenFile(logPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			w.Close()
+		}
+	}()
+
+	w.index, err = os.OpenFile(IndexPath(logPath), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	w.entriesEnd = int64(len(data))
+	if h.Pruned {
+		data = append(data, encodeUint32(0)...)
+	}
+
+	if _, err := w.log.Write(data); err != nil {
+		return nil, fmt.Errorf("write header: %w", err)
+	}
+
+	return w, nil
+}
+
+// OpenWriter opens an existing blocks log to append blocks to it. The
+// index is rebuilt first if it is missing or does not match the log.
+func OpenWriter(logPath string) (out *Writer, err error) {
+	reader, err := NewReader(logPath)
+	if err != nil {
+		return nil, err
+	}
+	header, lastBlockNum, indexValid := reader.Header, reader.LastBlockNum(), reader.index != nil
+	entriesEnd, retainedCount := reader.entriesEnd, reader.retainedCount
+	reader.Close()
+
+	if !indexValid {
+		if err := RebuildIndex(logPath); err != nil {
+			return nil, err
+		}
+	}
+
+	w := &Writer{Header: header, entriesEnd: entriesEnd, retainedCount: retainedCount, nextBlockNum: lastBlockNum + 1}
+	w.log, err = os.OpenFile(logPath, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			w.Close()
+		}
+	}()
+
+	w.index, err = os.OpenFile(IndexPath(logPath), os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = w.index.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// NextBlockNum is the number of the block expected by the next call
+// to `AppendBlock`.
+func (w *Writer) NextBlockNum() uint32 {
+	return w.nextBlockNum
+}
+
+// AppendBlock packs and appends a block, which must be the one
+// following the last block of the log.
+func (w *Writer) AppendBlock(block *eos.SignedBlock) error {
+	if block.BlockNumber() != w.nextBlockNum {
+		return fmt.Errorf("cannot append block %d, expected block %d", block.BlockNumber(), w.nextBlockNum)
+	}
+
+	var packed interface{} = block
+	if w.Header.Version >= prunableBlockVersion {
+		packed = newPrunableBlock(block)
+	}
+
+	data, err := eos.MarshalBinary(packed)
+	if err != nil {
+		return fmt.Errorf("pack block %d: %w", w.nextBlockNum, err)
+	}
+
+	return w.appendEntry(data, segmentCompressionNone)
+}
+
+// AppendBlockBytes appends an already packed block, as returned by
+// `Reader.ReadBlockBytes`, so in its prunable format for version 4 logs.
+// The caller is responsible for appending blocks in order.
+func (w *Writer) AppendBlockBytes(data []byte) error {
+	return w.appendEntry(data, segmentCompressionNone)
+}
+
+// appendEntry appends the entry of a packed block, starting with its
+// size and the compression of its context-free segments for version 4
+// logs, and rewrites the block count of pruned logs after it.
+func (w *Writer) appendEntry(data []byte, segmentCompression uint8) error {
+	entry := make([]byte, 0, 5+len(data)+8+4)
+	if w.Header.Version >= prunableBlockVersion {
+		entry = append(entry, encodeUint32(uint32(5+len(data)+8))...)
+		entry = append(entry, segmentCompression)
+	}
+	entry = append(entry, data...)
+
+	position := make([]byte, 8)
+	binary.LittleEndian.PutUint64(position, uint64(w.entriesEnd))
+	entry = append(entry, position...)
+
+	entriesEnd := w.entriesEnd + int64(len(entry))
+	if w.Header.Pruned {
+		entry = append(entry, encodeUint32(w.retainedCount+1)...)
+	}
+
+	if _, err := w.log.WriteAt(entry, w.entriesEnd); err != nil {
+		return fmt.Errorf("write block %d: %w", w.nextBlockNum, err)
+	}
+
+	if _, err := w.index.Write(position); err != nil {
+		return fmt.Errorf("write index of block %d: %w", w.nextBlockNum, err)
+	}
+
+	w.entriesEnd = entriesEnd
+	w.retainedCount++
+	w.nextBlockNum++
+	return nil
+}
+
+func encodeUint32(value uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, value)
+	return buf
+}
+
+func (w *Writer) Close() error {
+	var err error
+	if w.index != nil {
+		err = w.index.Close()
+	}
+
+	if w.log != nil {
+		if logErr := w.log.Close(); logErr != nil {
+			err = logErr
+		}
+	}
+
+	return err
+}
+
+// RebuildIndex writes the `blocks.index` of a blocks log from scratch.
+// The positions of the blocks pruned from pruned logs are zeroed.
+func RebuildIndex(logPath string) error {
+	reader, err := newReader(logPath, false)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	positions := make([]uint64, reader.FirstBlockNum()-reader.Header.FirstBlockNum, int(reader.LastBlockNum()+1-reader.Header.FirstBlockNum))
+	positions = append(positions, reader.positions...)
+
+	return writeIndex(IndexPath(logPath), positions)
+}
+
+func writeIndex(indexPath string, positions []uint64) error {
+	index, err := os.Create(indexPath)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(index)
+	for _, position := range positions {
+		if err := binary.Write(writer, binary.LittleEndian, position); err != nil {
+			index.Close()
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		index.Close()
+		return err
+	}
+
+	return index.Close()
+}
+
+// Truncate removes, in place, all the blocks after `lastBlockNum` from
+// a blocks log and its index. The block count of pruned logs is updated.
+func Truncate(logPath string, lastBlockNum uint32) error {
+	reader, err := NewReader(logPath)
+	if err != nil {
+		return err
+	}
+
+	if lastBlockNum+1 < reader.FirstBlockNum() || lastBlockNum > reader.LastBlockNum() {
+		reader.Close()
+		return fmt.Errorf("%w: cannot truncate after block %d, log contains blocks %d to %d", ErrBlockNotFound, lastBlockNum, reader.FirstBlockNum(), reader.LastBlockNum())
+	}
+
+	size := reader.entriesEnd
+	if lastBlockNum < reader.LastBlockNum() {
+		position, err := reader.position(lastBlockNum + 1)
+		if err != nil {
+			reader.Close()
+			return err
+		}
+		size = int64(position)
+	}
+
+	// The index starts at the first block the log ever held.
+	indexCount := lastBlockNum + 1 - reader.Header.FirstBlockNum
+	retainedCount, pruned := lastBlockNum+1-reader.FirstBlockNum(), reader.Header.Pruned
+	reader.Close()
+
+	if err := os.Truncate(logPath, size); err != nil {
+		return err
+	}
+
+	if pruned {
+		if err := writeRetainedCount(logPath, size, retainedCount); err != nil {
+			return err
+		}
+	}
+
+	indexPath := IndexPath(logPath)
+	if fileExists(indexPath) {
+		if err := os.Truncate(indexPath, int64(indexCount)*8); err != nil {
+			return err
+		}
+	}
+
+	return RebuildIndexIfInvalid(logPath)
+}
+
+func writeRetainedCount(logPath string, offset int64, count uint32) error {
+	log, err := os.OpenFile(logPath, os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := log.WriteAt(encodeUint32(count), offset); err != nil {
+		log.Close()
+		return fmt.Errorf("write block count: %w", err)
+	}
+
+	return log.Close()
+}
+
+// RebuildIndexIfInvalid rebuilds the index of a blocks log only if it
+// is missing or does not match the log.
+func RebuildIndexIfInvalid(logPath string) error {
+	reader, err := NewReader(logPath)
+	if err != nil {
+		return err
+	}
+	valid := reader.index != nil
+	reader.Close()
+
+	if valid {
+		return nil
+	}
+	return RebuildIndex(logPath)
+}
+
+// Trim writes blocks `firstBlockNum` to `lastBlockNum` (inclusive) of
+// the log at `srcLogPath` to a new log at `dstLogPath`, along with its
+// index. The new log uses version 4 for a version 4 source log, and
+// `DefaultVersion` otherwise, storing the chain ID instead of the
+// genesis state unless it starts at block 1. It is never pruned.
+func Trim(srcLogPath string, dstLogPath string, firstBlockNum uint32, lastBlockNum uint32) error {
+	reader, err := NewReader(srcLogPath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if firstBlockNum > lastBlockNum || firstBlockNum < reader.FirstBlockNum() || lastBlockNum > reader.LastBlockNum() {
+		return fmt.Errorf("%w: cannot trim to blocks %d to %d, log contains blocks %d to %d", ErrBlockNotFound, firstBlockNum, lastBlockNum, reader.FirstBlockNum(), reader.LastBlockNum())
+	}
+
+	version := DefaultVersion
+	if reader.Header.Version >= prunableBlockVersion {
+		version = reader.Header.Version
+	}
+
+	header := &Header{
+		Version:       version,
+		FirstBlockNum: firstBlockNum,
+		ChainID:       reader.Header.ChainID,
+	}
+	if containsGenesisState(header.Version, firstBlockNum) {
+		if reader.Header.Genesis == nil {
+			return fmt.Errorf("source log has no genesis state, required for a log starting at block %d", firstBlockNum)
+		}
+		header.Genesis = reader.Header.Genesis
+	}
+
+	writer, err := NewWriter(dstLogPath, header)
+	if err != nil {
+		return err
+	}
+
+	for blockNum := firstBlockNum; blockNum <= lastBlockNum; blockNum++ {
+		data, segmentCompression, err := reader.readEntry(blockNum)
+		if err != nil {
+			writer.Close()
+			return err
+		}
+
+		if err := writer.appendEntry(data, segmentCompression); err != nil {
+			writer.Close()
+			return err
+		}
+	}
+
+	return writer.Close()
+}
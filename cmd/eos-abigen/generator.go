@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/eoscanada/eos-go"
+)
+
+// builtinTypes maps the built-in ABI types to their Go type.
+var builtinTypes = map[string]string{
+	"bool":                 "bool",
+	"int8":                 "int8",
+	"uint8":                "uint8",
+	"int16":                "int16",
+	"uint16":               "uint16",
+	"int32":                "int32",
+	"uint32":               "uint32",
+	"int64":                "eos.Int64",
+	"uint64":               "eos.Uint64",
+	"int128":               "eos.Int128",
+	"uint128":              "eos.Uint128",
+	"varint32":             "eos.Varint32",
+	"varuint32":            "eos.Varuint32",
+	"float32":              "float32",
+	"float64":              "eos.Float64",
+	"float128":             "eos.Float128",
+	"time_point":           "eos.TimePoint",
+	"time_point_sec":       "eos.TimePointSec",
+	"block_timestamp_type": "eos.BlockTimestamp",
+	"name":                 "eos.Name",
+	"bytes":                "eos.HexBytes",
+	"string":               "string",
+	"checksum160":          "eos.Checksum160",
+	"checksum256":          "eos.Checksum256",
+	"checksum512":          "eos.Checksum512",
+	"public_key":           "ecc.PublicKey",
+	"signature":            "ecc.Signature",
+	"symbol":               "eos.Symbol",
+	"symbol_code":          "eos.SymbolCode",
+	"asset":                "eos.Asset",
+	"extended_asset":       "eos.ExtendedAsset",
+}
+
+type generator struct {
+	abi         *eos.ABI
+	packageName string
+	contract    eos.AccountName
+
+	buf     bytes.Buffer
+	imports map[string]bool
+	names   map[string]bool
+}
+
+// generate returns the Go source of a package named `packageName` for
+// the contract described by `abi`, deployed on `contract`.
+func generate(abi *eos.ABI, packageName string, contract eos.AccountName) ([]byte, error) {
+	g := &generator{
+		abi:         abi,
+		packageName: packageName,
+		contract:    contract,
+		imports:     map[string]bool{"github.com/eoscanada/eos-go": true},
+		names:       map[string]bool{},
+	}
+
+	for _, typeDef := range abi.Types {
+		g.names[typeDef.NewTypeName] = true
+	}
+	for _, structDef := range abi.Structs {
+		g.names[structDef.Name] = true
+	}
+	for _, variantDef := range abi.Variants {
+		g.names[variantDef.Name] = true
+	}
+
+	body, err := g.generateBody()
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by eos-abigen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", packageName)
+
+	var imports []string
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+
+	fmt.Fprintf(&out, "import (\n")
+	stdlib := true
+	for _, imp := range imports {
+		if stdlib && strings.Contains(imp, ".") {
+			if imp != imports[0] {
+				fmt.Fprintf(&out, "\n")
+			}
+			stdlib = false
+		}
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	fmt.Fprintf(&out, ")\n\n")
+	out.Write(body)
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, out.String())
+	}
+	return formatted, nil
+}
+
+func (g *generator) generateBody() ([]byte, error) {
+	g.printf("// Contract is the account the contract is deployed on.\n")
+	g.printf("var Contract = eos.AN(%q)\n\n", g.contract)
+
+	for _, typeDef := range g.abi.Types {
+		goType, err := g.goType(typeDef.Type)
+		if err != nil {
+			return nil, fmt.Errorf("type %q: %w", typeDef.NewTypeName, err)
+		}
+		g.printf("type %s = %s\n\n", goName(typeDef.NewTypeName), goType)
+	}
+
+	for _, structDef := range g.abi.Structs {
+		if err := g.generateStruct(structDef); err != nil {
+			return nil, fmt.Errorf("struct %q: %w", structDef.Name, err)
+		}
+	}
+
+	for _, variantDef := range g.abi.Variants {
+		if err := g.generateVariant(variantDef); err != nil {
+			return nil, fmt.Errorf("variant %q: %w", variantDef.Name, err)
+		}
+	}
+
+	for _, actionDef := range g.abi.Actions {
+		if err := g.generateAction(actionDef); err != nil {
+			return nil, fmt.Errorf("action %q: %w", actionDef.Name, err)
+		}
+	}
+
+	g.generateRegistration()
+
+	for _, tableDef := range g.abi.Tables {
+		if err := g.generateTable(tableDef); err != nil {
+			return nil, fmt.Errorf("table %q: %w", tableDef.Name, err)
+		}
+	}
+
+	return g.buf.Bytes(), nil
+}
+
+func (g *generator) generateStruct(structDef eos.StructDef) error {
+	g.printf("// %s represents the `%s` struct of the contract.\n", goName(structDef.Name), structDef.Name)
+	g.printf("type %s struct {\n", goName(structDef.Name))
+
+	if structDef.Base != "" {
+		if !g.names[structDef.Base] {
+			return fmt.Errorf("unknown base %q", structDef.Base)
+		}
+		g.printf("\t%s\n", goName(structDef.Base))
+	}
+
+	seenBinaryExtension := false
+	for _, field := range structDef.Fields {
+		fieldType := field.Type
+		tag := ""
+
+		switch {
+		case strings.HasSuffix(fieldType, "$"):
+			fieldType = strings.TrimSuffix(fieldType, "$")
+			tag = ` eos:"binary_extension"`
+			seenBinaryExtension = true
+		case seenBinaryExtension:
+			return fmt.Errorf("field %q follows a binary extension", field.Name)
+		}
+
+		optional := strings.HasSuffix(fieldType, "?")
+		if optional {
+			fieldType = strings.TrimSuffix(fieldType, "?")
+			if tag != "" {
+				return fmt.Errorf("field %q cannot be both optional and a binary extension", field.Name)
+			}
+			tag = ` eos:"optional"`
+		}
+
+		goType, err := g.goType(fieldType)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", field.Name, err)
+		}
+		if optional {
+			goType = "*" + goType
+		}
+
+		g.printf("\t%s %s `json:\"%s\"%s`\n", goName(field.Name), goType, field.Name, tag)
+	}
+
+	g.printf("}\n\n")
+	return nil
+}
+
+func (g *generator) generateVariant(variantDef eos.VariantDef) error {
+	name := goName(variantDef.Name)
+
+	g.printf("var %sVariant = eos.NewVariantDefinition([]eos.VariantType{\n", name)
+	for _, typeName := range variantDef.Types {
+		goType, err := g.goType(typeName)
+		if err != nil {
+			return err
+		}
+		g.printf("\t{Name: %q, Type: (*%s)(nil)},\n", typeName, goType)
+	}
+	g.printf("})\n\n")
+
+	g.printf("// %s represents the `%s` variant of the contract.\n", name, variantDef.Name)
+	g.printf("type %s struct {\n\teos.BaseVariant\n}\n\n", name)
+	// Variants are used by value in the generated structs, a value
+	// receiver keeps them marshalled as variants when not addressable.
+	g.printf("func (a %s) MarshalJSON() ([]byte, error) {\n\treturn a.BaseVariant.MarshalJSON(%sVariant)\n}\n\n", name, name)
+	g.printf("func (a *%s) UnmarshalJSON(data []byte) error {\n\treturn a.BaseVariant.UnmarshalJSON(data, %sVariant)\n}\n\n", name, name)
+	g.printf("func (a *%s) UnmarshalBinary(decoder *eos.Decoder) error {\n\treturn a.BaseVariant.UnmarshalBinaryVariant(decoder, %sVariant)\n}\n\n", name, name)
+	return nil
+}
+
+func (g *generator) generateAction(actionDef eos.ActionDef) error {
+	if g.abi.StructForName(actionDef.Type) == nil {
+		return fmt.Errorf("unknown struct %q", actionDef.Type)
+	}
+
+	g.printf("// New%s returns the `%s` action of the contract.\n", goName(string(actionDef.Name)), actionDef.Name)
+	g.printf("func New%s(authorization []eos.PermissionLevel, data %s) *eos.Action {\n", goName(string(actionDef.Name)), goName(actionDef.Type))
+	g.printf("\treturn &eos.Action{\n")
+	g.printf("\t\tAccount: Contract,\n")
+	g.printf("\t\tName: eos.ActN(%q),\n", actionDef.Name)
+	g.printf("\t\tAuthorization: authorization,\n")
+	g.printf("\t\tActionData: eos.NewActionData(data),\n")
+	g.printf("\t}\n}\n\n")
+	return nil
+}
+
+func (g *generator) generateRegistration() {
+	g.printf("// RegisterActions registers the actions of the contract in `registry`.\n")
+	g.printf("func RegisterActions(registry *eos.Registry) {\n")
+	for _, actionDef := range g.abi.Actions {
+		g.printf("\tregistry.RegisterAction(Contract, eos.ActN(%q), %s{})\n", actionDef.Name, goName(actionDef.Type))
+	}
+	g.printf("}\n\n")
+
+	g.printf("func init() {\n\tRegisterActions(eos.DefaultRegistry)\n}\n\n")
+}
+
+func (g *generator) generateTable(tableDef eos.TableDef) error {
+	if !g.names[tableDef.Type] {
+		return fmt.Errorf("unknown row type %q", tableDef.Type)
+	}
+
+	g.imports["context"] = true
+	g.imports["fmt"] = true
+
+	name := goName(string(tableDef.Name))
+	rowType := goName(tableDef.Type)
+
+	g.printf("// Get%sRows returns the rows of the `%s` table matching `request`,\n", name, tableDef.Name)
+	g.printf("// and whether there are more rows.\n")
+	g.printf("func Get%sRows(ctx context.Context, api *eos.API, request eos.GetTableRowsRequest) (rows []*%s, more bool, err error) {\n", name, rowType)
+	g.printf("\trequest.Code = string(Contract)\n")
+	g.printf("\trequest.Table = %q\n", tableDef.Name)
+	g.printf("\trequest.JSON = false\n\n")
+	g.printf("\tresp, err := api.GetTableRows(ctx, request)\n")
+	g.printf("\tif err != nil {\n\t\treturn nil, false, err\n\t}\n\n")
+	g.printf("\tif err := resp.BinaryToStructs(&rows); err != nil {\n")
+	g.printf("\t\treturn nil, false, fmt.Errorf(\"decode %s rows: %%w\", err)\n\t}\n\n", tableDef.Name)
+	g.printf("\treturn rows, resp.More, nil\n}\n\n")
+	return nil
+}
+
+// goType returns the Go type of an ABI type, which can be an array.
+func (g *generator) goType(abiType string) (string, error) {
+	if strings.HasSuffix(abiType, "[]") {
+		elem, err := g.goType(strings.TrimSuffix(abiType, "[]"))
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	}
+
+	if strings.HasSuffix(abiType, "?") || strings.HasSuffix(abiType, "$") || strings.HasSuffix(abiType, "]") {
+		return "", fmt.Errorf("unsupported type %q", abiType)
+	}
+
+	if goType, found := builtinTypes[abiType]; found {
+		if strings.HasPrefix(goType, "ecc.") {
+			g.imports["github.com/eoscanada/eos-go/ecc"] = true
+		}
+		return goType, nil
+	}
+
+	if g.names[abiType] {
+		return goName(abiType), nil
+	}
+
+	return "", fmt.Errorf("unknown type %q", abiType)
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// goName turns an ABI name like `user_resources` into an exported Go
+// name like `UserResources`.
+func goName(name string) string {
+	var out strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '.' }) {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		out.WriteString(string(runes))
+	}
+
+	if out.Len() == 0 || unicode.IsDigit([]rune(out.String())[0]) {
+		return "X" + out.String()
+	}
+	return out.String()
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestGenerate(t *testing.T) {
+	abiFile, err := os.Open("testdata/example.abi")
+	require.NoError(t, err)
+	defer abiFile.Close()
+
+	abi, err := eos.NewABI(abiFile)
+	require.NoError(t, err)
+
+	code, err := generate(abi, "example", "example")
+	require.NoError(t, err)
+
+	if *updateGolden {
+		require.NoError(t, ioutil.WriteFile("testdata/example.go.golden", code, 0644))
+	}
+
+	expected, err := ioutil.ReadFile("testdata/example.go.golden")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(code))
+}
+
+// generatedProgram packs a populated `memo.xfer` action with the
+// generated package, printing its hex.
+const generatedProgram = `package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/ecc"
+)
+
+func main() {
+	key, err := ecc.NewPublicKey("EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV")
+	if err != nil {
+		panic(err)
+	}
+
+	note, referrer, nonce := "hello", AccountName("carol"), uint32(7)
+	action := NewMemoXfer([]eos.PermissionLevel{{Actor: "alice", Permission: "active"}}, MemoTransfer{
+		Transfer:  Transfer{From: "alice", To: "bob", Quantity: eos.Asset{Amount: 10000, Symbol: eos.EOSSymbol}, Memo: "memo"},
+		Tags:      []string{"a", "b"},
+		Key:       &key,
+		Payload:   Payload{eos.BaseVariant{TypeID: PayloadVariant.TypeID("text_payload"), Impl: &TextPayload{Text: "text"}}},
+		Note:      &note,
+		Referrer:  &referrer,
+		Nonce:     &nonce,
+		ExpiresAt: eos.TimePointSec(1700000000),
+	})
+
+	data, err := eos.MarshalBinary(action)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(hex.EncodeToString(data))
+}
+`
+
+// TestGenerate_Compiles builds a program with the generated package and
+// decodes the action it packs through the ABI.
+func TestGenerate_Compiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program with the go tool")
+	}
+
+	abiFile, err := os.Open("testdata/example.abi")
+	require.NoError(t, err)
+	defer abiFile.Close()
+
+	abi, err := eos.NewABI(abiFile)
+	require.NoError(t, err)
+
+	code, err := generate(abi, "main", "example")
+	require.NoError(t, err)
+
+	// Within the module, so the program builds with its dependencies.
+	dir, err := ioutil.TempDir("testdata", "generated")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "example.go"), code, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(generatedProgram), 0644))
+
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	require.NoError(t, err)
+
+	data, err := hex.DecodeString(string(out))
+	require.NoError(t, err)
+
+	registry := eos.NewRegistry()
+	registry.RegisterABI("example", abi)
+
+	decoder := eos.NewDecoder(data)
+	decoder.SetRegistry(registry)
+
+	var action *eos.Action
+	require.NoError(t, decoder.Decode(&action))
+	assert.Equal(t, eos.ActN("memo.xfer"), action.Name)
+
+	decoded, ok := action.Data.(json.RawMessage)
+	require.True(t, ok, "action data decoded to %T", action.Data)
+	assert.JSONEq(t, `{
+		"from": "alice",
+		"to": "bob",
+		"quantity": "1.0000 EOS",
+		"memo": "memo",
+		"tags": ["a", "b"],
+		"key": "EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV",
+		"payload": {"text": "text"},
+		"note": "hello",
+		"referrer": "carol",
+		"nonce": 7,
+		"expires_at": "2023-11-14T22:13:20"
+	}`, string(decoded))
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		abi           string
+		expectedError string
+	}{
+		{
+			name:          "unknown type",
+			abi:           `{"structs": [{"name": "a", "fields": [{"name": "f", "type": "unknown"}]}]}`,
+			expectedError: `struct "a": field "f": unknown type "unknown"`,
+		},
+		{
+			name:          "fixed size array",
+			abi:           `{"structs": [{"name": "a", "fields": [{"name": "f", "type": "uint8[4]"}]}]}`,
+			expectedError: `struct "a": field "f": unsupported type "uint8[4]"`,
+		},
+		{
+			name:          "field after binary extension",
+			abi:           `{"structs": [{"name": "a", "fields": [{"name": "f", "type": "uint8$"}, {"name": "g", "type": "uint8"}]}]}`,
+			expectedError: `struct "a": field "g" follows a binary extension`,
+		},
+		{
+			name:          "unknown action struct",
+			abi:           `{"actions": [{"name": "act", "type": "a"}]}`,
+			expectedError: `action "act": unknown struct "a"`,
+		},
+		{
+			name:          "unknown table type",
+			abi:           `{"tables": [{"name": "rows", "type": "a"}]}`,
+			expectedError: `table "rows": unknown row type "a"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			abi, err := eos.NewABI(strings.NewReader(test.abi))
+			require.NoError(t, err)
+
+			_, err = generate(abi, "example", "example")
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "UserResources", goName("user_resources"))
+	assert.Equal(t, "MemoXfer", goName("memo.xfer"))
+	assert.Equal(t, "X1stplace", goName("1stplace"))
+}
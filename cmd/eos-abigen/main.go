@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/eoscanada/eos-go"
+)
+
+var packageName = flag.String("package", "", "name of the generated package, defaults to the contract account with dots removed")
+var contract = flag.String("contract", "", "account the contract is deployed on (required)")
+var output = flag.String("o", "", "file to write the generated code to, defaults to stdout")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: eos-abigen -contract <account> [-package <name>] [-o <file>] <file.abi>\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 || *contract == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	abiFile, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatalln("error opening abi:", err)
+	}
+	defer abiFile.Close()
+
+	abi, err := eos.NewABI(abiFile)
+	if err != nil {
+		log.Fatalln("error reading abi:", err)
+	}
+
+	if diagnostics := abi.Validate(); len(diagnostics) > 0 {
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintln(os.Stderr, diagnostic)
+		}
+		log.Fatalln("invalid abi")
+	}
+
+	name := *packageName
+	if name == "" {
+		name = defaultPackageName(*contract)
+	}
+
+	code, err := generate(abi, name, eos.AccountName(*contract))
+	if err != nil {
+		log.Fatalln("error generating code:", err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(code)
+		return
+	}
+
+	if err := ioutil.WriteFile(*output, code, 0644); err != nil {
+		log.Fatalln("error writing code:", err)
+	}
+}
+
+func defaultPackageName(contract string) string {
+	var out []rune
+	for _, r := range contract {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' && len(out) > 0 {
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
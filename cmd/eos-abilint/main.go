@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/eoscanada/eos-go"
+)
+
+var jsonOutput = flag.Bool("json", false, "print the diagnostics and changes as JSON lines")
+var previous = flag.String("previous", "", "previous version of the abi, to report the changes and fail on breaking ones")
+
+type fileDiagnostic struct {
+	File string `json:"file"`
+	*eos.ABIDiagnostic
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: eos-abilint [-json] <file.abi>...\n")
+		fmt.Fprintf(os.Stderr, "       eos-abilint [-json] -previous <previous.abi> <file.abi>\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 || *previous != "" && flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	failed := false
+	for _, file := range flag.Args() {
+		abi, err := readABI(file)
+		if err != nil {
+			log.Fatalln("error reading abi:", err)
+		}
+
+		for _, diagnostic := range abi.Validate() {
+			failed = true
+			if *jsonOutput {
+				if err := encoder.Encode(fileDiagnostic{File: file, ABIDiagnostic: diagnostic}); err != nil {
+					log.Fatalln("error writing diagnostic:", err)
+				}
+				continue
+			}
+			fmt.Printf("%s: %s\n", file, diagnostic)
+		}
+
+		if *previous == "" {
+			continue
+		}
+
+		previousABI, err := readABI(*previous)
+		if err != nil {
+			log.Fatalln("error reading previous abi:", err)
+		}
+
+		diff := eos.DiffABI(previousABI, abi)
+		failed = failed || diff.Breaking
+		if *jsonOutput {
+			if err := encoder.Encode(diff); err != nil {
+				log.Fatalln("error writing changes:", err)
+			}
+			continue
+		}
+		for _, change := range diff.Changes {
+			fmt.Printf("%s: %s\n", file, change)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func readABI(file string) (*eos.ABI, error) {
+	abiFile, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer abiFile.Close()
+
+	abi, err := eos.NewABI(abiFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return abi, nil
+}
@@ -102,15 +102,9 @@ func init() {
 	TypeSize.UInt128 = TypeSize.Uint128
 }
 
-var RegisteredActions = map[AccountName]map[ActionName]reflect.Type{}
-
-// Registers Action objects..
+// RegisterAction registers Action objects in the `DefaultRegistry`.
 func RegisterAction(accountName AccountName, actionName ActionName, obj interface{}) {
-	// TODO: lock or som'th.. unless we never call after boot time..
-	if RegisteredActions[accountName] == nil {
-		RegisteredActions[accountName] = make(map[ActionName]reflect.Type)
-	}
-	RegisteredActions[accountName][actionName] = reflect.TypeOf(obj)
+	DefaultRegistry.RegisterAction(accountName, actionName, obj)
 }
 
 // Decoder implements the EOS unpacking, similar to FC_BUFFER
@@ -119,6 +113,7 @@ type Decoder struct {
 	pos              int
 	decodeP2PMessage bool
 	decodeActions    bool
+	registry         *Registry
 }
 
 func NewDecoder(data []byte) *Decoder {
@@ -137,6 +132,19 @@ func (d *Decoder) DecodeActions(decode bool) {
 	d.decodeActions = decode
 }
 
+// SetRegistry sets the registry used to decode the data of actions,
+// `DefaultRegistry` being used when nil.
+func (d *Decoder) SetRegistry(registry *Registry) {
+	d.registry = registry
+}
+
+func (d *Decoder) actionRegistry() *Registry {
+	if d.registry == nil {
+		return DefaultRegistry
+	}
+	return d.registry
+}
+
 type DecodeOption = interface{}
 
 type optionalFieldType bool
@@ -203,6 +211,13 @@ func (d *Decoder) Decode(v interface{}, options ...DecodeOption) (err error) {
 		}
 
 		rv = reflect.Indirect(newRV)
+
+		// Optional fields decode into the allocated value, so it is
+		// matched by the types below, like the `*string` of an optional
+		// `string`.
+		if optionalField {
+			v = newRV.Interface()
+		}
 	} else {
 		// We check if `v` directly is `UnmarshalerBinary` this is to overcome our bad code that
 		// has problem dealing with non-pointer type, which should still be possible here, by allocating
@@ -1226,38 +1241,18 @@ func (d *Decoder) ReadSymbolCode() (out SymbolCode, err error) {
 	return
 }
 
+// ReadActionData decodes the data of the action into the type
+// registered for it, or to JSON through the ABI registered for its
+// contract. The data is left as `HexData` only for unknown actions.
 func (d *Decoder) ReadActionData(action *Action) (err error) {
-	actionMap := RegisteredActions[action.Account]
-
-	var decodeInto reflect.Type
-	if actionMap != nil {
-
-		objType := actionMap[action.Name]
-		if objType != nil {
-			if tracer.Enabled() {
-				zlog.Debug("read object", zap.String("type", objType.Name()))
-			}
-			decodeInto = objType
-		}
-	}
-	if decodeInto == nil {
-		return
-	}
-
-	if tracer.Enabled() {
-		zlog.Debug("reflect type", zap.String("type", decodeInto.Name()))
-	}
-	obj := reflect.New(decodeInto)
-	iface := obj.Interface()
-	if tracer.Enabled() {
-		zlog.Debug("reflect object", typeField("type", iface), zap.Reflect("obj", obj))
-	}
-	err = UnmarshalBinary(action.ActionData.HexData, iface)
+	data, err := d.actionRegistry().decodeActionData(action)
 	if err != nil {
-		return fmt.Errorf("decoding Action [%s], %s", obj.Type().Name(), err)
+		return err
 	}
 
-	action.ActionData.Data = iface
+	if data != nil {
+		action.ActionData.Data = data
+	}
 
 	return
 }
@@ -1,8 +1,14 @@
 package ecc
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,14 +39,33 @@ func TestPrefixedK1PrivateToPublic(t *testing.T) {
 
 func TestR1PrivateToPublic(t *testing.T) {
 	encodedPrivKey := "PVT_R1_2o5WfMRU4dTp23pbcbP2yn5MumQzSMy3ayNQ31qi5nUfa2jdWC"
-	_, err := NewPrivateKey(encodedPrivKey)
+	privKey, err := NewPrivateKey(encodedPrivKey)
 	require.NoError(t, err)
+	assert.Equal(t, encodedPrivKey, privKey.String())
+
+	pubKey := privKey.PublicKey()
+	require.NoError(t, pubKey.Validate())
+	assert.Equal(t, "PUB_R1_6RJ9pXJNe1wk6p2yiJcuJ8QPo7WTudHya9z8vu1VPk44fhBz79", pubKey.String())
+}
 
-	// FIXME: Actual retrieval of publicKey from privateKey for R1 is not done yet, disable this check
-	// pubKey := privKey.PublicKey()
+func TestR1PrivateKeyValidity(t *testing.T) {
+	_, err := NewPrivateKey("PVT_R1_2o5WfMRU4dTp23pbcbP2yn5MumQzSMy3ayNQ31qi5nUfa2jdWD")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "private key checksum failed")
 
-	// pubKeyString := pubKey.String()
-	// assert.Equal(t, "PUB_R1_0000000000000000000000000000000000000000000000", pubKeyString)
+	_, err = NewPrivateKeyFromData(append([]byte{byte(CurveR1)}, bytes.Repeat([]byte{0xff}, 32)...))
+	assert.EqualError(t, err, "R1 private key is out of the curve order range")
+}
+
+func TestNewRandomR1PrivateKey(t *testing.T) {
+	key, err := NewRandomR1PrivateKey()
+	require.NoError(t, err)
+	assert.Equal(t, CurveR1, key.Curve)
+	assert.Regexp(t, "^PVT_R1_", key.String())
+
+	parsed, err := NewPrivateKey(key.String())
+	require.NoError(t, err)
+	assert.Equal(t, key.PublicKey().String(), parsed.PublicKey().String())
 }
 
 func TestNewPublicKeyAndSerializeCompress(t *testing.T) {
@@ -118,7 +143,112 @@ func TestR1Signature(t *testing.T) {
 
 	cnt := []byte("hi")
 	digest := sigDigest([]byte{}, cnt, nil)
-	_, err = privKey.Sign(digest)
-	assert.Error(t, err)
-	assert.Equal(t, "R1 not supported", err.Error())
+	signature, err := privKey.Sign(digest)
+	require.NoError(t, err)
+	require.NoError(t, signature.Validate())
+
+	assert.Regexp(t, "^SIG_R1_", signature.String())
+	assert.True(t, signature.Content[0] >= 31 && signature.Content[0] < 35)
+	assert.True(t, signature.Verify(digest, privKey.PublicKey()))
+
+	// S is normalized to the lower half of the curve order
+	halfOrder := new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+	assert.True(t, new(big.Int).SetBytes(signature.Content[33:]).Cmp(halfOrder) <= 0)
+
+	key, err := privKey.PublicKey().Key()
+	require.NoError(t, err)
+	assert.True(t, ecdsa.Verify(key.ToECDSA(), digest, new(big.Int).SetBytes(signature.Content[1:33]), new(big.Int).SetBytes(signature.Content[33:])))
+
+	parsed, err := NewSignature(signature.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.Verify(digest, privKey.PublicKey()))
+
+	otherKey, err := NewRandomR1PrivateKey()
+	require.NoError(t, err)
+	assert.False(t, signature.Verify(digest, otherKey.PublicKey()))
+	assert.False(t, signature.Verify(sigDigest([]byte{}, []byte("ho"), nil), privKey.PublicKey()))
+}
+
+func TestWASignature(t *testing.T) {
+	r1Key, err := NewPrivateKey("PVT_R1_2o5WfMRU4dTp23pbcbP2yn5MumQzSMy3ayNQ31qi5nUfa2jdWC")
+	require.NoError(t, err)
+
+	authenticator, err := NewWebAuthnAuthenticator(r1Key, "example.com")
+	require.NoError(t, err)
+
+	pubKey := authenticator.PublicKey()
+	require.NoError(t, pubKey.Validate())
+	assert.Regexp(t, "^PUB_WA_", pubKey.String())
+
+	parsedPubKey, err := NewPublicKey(pubKey.String())
+	require.NoError(t, err)
+	assert.Equal(t, pubKey.Content, parsedPubKey.Content)
+	assert.Equal(t, r1Key.PublicKey().Content, pubKey.Content[:33])
+	assert.Equal(t, append([]byte{WAUserPresencePresent, 11}, "example.com"...), pubKey.Content[33:])
+
+	digest := sigDigest([]byte{}, []byte("hi"), nil)
+	signature, err := authenticator.Sign(digest)
+	require.NoError(t, err)
+	assert.Regexp(t, "^SIG_WA_", signature.String())
+	assert.True(t, signature.Verify(digest, pubKey))
+
+	parsed, err := NewSignature(signature.String())
+	require.NoError(t, err)
+	recovered, err := parsed.PublicKey(digest)
+	require.NoError(t, err)
+	assert.Equal(t, pubKey.String(), recovered.String())
+
+	authenticatorData, rest, err := readWAField(signature.Content[65:])
+	require.NoError(t, err)
+	clientData, _, err := readWAField(rest)
+	require.NoError(t, err)
+
+	rpIDHash := sha256.Sum256([]byte("example.com"))
+	assert.Equal(t, rpIDHash[:], authenticatorData[:32])
+	assert.Equal(t, []byte{0x01, 0, 0, 0, 1}, authenticatorData[32:])
+	assert.JSONEq(t, `{"type":"webauthn.get","challenge":"`+base64.RawURLEncoding.EncodeToString(digest)+`","origin":"https://example.com"}`, string(clientData))
+
+	assert.False(t, signature.Verify(sigDigest([]byte{}, []byte("ho"), nil), pubKey))
+	assert.False(t, signature.Verify(digest, r1Key.PublicKey()))
+
+	// A key with another relying party or user presence is another key
+	other, err := NewWebAuthnAuthenticator(r1Key, "other.com")
+	require.NoError(t, err)
+	assert.False(t, signature.Verify(digest, other.PublicKey()))
+
+	verified, err := NewWebAuthnAuthenticator(r1Key, "example.com")
+	require.NoError(t, err)
+	verified.UserVerified = true
+	verified.Origin = "https://example.com:8443"
+	assert.False(t, signature.Verify(digest, verified.PublicKey()))
+
+	signature, err = verified.Sign(digest)
+	require.NoError(t, err)
+	assert.True(t, signature.Verify(digest, verified.PublicKey()))
+	assert.False(t, signature.Verify(digest, pubKey))
+
+	verified.Origin = "https://example.com/login:step"
+	signature, err = verified.Sign(digest)
+	require.NoError(t, err)
+	assert.True(t, signature.Verify(digest, verified.PublicKey()))
+}
+
+func TestWAPrivateKey(t *testing.T) {
+	authenticator, err := NewRandomWebAuthnAuthenticator("example.com")
+	require.NoError(t, err)
+
+	privKey := authenticator.PrivateKey()
+	assert.Equal(t, CurveWA, privKey.Curve)
+	assert.Equal(t, authenticator.PublicKey().String(), privKey.PublicKey().String())
+
+	digest := sigDigest([]byte{}, []byte("hi"), nil)
+	signature, err := privKey.Sign(digest)
+	require.NoError(t, err)
+	assert.True(t, signature.Verify(digest, privKey.PublicKey()))
+
+	_, err = NewWebAuthnAuthenticator(privKey, "example.com")
+	assert.EqualError(t, err, "WebAuthn credential key must be an R1 key, got WA")
+
+	_, err = NewPrivateKey("PVT_WA_PLACE_HOLDER")
+	assert.EqualError(t, err, "WA private keys have no string format, use a WebAuthnAuthenticator")
 }
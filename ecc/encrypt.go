@@ -0,0 +1,205 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eoscanada/eos-go/btcsuite/btcd/btcec"
+	"github.com/eoscanada/eos-go/btcsuite/btcutil/base58"
+)
+
+// EncryptedMemoPrefix prefixes encrypted memos, see `EncryptedMessage.Memo`.
+const EncryptedMemoPrefix = "#"
+
+var ErrInvalidMessageKey = errors.New("invalid key, the message was not encrypted with it")
+
+// EncryptedMessage is a message encrypted from a sender to a recipient
+// with the shared secret of their K1 keys, the way `eosjs-ecc`'s
+// `Aes.encrypt` does: the AES-256-CBC key and IV are the SHA512 hash
+// of the nonce followed by the shared secret, and the checksum is the
+// first 4 bytes of the SHA256 hash of that hash.
+type EncryptedMessage struct {
+	From     PublicKey
+	To       PublicKey
+	Nonce    uint64
+	Checksum uint32
+	Data     []byte
+}
+
+// EncryptMessage encrypts `message` from `privKey`, the sender's key,
+// to `pubKey`, the recipient's key, with a random nonce.
+func EncryptMessage(privKey *PrivateKey, pubKey PublicKey, message []byte) (*EncryptedMessage, error) {
+	nonce := make([]byte, 8)
+	if _, err := cryptorand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error feeding crypto-rand numbers to seed nonce: %w", err)
+	}
+
+	return EncryptMessageWithNonce(privKey, pubKey, binary.LittleEndian.Uint64(nonce), message)
+}
+
+// EncryptMessageWithNonce encrypts `message` from `privKey`, the
+// sender's key, to `pubKey`, the recipient's key. The nonce must be
+// unique for each message of a pair of keys.
+func EncryptMessageWithNonce(privKey *PrivateKey, pubKey PublicKey, nonce uint64, message []byte) (*EncryptedMessage, error) {
+	key, iv, checksum, err := messageKey(privKey, pubKey, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	padding := aes.BlockSize - len(message)%aes.BlockSize
+	data := append(append([]byte{}, message...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+
+	return &EncryptedMessage{
+		From:     privKey.PublicKey(),
+		To:       pubKey,
+		Nonce:    nonce,
+		Checksum: checksum,
+		Data:     data,
+	}, nil
+}
+
+// Decrypt decrypts the message with `privKey`, the private key of
+// either its sender or its recipient.
+func (m *EncryptedMessage) Decrypt(privKey *PrivateKey) ([]byte, error) {
+	var otherKey PublicKey
+	switch privKey.PublicKey().String() {
+	case m.To.String():
+		otherKey = m.From
+	case m.From.String():
+		otherKey = m.To
+	default:
+		return nil, fmt.Errorf("private key is neither the sender nor the recipient of the message")
+	}
+
+	key, iv, checksum, err := messageKey(privKey, otherKey, m.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if checksum != m.Checksum {
+		return nil, ErrInvalidMessageKey
+	}
+
+	if len(m.Data) == 0 || len(m.Data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("encrypted data should be a non-empty multiple of %d bytes, got %d", aes.BlockSize, len(m.Data))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, len(m.Data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(data, m.Data)
+
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(data[len(data)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, fmt.Errorf("invalid message padding")
+	}
+
+	return data[:len(data)-padding], nil
+}
+
+// Memo returns the message encoded for a transfer memo, `#` followed by
+// the base58 encoding of the sender and recipient compressed public
+// keys (33 bytes each), the nonce and checksum (little endian) and the
+// size-prefixed encrypted data, the Steem encrypted memo layout.
+func (m *EncryptedMessage) Memo() string {
+	buffer := &bytes.Buffer{}
+	buffer.Write(m.From.Content)
+	buffer.Write(m.To.Content)
+	_ = binary.Write(buffer, binary.LittleEndian, m.Nonce)
+	_ = binary.Write(buffer, binary.LittleEndian, m.Checksum)
+	buffer.Write(appendUvarint(nil, uint64(len(m.Data))))
+	buffer.Write(m.Data)
+
+	return EncryptedMemoPrefix + base58.Encode(buffer.Bytes())
+}
+
+// IsEncryptedMemo returns whether `memo` looks like an encrypted memo,
+// see `EncryptedMessage.Memo`.
+func IsEncryptedMemo(memo string) bool {
+	_, err := ParseEncryptedMemo(memo)
+	return err == nil
+}
+
+// ParseEncryptedMemo parses a memo encoded by `EncryptedMessage.Memo`.
+func ParseEncryptedMemo(memo string) (*EncryptedMessage, error) {
+	if !strings.HasPrefix(memo, EncryptedMemoPrefix) {
+		return nil, fmt.Errorf("encrypted memo should start with %q", EncryptedMemoPrefix)
+	}
+
+	data := base58.Decode(memo[len(EncryptedMemoPrefix):])
+	if len(data) < 33+33+8+4+1 {
+		return nil, fmt.Errorf("encrypted memo is too short")
+	}
+
+	from, err := NewPublicKeyFromData(append([]byte{byte(CurveK1)}, data[:33]...))
+	if err != nil {
+		return nil, fmt.Errorf("encrypted memo sender: %w", err)
+	}
+
+	to, err := NewPublicKeyFromData(append([]byte{byte(CurveK1)}, data[33:66]...))
+	if err != nil {
+		return nil, fmt.Errorf("encrypted memo recipient: %w", err)
+	}
+
+	message := &EncryptedMessage{
+		From:     from,
+		To:       to,
+		Nonce:    binary.LittleEndian.Uint64(data[66:74]),
+		Checksum: binary.LittleEndian.Uint32(data[74:78]),
+	}
+
+	size, read := binary.Uvarint(data[78:])
+	if read <= 0 || uint64(len(data[78+read:])) != size {
+		return nil, fmt.Errorf("encrypted memo data size is invalid")
+	}
+	message.Data = data[78+read:]
+
+	return message, nil
+}
+
+// messageKey returns the AES key and IV, and the checksum, of the
+// messages between the two keys with `nonce`.
+func messageKey(privKey *PrivateKey, pubKey PublicKey, nonce uint64) (key []byte, iv []byte, checksum uint32, err error) {
+	k1PrivKey, ok := privKey.inner.(*innerK1PrivateKey)
+	if !ok {
+		return nil, nil, 0, fmt.Errorf("only K1 keys can encrypt messages, got a %s private key", privKey.Curve)
+	}
+
+	if pubKey.Curve != CurveK1 {
+		return nil, nil, 0, fmt.Errorf("only K1 keys can encrypt messages, got a %s public key", pubKey.Curve)
+	}
+
+	k1PubKey, err := pubKey.Key()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	sharedX := make([]byte, 32)
+	secret := btcec.GenerateSharedSecret(k1PrivKey.privKey, k1PubKey)
+	copy(sharedX[32-len(secret):], secret)
+	sharedSecret := sha512.Sum512(sharedX)
+
+	buffer := make([]byte, 8, 8+len(sharedSecret))
+	binary.LittleEndian.PutUint64(buffer, nonce)
+	encryptionKey := sha512.Sum512(append(buffer, sharedSecret[:]...))
+
+	check := sha256.Sum256(encryptionKey[:])
+	return encryptionKey[:32], encryptionKey[32:48], binary.LittleEndian.Uint32(check[:4]), nil
+}
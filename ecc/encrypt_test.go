@@ -0,0 +1,85 @@
+package ecc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEncryptionKeys(t *testing.T) (sender *PrivateKey, recipient *PrivateKey) {
+	sender, err := NewPrivateKey("5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3")
+	require.NoError(t, err)
+
+	recipient, err = NewPrivateKey("5KYZdUEo39z3FPrtuX2QbbwGnNP5zTd7yyr2SC1j299sBCnWjss")
+	require.NoError(t, err)
+
+	return sender, recipient
+}
+
+// The expected checksum and data were computed following `eosjs-ecc`'s
+// `Aes.encrypt` with Node.js crypto.
+func TestEncryptMessageWithNonce(t *testing.T) {
+	sender, recipient := newTestEncryptionKeys(t)
+
+	message, err := EncryptMessageWithNonce(sender, recipient.PublicKey(), 1234567890123456789, []byte("invoice 42"))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3090519763), message.Checksum)
+	assert.Equal(t, "d72e7126c24d4589f81c17a25849f7cf", hex.EncodeToString(message.Data))
+	assert.Equal(t, sender.PublicKey(), message.From)
+	assert.Equal(t, recipient.PublicKey(), message.To)
+
+	for _, key := range []*PrivateKey{sender, recipient} {
+		decrypted, err := message.Decrypt(key)
+		require.NoError(t, err)
+		assert.Equal(t, "invoice 42", string(decrypted))
+	}
+}
+
+func TestEncryptedMessage_Memo(t *testing.T) {
+	sender, recipient := newTestEncryptionKeys(t)
+
+	message, err := EncryptMessage(sender, recipient.PublicKey(), []byte("a message of more than one AES block"))
+	require.NoError(t, err)
+
+	memo := message.Memo()
+	assert.Regexp(t, "^#", memo)
+	assert.True(t, IsEncryptedMemo(memo))
+	assert.False(t, IsEncryptedMemo("invoice 42"))
+	assert.False(t, IsEncryptedMemo("#hashtag"))
+
+	parsed, err := ParseEncryptedMemo(memo)
+	require.NoError(t, err)
+	assert.Equal(t, message, parsed)
+
+	decrypted, err := parsed.Decrypt(recipient)
+	require.NoError(t, err)
+	assert.Equal(t, "a message of more than one AES block", string(decrypted))
+
+	other, err := EncryptMessage(sender, recipient.PublicKey(), []byte("a message of more than one AES block"))
+	require.NoError(t, err)
+	assert.NotEqual(t, message.Data, other.Data)
+}
+
+func TestEncryptedMessage_DecryptErrors(t *testing.T) {
+	sender, recipient := newTestEncryptionKeys(t)
+
+	message, err := EncryptMessage(sender, recipient.PublicKey(), []byte("invoice 42"))
+	require.NoError(t, err)
+
+	stranger, err := NewRandomPrivateKey()
+	require.NoError(t, err)
+	_, err = message.Decrypt(stranger)
+	assert.EqualError(t, err, "private key is neither the sender nor the recipient of the message")
+
+	tampered := *message
+	tampered.Nonce++
+	_, err = tampered.Decrypt(recipient)
+	assert.Equal(t, ErrInvalidMessageKey, err)
+
+	r1Key, err := NewRandomR1PrivateKey()
+	require.NoError(t, err)
+	_, err = EncryptMessage(r1Key, recipient.PublicKey(), []byte("invoice 42"))
+	assert.EqualError(t, err, "only K1 keys can encrypt messages, got a R1 private key")
+}
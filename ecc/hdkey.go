@@ -0,0 +1,202 @@
+package ecc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/eoscanada/eos-go/btcsuite/btcd/btcec"
+	"github.com/eoscanada/eos-go/btcsuite/btcutil"
+	"github.com/eoscanada/eos-go/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// HardenedKeyStart is the index of the first hardened child key, noted
+// with a `'` in derivation paths.
+const HardenedKeyStart = uint32(0x80000000)
+
+// EOSCoinType is the SLIP-44 coin type of EOS.
+const EOSCoinType = uint32(194)
+
+// xprvVersion is the version of serialized mainnet BIP32 private keys.
+var xprvVersion = []byte{0x04, 0x88, 0xad, 0xe4}
+
+// HDKey is a BIP32 extended private key on the K1 curve, from which
+// child keys are derived.
+type HDKey struct {
+	key               []byte
+	chainCode         []byte
+	depth             uint8
+	parentFingerprint []byte
+	childNumber       uint32
+}
+
+// NewHDKeyFromSeed returns the BIP32 master key of `seed`, 16 to 64
+// bytes like the ones returned by `MnemonicToSeed`.
+func NewHDKeyFromSeed(seed []byte) (*HDKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed should be 16 to 64 bytes, got %d", len(seed))
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	_, _ = mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	if !isValidK1Key(new(big.Int).SetBytes(sum[:32])) {
+		return nil, fmt.Errorf("seed derives an invalid master key")
+	}
+
+	return &HDKey{key: sum[:32], chainCode: sum[32:], parentFingerprint: make([]byte, 4)}, nil
+}
+
+// NewHDKeyFromMnemonic returns the BIP32 master key of a BIP39 mnemonic
+// and its (optional) passphrase.
+func NewHDKeyFromMnemonic(mnemonic string, passphrase string) (*HDKey, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewHDKeyFromSeed(seed)
+}
+
+// EOSDerivationPath returns the BIP44 derivation path of EOS keys,
+// `m/44'/194'/account'/0/index`, the one used by hardware wallets and
+// Anchor.
+func EOSDerivationPath(account, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/0/%d", EOSCoinType, account, index)
+}
+
+// NewPrivateKeyFromMnemonic derives the private key at the EOS
+// derivation path `m/44'/194'/account'/0/index` of a BIP39 mnemonic
+// and its (optional) passphrase.
+func NewPrivateKeyFromMnemonic(mnemonic string, passphrase string, account, index uint32) (*PrivateKey, error) {
+	master, err := NewHDKeyFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := master.Derive(EOSDerivationPath(account, index))
+	if err != nil {
+		return nil, err
+	}
+	return key.PrivateKey(), nil
+}
+
+// Derive derives the key at `path`, like `m/44'/194'/0'/0/0`, from
+// this key, hardened indexes being marked with `'` (or `h`).
+func (k *HDKey) Derive(path string) (*HDKey, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q should start with %q", path, "m")
+	}
+
+	key := k
+	for _, segment := range segments[1:] {
+		hardened := strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h") || strings.HasSuffix(segment, "H")
+		if hardened {
+			segment = segment[:len(segment)-1]
+		}
+
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("derivation path %q has an invalid index %q", path, segment)
+		}
+
+		if hardened {
+			index += uint64(HardenedKeyStart)
+		}
+
+		key, err = key.Child(uint32(index))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// Child derives the child key at `index`, a hardened child key when
+// `index` is `HardenedKeyStart` or more.
+func (k *HDKey) Child(index uint32) (*HDKey, error) {
+	if k.depth == 255 {
+		return nil, fmt.Errorf("cannot derive a key deeper than 255 levels")
+	}
+
+	data := make([]byte, 37)
+	if index >= HardenedKeyStart {
+		copy(data[1:], k.key)
+	} else {
+		copy(data, k.publicKeyBytes())
+	}
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	_, _ = mac.Write(data)
+	sum := mac.Sum(nil)
+
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(btcec.S256().N) >= 0 {
+		return nil, fmt.Errorf("child key %d is invalid, use the next index", index)
+	}
+
+	childKey := tweak.Add(tweak, new(big.Int).SetBytes(k.key))
+	childKey.Mod(childKey, btcec.S256().N)
+	if childKey.Sign() == 0 {
+		return nil, fmt.Errorf("child key %d is invalid, use the next index", index)
+	}
+
+	return &HDKey{
+		key:               childKey.FillBytes(make([]byte, 32)),
+		chainCode:         sum[32:],
+		depth:             k.depth + 1,
+		parentFingerprint: hash160(k.publicKeyBytes())[:4],
+		childNumber:       index,
+	}, nil
+}
+
+// PrivateKey returns the K1 private key of this extended key.
+func (k *HDKey) PrivateKey() *PrivateKey {
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), k.key)
+	return &PrivateKey{Curve: CurveK1, inner: &innerK1PrivateKey{privKey: privKey}}
+}
+
+// PublicKey returns the K1 public key of this extended key.
+func (k *HDKey) PublicKey() PublicKey {
+	return k.PrivateKey().PublicKey()
+}
+
+// String returns the key serialized as a BIP32 `xprv` key.
+func (k *HDKey) String() string {
+	data := make([]byte, 0, 82)
+	data = append(data, xprvVersion...)
+	data = append(data, k.depth)
+	data = append(data, k.parentFingerprint...)
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], k.childNumber)
+	data = append(data, k.chainCode...)
+	data = append(data, 0)
+	data = append(data, k.key...)
+	data = append(data, btcutil.DoubleHashB(data)[:4]...)
+	return base58.Encode(data)
+}
+
+func (k *HDKey) publicKeyBytes() []byte {
+	_, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), k.key)
+	return pubKey.SerializeCompressed()
+}
+
+func isValidK1Key(key *big.Int) bool {
+	return key.Sign() > 0 && key.Cmp(btcec.S256().N) < 0
+}
+
+func hash160(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	h := ripemd160.New()
+	_, _ = h.Write(sum[:]) // this implementation has no error path
+	return h.Sum(nil)
+}
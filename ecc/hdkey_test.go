@@ -0,0 +1,82 @@
+package ecc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors 1 and 2 from https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vectors
+func TestHDKey_BIP32Vectors(t *testing.T) {
+	tests := []struct {
+		seed     string
+		path     string
+		expected string
+	}{
+		{"000102030405060708090a0b0c0d0e0f", "m", "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'", "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'/1/2'/2/1000000000", "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76"},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", "m", "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U"},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", "m/0/2147483647'/1/2147483646'/2", "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			seed, err := hex.DecodeString(test.seed)
+			require.NoError(t, err)
+
+			master, err := NewHDKeyFromSeed(seed)
+			require.NoError(t, err)
+
+			key, err := master.Derive(test.path)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, key.String())
+		})
+	}
+}
+
+// The expected keys were checked against an independent BIP32
+// implementation.
+func TestNewPrivateKeyFromMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	assert.Equal(t, "m/44'/194'/0'/0/0", EOSDerivationPath(0, 0))
+
+	privKey, err := NewPrivateKeyFromMnemonic(mnemonic, "", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "5K2VtCafACZx6iiN5xyBb67UszFQa6yVLR8UquZU2x6aPmbQnU6", privKey.String())
+	assert.Equal(t, "EOS6zpSNY1YoLxNt2VsvJjoDfBueU6xC1M1ERJw1UoekL1NHn8KNA", privKey.PublicKey().String())
+
+	master, err := NewHDKeyFromMnemonic(mnemonic, "")
+	require.NoError(t, err)
+	key, err := master.Derive("m/44h/194h/0h/0/0")
+	require.NoError(t, err)
+	assert.Equal(t, privKey.String(), key.PrivateKey().String())
+	assert.Equal(t, privKey.PublicKey().String(), key.PublicKey().String())
+
+	other, err := NewPrivateKeyFromMnemonic(mnemonic, "", 0, 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, privKey.String(), other.String())
+
+	withPassphrase, err := NewPrivateKeyFromMnemonic(mnemonic, "TREZOR", 0, 0)
+	require.NoError(t, err)
+	assert.NotEqual(t, privKey.String(), withPassphrase.String())
+}
+
+func TestHDKey_Errors(t *testing.T) {
+	_, err := NewHDKeyFromSeed(make([]byte, 8))
+	assert.EqualError(t, err, "seed should be 16 to 64 bytes, got 8")
+
+	master, err := NewHDKeyFromSeed(make([]byte, 16))
+	require.NoError(t, err)
+
+	_, err = master.Derive("44'/194'")
+	assert.EqualError(t, err, `derivation path "44'/194'" should start with "m"`)
+
+	_, err = master.Derive("m/44'/abc")
+	assert.EqualError(t, err, `derivation path "m/44'/abc" has an invalid index "abc"`)
+
+	_, err = master.Derive("m/2147483648")
+	assert.EqualError(t, err, `derivation path "m/2147483648" has an invalid index "2147483648"`)
+}
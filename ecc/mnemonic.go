@@ -0,0 +1,135 @@
+package ecc
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// bip39English is the BIP39 english word list,
+// https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+//
+//go:embed bip39_english.txt
+var bip39English string
+
+var bip39Words = strings.Split(strings.TrimSpace(bip39English), "\n")
+
+var bip39WordIndex = func() map[string]int {
+	out := make(map[string]int, len(bip39Words))
+	for i, word := range bip39Words {
+		out[word] = i
+	}
+	return out
+}()
+
+var ErrInvalidMnemonicChecksum = errors.New("invalid mnemonic checksum")
+
+// NewMnemonic generates a BIP39 mnemonic from `entropyBits` bits of
+// randomness, 128 (12 words) to 256 (24 words) by steps of 32.
+func NewMnemonic(entropyBits int) (string, error) {
+	if err := validateEntropyBits(entropyBits); err != nil {
+		return "", err
+	}
+
+	entropy := make([]byte, entropyBits/8)
+	if _, err := io.ReadFull(cryptorand.Reader, entropy); err != nil {
+		return "", fmt.Errorf("error feeding crypto-rand numbers to seed mnemonic: %w", err)
+	}
+
+	return NewMnemonicFromEntropy(entropy)
+}
+
+// NewMnemonicFromEntropy returns the BIP39 mnemonic encoding `entropy`,
+// 16 to 32 bytes by steps of 4.
+func NewMnemonicFromEntropy(entropy []byte) (string, error) {
+	entropyBits := len(entropy) * 8
+	if err := validateEntropyBits(entropyBits); err != nil {
+		return "", err
+	}
+
+	checksumBits := entropyBits / 32
+	hash := sha256.Sum256(entropy)
+
+	// The entropy followed by the first bits of its hash, split in groups
+	// of 11 bits indexing the word list.
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, uint(checksumBits))
+	data.Or(data, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	wordCount := (entropyBits + checksumBits) / 11
+	words := make([]string, wordCount)
+	mask := big.NewInt(2047)
+	for i := wordCount - 1; i >= 0; i-- {
+		words[i] = bip39Words[new(big.Int).And(data, mask).Int64()]
+		data.Rsh(data, 11)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes a BIP39 mnemonic to its entropy, checking
+// that all its words are in the english word list and its checksum.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, fmt.Errorf("mnemonic should have 12, 15, 18, 21 or 24 words, got %d", len(words))
+	}
+
+	data := new(big.Int)
+	for _, word := range words {
+		index, found := bip39WordIndex[word]
+		if !found {
+			return nil, fmt.Errorf("word %q is not in the mnemonic word list", word)
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(index)))
+	}
+
+	checksumBits := len(words) / 3
+	checksum := new(big.Int).And(data, big.NewInt(int64(1<<checksumBits-1))).Int64()
+	data.Rsh(data, uint(checksumBits))
+
+	entropy := data.FillBytes(make([]byte, (len(words)*11-checksumBits)/8))
+	hash := sha256.Sum256(entropy)
+	if int64(hash[0]>>(8-checksumBits)) != checksum {
+		return nil, ErrInvalidMnemonicChecksum
+	}
+
+	return entropy, nil
+}
+
+// ValidateMnemonic checks that `mnemonic` is a valid BIP39 mnemonic.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// MnemonicToSeed validates a BIP39 mnemonic and returns the 64 bytes
+// seed derived from it and the (optional) `passphrase`, the input of
+// `NewHDKeyFromSeed`.
+//
+// The mnemonic and the passphrase are used as is, they must already be
+// in Unicode NFKD form when not ASCII.
+func MnemonicToSeed(mnemonic string, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	normalized := strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(normalized), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
+
+func validateEntropyBits(entropyBits int) error {
+	if entropyBits < 128 || entropyBits > 256 || entropyBits%32 != 0 {
+		return fmt.Errorf("entropy should be 128 to 256 bits by steps of 32, got %d", entropyBits)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package ecc
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors from https://github.com/trezor/python-mnemonic/blob/master/vectors.json,
+// all using the "TREZOR" passphrase.
+func TestMnemonic_Vectors(t *testing.T) {
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			"80808080808080808080808080808080",
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+			"d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+		},
+		{
+			"ffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mnemonic, func(t *testing.T) {
+			entropy, err := hex.DecodeString(test.entropy)
+			require.NoError(t, err)
+
+			mnemonic, err := NewMnemonicFromEntropy(entropy)
+			require.NoError(t, err)
+			assert.Equal(t, test.mnemonic, mnemonic)
+
+			decoded, err := MnemonicToEntropy(mnemonic)
+			require.NoError(t, err)
+			assert.Equal(t, entropy, decoded)
+
+			seed, err := MnemonicToSeed(mnemonic, "TREZOR")
+			require.NoError(t, err)
+			assert.Equal(t, test.seed, hex.EncodeToString(seed))
+		})
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for _, bits := range []int{128, 160, 192, 224, 256} {
+		mnemonic, err := NewMnemonic(bits)
+		require.NoError(t, err)
+		assert.Len(t, strings.Fields(mnemonic), bits*3/32)
+		assert.NoError(t, ValidateMnemonic(mnemonic))
+	}
+
+	_, err := NewMnemonic(100)
+	assert.EqualError(t, err, "entropy should be 128 to 256 bits by steps of 32, got 100")
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	tests := []struct {
+		mnemonic    string
+		expectedErr string
+	}{
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", ""},
+		{"  abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon   about ", ""},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "invalid mnemonic checksum"},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon eos", `word "eos" is not in the mnemonic word list`},
+		{"abandon abandon abandon", "mnemonic should have 12, 15, 18, 21 or 24 words, got 3"},
+	}
+
+	for _, test := range tests {
+		err := ValidateMnemonic(test.mnemonic)
+		if test.expectedErr == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, test.expectedErr)
+		}
+	}
+
+	_, err := MnemonicToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "")
+	assert.Equal(t, ErrInvalidMnemonicChecksum, err)
+}
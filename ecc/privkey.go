@@ -64,14 +64,14 @@ func NewPrivateKey(wif string) (*PrivateKey, error) {
 			inner := &innerK1PrivateKey{privKey: wifObj.PrivKey}
 			return &PrivateKey{Curve: CurveK1, inner: inner}, nil
 		case "R1_":
-
-			inner := &innerR1PrivateKey{}
-			return &PrivateKey{Curve: CurveR1, inner: inner}, nil
+			rawPrivKey, err := decodeKeyMaterial("private key", privKeyMaterial, CurveR1, ripemd160checksum)
+			if err != nil {
+				return nil, err
+			}
+			return newR1PrivateKey(rawPrivKey)
 
 		case "WA_":
-
-			inner := &innerWAPrivateKey{}
-			return &PrivateKey{Curve: CurveWA, inner: inner}, nil
+			return nil, fmt.Errorf("WA private keys have no string format, use a WebAuthnAuthenticator")
 
 		default:
 			return nil, fmt.Errorf("unsupported curve prefix %q", curvePrefix)
@@ -88,6 +88,26 @@ func NewPrivateKey(wif string) (*PrivateKey, error) {
 	}
 }
 
+// NewPrivateKeyFromData creates a private key from its binary
+// representation, a curve ID byte followed by the 32 bytes of the key,
+// as serialized by `nodeos` and `keosd`.
+func NewPrivateKeyFromData(data []byte) (*PrivateKey, error) {
+	if len(data) != 33 {
+		return nil, fmt.Errorf("data should be 33 bytes, got %d", len(data))
+	}
+
+	switch CurveID(data[0]) {
+	case CurveK1:
+		privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), data[1:])
+		inner := &innerK1PrivateKey{privKey: privKey}
+		return &PrivateKey{Curve: CurveK1, inner: inner}, nil
+	case CurveR1:
+		return newR1PrivateKey(data[1:])
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", CurveID(data[0]))
+	}
+}
+
 func NewPrivateKeyFromSeed(seed string) (*PrivateKey, error) {
 	hashByte := sha256.Sum256([]byte(seed))
 	privateKey, err := NewDeterministicPrivateKey(bytes.NewBuffer(hashByte[:]))
@@ -1,25 +1,114 @@
 package ecc
 
 import (
-	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
 	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/eoscanada/eos-go/btcsuite/btcutil/base58"
 )
 
+// NewRandomR1PrivateKey generates a new secp256r1 (P-256) private key.
+func NewRandomR1PrivateKey() (*PrivateKey, error) {
+	return NewDeterministicR1PrivateKey(cryptorand.Reader)
+}
+
+// NewDeterministicR1PrivateKey generates a secp256r1 (P-256) private
+// key from the 32 bytes blocks read from `randSource`, skipping the
+// ones that are not a valid key.
+func NewDeterministicR1PrivateKey(randSource io.Reader) (*PrivateKey, error) {
+	rawPrivKey := make([]byte, 32)
+	for {
+		if _, err := io.ReadFull(randSource, rawPrivKey); err != nil {
+			return nil, fmt.Errorf("error feeding crypto-rand numbers to seed ephemeral private key: %w", err)
+		}
+
+		if privKey, err := newR1PrivateKey(rawPrivKey); err == nil {
+			return privKey, nil
+		}
+	}
+}
+
+func newR1PrivateKey(rawPrivKey []byte) (*PrivateKey, error) {
+	if len(rawPrivKey) != 32 {
+		return nil, fmt.Errorf("R1 private key should be 32 bytes, got %d", len(rawPrivKey))
+	}
+
+	curve := elliptic.P256()
+	d := new(big.Int).SetBytes(rawPrivKey)
+	if d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+		return nil, fmt.Errorf("R1 private key is out of the curve order range")
+	}
+
+	privKey := &ecdsa.PrivateKey{D: d}
+	privKey.PublicKey.Curve = curve
+	privKey.PublicKey.X, privKey.PublicKey.Y = curve.ScalarBaseMult(rawPrivKey)
+
+	inner := &innerR1PrivateKey{privKey: privKey}
+	return &PrivateKey{Curve: CurveR1, inner: inner}, nil
+}
+
 type innerR1PrivateKey struct {
+	privKey *ecdsa.PrivateKey
 }
 
 func (k *innerR1PrivateKey) publicKey() PublicKey {
-	//TODO: Finish R1 support here - for now we  return bogus key
-	var pubKeyData []byte
-	pubKeyData = append(pubKeyData, byte(1))
-	pubKeyData = append(pubKeyData, bytes.Repeat([]byte{0}, 33)...)
-	return PublicKey{Curve: CurveR1, Content: pubKeyData, inner: &innerR1PublicKey{}}
+	content := elliptic.MarshalCompressed(k.privKey.Curve, k.privKey.X, k.privKey.Y)
+	return PublicKey{Curve: CurveR1, Content: content, inner: &innerR1PublicKey{}}
 }
 
 func (k *innerR1PrivateKey) sign(hash []byte) (out Signature, err error) {
-	return out, fmt.Errorf("R1 not supported")
+	if len(hash) != 32 {
+		return out, fmt.Errorf("hash should be 32 bytes")
+	}
+
+	content, err := signR1Compact(k.privKey, hash)
+	if err != nil {
+		return out, err
+	}
+
+	return Signature{Curve: CurveR1, Content: content, inner: &innerR1Signature{}}, nil
 }
 
 func (k *innerR1PrivateKey) string() string {
-	return "PVT_R1_PLACE_HOLDER"
+	rawPrivKey := k.privKey.D.FillBytes(make([]byte, 32))
+	checksum := ripemd160checksum(rawPrivKey, CurveR1)
+	return PrivateKeyPrefix + CurveR1.StringPrefix() + base58.Encode(append(rawPrivKey, checksum...))
+}
+
+// signR1Compact produces a compact signature like `nodeos` does, the
+// recovery ID byte (offset by 27 + 4, for a compressed public key)
+// followed by R and S, with S normalized to the lower half of the curve
+// order, as `nodeos` only accepts such canonical R1 signatures.
+func signR1Compact(privKey *ecdsa.PrivateKey, hash []byte) ([]byte, error) {
+	r, s, err := ecdsa.Sign(cryptorand.Reader, privKey, hash)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	params := privKey.Curve.Params()
+	if s.Cmp(new(big.Int).Rsh(params.N, 1)) > 0 {
+		s.Sub(params.N, s)
+	}
+
+	content := make([]byte, 65)
+	r.FillBytes(content[1:33])
+	s.FillBytes(content[33:65])
+
+	for recoveryID := 0; recoveryID < 4; recoveryID++ {
+		key, err := recoverKeyFromSignature(privKey.Curve, r, s, hash, recoveryID, false)
+		if err != nil {
+			continue
+		}
+
+		if key.X.Cmp(privKey.X) == 0 && key.Y.Cmp(privKey.Y) == 0 {
+			content[0] = byte(27 + 4 + recoveryID)
+			return content, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to find the recovery id of the signature")
 }
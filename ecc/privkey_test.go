@@ -28,3 +28,26 @@ func Test_NewPrivateKey(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewPrivateKeyFromData(t *testing.T) {
+	privKey, err := NewPrivateKey("5HxXwim9PAZZctKJG7Sk6mURD6UXW2hkjDKqnNZu9WYjKD6fF5a")
+	require.NoError(t, err)
+
+	data := append([]byte{byte(CurveK1)}, privKey.inner.(*innerK1PrivateKey).privKey.Serialize()...)
+	fromData, err := NewPrivateKeyFromData(data)
+	require.NoError(t, err)
+	assert.Equal(t, privKey.String(), fromData.String())
+
+	_, err = NewPrivateKeyFromData(data[1:])
+	assert.Error(t, err)
+}
+
+func Test_NewPrivateKeyFromData_R1(t *testing.T) {
+	privKey, err := NewPrivateKey("PVT_R1_2o5WfMRU4dTp23pbcbP2yn5MumQzSMy3ayNQ31qi5nUfa2jdWC")
+	require.NoError(t, err)
+
+	data := append([]byte{byte(CurveR1)}, privKey.inner.(*innerR1PrivateKey).privKey.D.FillBytes(make([]byte, 32))...)
+	fromData, err := NewPrivateKeyFromData(data)
+	require.NoError(t, err)
+	assert.Equal(t, privKey.String(), fromData.String())
+}
@@ -1,28 +1,187 @@
 package ecc
 
 import (
-	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"sync"
 )
 
+// WebAuthn authenticator data flags.
+const (
+	webAuthnFlagUserPresent  = 0x01
+	webAuthnFlagUserVerified = 0x04
+)
+
+// WA public key user presence, as encoded in `PUB_WA_` keys.
+const (
+	WAUserPresenceNone     = byte(0)
+	WAUserPresencePresent  = byte(1)
+	WAUserPresenceVerified = byte(2)
+)
+
+// WebAuthnAuthenticator is a software WebAuthn authenticator, holding
+// the P-256 credential key of a relying party. It produces the `SIG_WA_`
+// signatures a browser would for a `navigator.credentials.get()` call
+// using the digest to sign as challenge, to test WA-permissioned
+// accounts without a hardware authenticator.
+//
+// Its `PrivateKey` can be added to a `KeyBag` like any other key.
+type WebAuthnAuthenticator struct {
+	// RPID is the relying party ID, the host the credential is scoped to.
+	RPID string
+	// Origin is the origin put in the client data, `https://` followed by
+	// the RPID when empty. `nodeos` derives the RPID from the origin,
+	// ignoring its port and path.
+	Origin string
+	// UserVerified sets the user verified flag on top of the user present
+	// one, making the public key a "verified" one.
+	UserVerified bool
+
+	key *ecdsa.PrivateKey
+
+	lock      sync.Mutex
+	signCount uint32
+}
+
+// NewWebAuthnAuthenticator returns an authenticator for the relying
+// party `rpID` using `key`, an R1 private key, as credential key.
+func NewWebAuthnAuthenticator(key *PrivateKey, rpID string) (*WebAuthnAuthenticator, error) {
+	inner, ok := key.inner.(*innerR1PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("WebAuthn credential key must be an R1 key, got %s", key.Curve)
+	}
+
+	if rpID == "" {
+		return nil, fmt.Errorf("relying party ID is required")
+	}
+
+	return &WebAuthnAuthenticator{RPID: rpID, key: inner.privKey}, nil
+}
+
+// NewRandomWebAuthnAuthenticator returns an authenticator for the
+// relying party `rpID` with a new random credential key.
+func NewRandomWebAuthnAuthenticator(rpID string) (*WebAuthnAuthenticator, error) {
+	key, err := NewRandomR1PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return NewWebAuthnAuthenticator(key, rpID)
+}
+
+// PublicKey returns the `PUB_WA_` public key of the credential, which
+// is what WA-permissioned accounts list in their authorities.
+func (a *WebAuthnAuthenticator) PublicKey() PublicKey {
+	presence := WAUserPresencePresent
+	if a.UserVerified {
+		presence = WAUserPresenceVerified
+	}
+
+	content := elliptic.MarshalCompressed(a.key.Curve, a.key.X, a.key.Y)
+	content = append(content, presence)
+	content = appendUvarint(content, uint64(len(a.RPID)))
+	content = append(content, a.RPID...)
+
+	return PublicKey{Curve: CurveWA, Content: content, inner: &innerWAPublicKey{}}
+}
+
+// Sign produces a `SIG_WA_` signature of `digest`, a 32 bytes SHA256
+// hash like a transaction's signing digest. The digest is the
+// challenge of the client data, and the credential key signs the
+// authenticator data followed by the SHA256 hash of the client data.
+func (a *WebAuthnAuthenticator) Sign(digest []byte) (out Signature, err error) {
+	if len(digest) != 32 {
+		return out, fmt.Errorf("hash should be 32 bytes")
+	}
+
+	clientData, err := json.Marshal(&webAuthnClientData{
+		Type:      "webauthn.get",
+		Challenge: base64.RawURLEncoding.EncodeToString(digest),
+		Origin:    a.origin(),
+	})
+	if err != nil {
+		return out, fmt.Errorf("client data: %w", err)
+	}
+
+	authenticatorData := a.authenticatorData()
+	clientDataHash := sha256.Sum256(clientData)
+	signedDigest := sha256.Sum256(append(authenticatorData, clientDataHash[:]...))
+
+	content, err := signR1Compact(a.key, signedDigest[:])
+	if err != nil {
+		return out, err
+	}
+
+	content = appendUvarint(content, uint64(len(authenticatorData)))
+	content = append(content, authenticatorData...)
+	content = appendUvarint(content, uint64(len(clientData)))
+	content = append(content, clientData...)
+
+	return Signature{Curve: CurveWA, Content: content, inner: &innerWASignature{}}, nil
+}
+
+// PrivateKey returns a WA private key signing with the authenticator,
+// to use in a `KeyBag`. WA private keys have no string format of their
+// own, its `String` is the R1 credential key.
+func (a *WebAuthnAuthenticator) PrivateKey() *PrivateKey {
+	return &PrivateKey{Curve: CurveWA, inner: &innerWAPrivateKey{authenticator: a}}
+}
+
+func (a *WebAuthnAuthenticator) origin() string {
+	if a.Origin != "" {
+		return a.Origin
+	}
+	return "https://" + a.RPID
+}
+
+// authenticatorData returns the RP ID hash, the flags and the signature
+// counter, incremented on each signature like authenticators do.
+func (a *WebAuthnAuthenticator) authenticatorData() []byte {
+	a.lock.Lock()
+	a.signCount++
+	signCount := a.signCount
+	a.lock.Unlock()
+
+	flags := byte(webAuthnFlagUserPresent)
+	if a.UserVerified {
+		flags |= webAuthnFlagUserVerified
+	}
+
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	data := make([]byte, 37)
+	copy(data, rpIDHash[:])
+	data[32] = flags
+	binary.BigEndian.PutUint32(data[33:], signCount)
+	return data
+}
+
+type webAuthnClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+func appendUvarint(data []byte, value uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(data, buf[:binary.PutUvarint(buf, value)]...)
+}
+
 type innerWAPrivateKey struct {
+	authenticator *WebAuthnAuthenticator
 }
 
 func (k *innerWAPrivateKey) publicKey() PublicKey {
-	//TODO: Finish WA support here - for now we return bogus key
-	var pubKeyData []byte
-	pubKeyData = append(pubKeyData, 0x03)                           // ySign (either 0x02 or 0x03)
-	pubKeyData = append(pubKeyData, bytes.Repeat([]byte{0}, 32)...) // X
-	pubKeyData = append(pubKeyData, 0x02)                           // Flags Presence (2 Verified, 1 Present, 0 None)
-	pubKeyData = append(pubKeyData, 0x00)                           // Relay Party ID (String encoded, i.e. varuint32 length + characters)
-
-	return PublicKey{Curve: CurveWA, Content: pubKeyData, inner: &innerWAPublicKey{}}
+	return k.authenticator.PublicKey()
 }
 
 func (k *innerWAPrivateKey) sign(hash []byte) (out Signature, err error) {
-	return out, fmt.Errorf("WA not supported")
+	return k.authenticator.Sign(hash)
 }
 
 func (k *innerWAPrivateKey) string() string {
-	return "PVT_WA_PLACE_HOLDER"
+	return (&innerR1PrivateKey{privKey: k.authenticator.key}).string()
 }
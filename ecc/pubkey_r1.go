@@ -1,7 +1,9 @@
 package ecc
 
 import (
+	"crypto/elliptic"
 	"fmt"
+	"math/big"
 
 	"github.com/eoscanada/eos-go/btcsuite/btcd/btcec"
 )
@@ -14,12 +16,21 @@ func newInnerR1PublicKey() innerPublicKey {
 }
 
 func (p *innerR1PublicKey) key(content []byte) (*btcec.PublicKey, error) {
-	key, err := btcec.ParsePubKey(content, btcec.S256())
+	if len(content) != 33 || (content[0] != 0x02 && content[0] != 0x03) {
+		return nil, fmt.Errorf("expected compressed public key format, expecting 0x02 or 0x03 followed by 32 bytes")
+	}
+
+	X := new(big.Int).SetBytes(content[1:33])
+	Y, err := decompressPoint(X, content[0] == 0x03)
 	if err != nil {
-		return nil, fmt.Errorf("parsePubKey: %w", err)
+		return nil, fmt.Errorf("unable to decompress compressed public key material: %w", err)
 	}
 
-	return key, nil
+	return &btcec.PublicKey{
+		Curve: elliptic.P256(),
+		X:     X,
+		Y:     Y,
+	}, nil
 }
 
 func (p *innerR1PublicKey) prefix() string {
@@ -1,8 +1,12 @@
 package ecc
 
 import (
+	"bytes"
+	"crypto/elliptic"
 	"fmt"
+	"math/big"
 
+	"github.com/eoscanada/eos-go/btcsuite/btcd/btcec"
 	"github.com/eoscanada/eos-go/btcsuite/btcutil/base58"
 )
 
@@ -13,12 +17,55 @@ func newInnerR1Signature() innerSignature {
 	return &innerR1Signature{}
 }
 
+// verify checks the signature against the pubKey by recovering the
+// public key that produced it, the way `nodeos` does. `hash` is a
+// sha256 hash of the payload to verify.
 func (s innerR1Signature) verify(content []byte, hash []byte, pubKey PublicKey) bool {
-	return false
+	if pubKey.Curve != CurveR1 {
+		return false
+	}
+
+	recoveredKey, err := s.publicKey(content, hash)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(recoveredKey.Content, pubKey.Content)
+}
+
+func (s innerR1Signature) publicKey(content []byte, hash []byte) (out PublicKey, err error) {
+	if len(content) != 65 {
+		return out, fmt.Errorf("R1 signature should be 65 bytes, got %d", len(content))
+	}
+
+	key, err := recoverR1Compact(content, hash)
+	if err != nil {
+		return out, err
+	}
+
+	return PublicKey{
+		Curve:   CurveR1,
+		Content: elliptic.MarshalCompressed(key.Curve, key.X, key.Y),
+		inner:   &innerR1PublicKey{},
+	}, nil
 }
 
-func (s *innerR1Signature) publicKey(content []byte, hash []byte) (out PublicKey, err error) {
-	return out, fmt.Errorf("R1 not supported")
+// recoverR1Compact recovers the public key of a compact R1 signature,
+// as produced by `signR1Compact`.
+func recoverR1Compact(content []byte, hash []byte) (*btcec.PublicKey, error) {
+	if content[0] < 27 || content[0] >= 35 {
+		return nil, fmt.Errorf("invalid R1 signature recovery id %d", content[0])
+	}
+
+	R := new(big.Int).SetBytes(content[1:33])
+	S := new(big.Int).SetBytes(content[33:65])
+	iteration := int((content[0] - 27) & ^byte(4))
+
+	key, err := recoverKeyFromSignature(elliptic.P256(), R, S, hash, iteration, false)
+	if err != nil {
+		return nil, fmt.Errorf("recover public key: %w", err)
+	}
+	return key, nil
 }
 
 func (s innerR1Signature) string(content []byte) string {
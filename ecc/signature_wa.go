@@ -1,10 +1,16 @@
 package ecc
 
 import (
-	"crypto/ecdsa"
+	"bytes"
 	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/eoscanada/eos-go/btcsuite/btcd/btcec"
 	"github.com/eoscanada/eos-go/btcsuite/btcutil/base58"
@@ -17,50 +23,111 @@ func newInnerWASignature() innerSignature {
 	return &innerWASignature{}
 }
 
+// verify checks the signature against the pubKey by recovering the
+// public key that produced it, along with its user presence and
+// relying party ID, the way `nodeos` does. `hash` is a sha256 hash of
+// the payload to verify, the challenge of the client data.
 func (s innerWASignature) verify(content []byte, hash []byte, pubKey PublicKey) bool {
-	key, err := pubKey.Key()
-	if err != nil {
-		// FIXME: How to log that to the other world?
+	if pubKey.Curve != CurveWA {
 		return false
 	}
 
-	R := new(big.Int).SetBytes(content[1:33])
-	S := new(big.Int).SetBytes(content[33:65])
+	recoveredKey, err := s.publicKey(content, hash)
+	if err != nil {
+		return false
+	}
 
-	return ecdsa.Verify(
-		key.ToECDSA(),
-		hash,
-		R,
-		S,
-	)
+	return bytes.Equal(recoveredKey.Content, pubKey.Content)
 }
 
 func (s *innerWASignature) publicKey(content []byte, hash []byte) (out PublicKey, err error) {
-	// This is the start of the algorithm to extract the PublicKey from a SIG_WA signature.
-	// This was extracted from the `btcec` package which works on a different curve settings.
-	// This is untested, but from my (small) comprehension of Elliptic Curves, this should
-	// still work for ECDSA P-256 curves.
-	//
-	// However, one remaining step is re-construction of the full WA public key material
-	// which contains more than just the X and Y points in the WA case on EOSIO. Indeed, the
-	// key material also contain the flag presence and the relay party ID in string format.
-	// This information are presented in the signature `content` after the R and S values of
-	// the actual signature. But they are encoded in `cbor` encoding. To finish this, we need
-	// to:
-	//   1- Extract the attestation object from the signature
-	//   2- Extract the client data JSON object from the signature
-	//   3- Extract the user presence flag from the attestation object, need to be decoded against cbor algorithm first
-	//   4- Extract the relay party ID from the client data JSON
-	//   5- Embed everything into the proper WA public key encoding scheme.
-
-	// R := new(big.Int).SetBytes(content[1:33])
-	// S := new(big.Int).SetBytes(content[33:65])
-
-	// iteration := int((content[0] - 27) & ^byte(4))
-
-	// key, err := recoverKeyFromSignature(elliptic.P256(), R, S, hash, iteration, false)
-
-	return out, errors.New("WA not supported")
+	if len(content) < 65 {
+		return out, fmt.Errorf("WA signature should be at least 65 bytes, got %d", len(content))
+	}
+
+	authenticatorData, rest, err := readWAField(content[65:])
+	if err != nil {
+		return out, fmt.Errorf("authenticator data: %w", err)
+	}
+
+	clientData, _, err := readWAField(rest)
+	if err != nil {
+		return out, fmt.Errorf("client data: %w", err)
+	}
+
+	if len(authenticatorData) < 37 {
+		return out, fmt.Errorf("authenticator data should be at least 37 bytes, got %d", len(authenticatorData))
+	}
+
+	var client webAuthnClientData
+	if err := json.Unmarshal(clientData, &client); err != nil {
+		return out, fmt.Errorf("client data: %w", err)
+	}
+
+	if client.Type != "webauthn.get" {
+		return out, fmt.Errorf("client data type should be %q, got %q", "webauthn.get", client.Type)
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(client.Challenge, "="))
+	if err != nil {
+		return out, fmt.Errorf("client data challenge: %w", err)
+	}
+
+	if !bytes.Equal(challenge, hash) {
+		return out, fmt.Errorf("client data challenge does not match the signed hash")
+	}
+
+	if !strings.HasPrefix(client.Origin, "https://") {
+		return out, fmt.Errorf("client data origin %q is not https", client.Origin)
+	}
+
+	rpID := strings.TrimPrefix(client.Origin, "https://")
+	if index := strings.IndexAny(rpID, ":/"); index >= 0 {
+		rpID = rpID[:index]
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	if !bytes.Equal(rpIDHash[:], authenticatorData[:32]) {
+		return out, fmt.Errorf("authenticator data relying party ID hash does not match origin %q", client.Origin)
+	}
+
+	presence := WAUserPresenceNone
+	if authenticatorData[32]&webAuthnFlagUserVerified != 0 {
+		presence = WAUserPresenceVerified
+	} else if authenticatorData[32]&webAuthnFlagUserPresent != 0 {
+		presence = WAUserPresencePresent
+	}
+
+	clientDataHash := sha256.Sum256(clientData)
+	signedDigest := sha256.Sum256(append(append([]byte{}, authenticatorData...), clientDataHash[:]...))
+
+	key, err := recoverR1Compact(content[:65], signedDigest[:])
+	if err != nil {
+		return out, err
+	}
+
+	keyContent := elliptic.MarshalCompressed(key.Curve, key.X, key.Y)
+	keyContent = append(keyContent, presence)
+	keyContent = appendUvarint(keyContent, uint64(len(rpID)))
+	keyContent = append(keyContent, rpID...)
+
+	return PublicKey{Curve: CurveWA, Content: keyContent, inner: &innerWAPublicKey{}}, nil
+}
+
+// readWAField reads a size-prefixed field of a WA signature, returning
+// the field and the data following it.
+func readWAField(data []byte) (field []byte, rest []byte, err error) {
+	size, read := binary.Uvarint(data)
+	if read <= 0 {
+		return nil, nil, fmt.Errorf("invalid size")
+	}
+
+	data = data[read:]
+	if uint64(len(data)) < size {
+		return nil, nil, fmt.Errorf("requires %d bytes, remaining %d", size, len(data))
+	}
+
+	return data[:size], data[size:], nil
 }
 
 func (s innerWASignature) string(content []byte) string {
@@ -82,7 +149,7 @@ func (s innerWASignature) signatureMaterialSize() *int {
 // format and thus we match bitcoind's behaviour here.
 //
 // This was copied straight (with slight modifications) from `btcec.recoverKeySignature`
-// seems it looks the algorithm is valid for all Elliptic curves. Not tested yet.
+// seems it looks the algorithm is valid for all Elliptic curves.
 func recoverKeyFromSignature(curve elliptic.Curve, R, S *big.Int, msg []byte, iter int, doChecks bool) (*btcec.PublicKey, error) {
 	// 1.1 x = (n * i) + r
 	Rx := new(big.Int).Mul(curve.Params().N,
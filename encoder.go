@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -35,9 +36,10 @@ func MarshalBinary(v interface{}) ([]byte, error) {
 // Encoder implements the EOS packing, similar to FC_BUFFER
 // --------------------------------------------------------------
 type Encoder struct {
-	output io.Writer
-	Order  binary.ByteOrder
-	count  int
+	output   io.Writer
+	Order    binary.ByteOrder
+	count    int
+	registry *Registry
 }
 
 func NewEncoder(w io.Writer) *Encoder {
@@ -48,6 +50,19 @@ func NewEncoder(w io.Writer) *Encoder {
 	}
 }
 
+// SetRegistry sets the registry used to encode the JSON data of
+// actions, `DefaultRegistry` being used when nil.
+func (e *Encoder) SetRegistry(registry *Registry) {
+	e.registry = registry
+}
+
+func (e *Encoder) actionRegistry() *Registry {
+	if e.registry == nil {
+		return DefaultRegistry
+	}
+	return e.registry
+}
+
 func (e *Encoder) writeName(name Name) error {
 	val, err := StringToName(string(name))
 	if err != nil {
@@ -115,6 +130,8 @@ func (e *Encoder) Encode(v interface{}) (err error) {
 		return e.writeFloat32(cv)
 	case float64:
 		return e.writeFloat64(cv)
+	case Float64:
+		return e.writeFloat64(float64(cv))
 	case Varint32:
 		return e.writeVarInt32(int32(cv))
 	case Uint128:
@@ -162,10 +179,21 @@ func (e *Encoder) Encode(v interface{}) (err error) {
 		return e.writeUint64(uint64(cv))
 	case Asset:
 		return e.writeAsset(cv)
+	case Action:
+		return e.writeAction(&cv)
+	case *Action:
+		if cv == nil {
+			return fmt.Errorf("encoding a nil action")
+		}
+		return e.writeAction(cv)
 	case ActionData:
 		return e.writeActionData(cv)
 	case *ActionData:
 		return e.writeActionData(*cv)
+	case TransactionWithID:
+		return e.writeTransactionWithID(cv)
+	case *TransactionWithID:
+		return e.writeTransactionWithID(*cv)
 	case *Packet:
 		return e.writeBlockP2PMessageEnvelope(*cv)
 	case TimePoint:
@@ -174,6 +202,11 @@ func (e *Encoder) Encode(v interface{}) (err error) {
 		return e.writeUint32(uint32(cv))
 	case nil:
 	default:
+		// Pointers, like the ones of optional fields, encode the value
+		// they point to, which can be of one of the types above.
+		if pv := reflect.ValueOf(v); pv.Kind() == reflect.Ptr && !pv.IsNil() {
+			return e.Encode(pv.Elem().Interface())
+		}
 
 		rv := reflect.Indirect(reflect.ValueOf(v))
 		t := rv.Type()
@@ -616,7 +649,66 @@ func (e *Encoder) writeBlockP2PMessageEnvelope(envelope Packet) (err error) {
 	return
 }
 
+func (e *Encoder) writeAction(action *Action) (err error) {
+	if err := e.Encode(action.Account); err != nil {
+		return err
+	}
+	if err := e.Encode(action.Name); err != nil {
+		return err
+	}
+	if err := e.Encode(action.Authorization); err != nil {
+		return err
+	}
+
+	var jsonData json.RawMessage
+	switch data := action.ActionData.Data.(type) {
+	case json.RawMessage:
+		jsonData = data
+	case map[string]interface{}:
+		if jsonData, err = json.Marshal(data); err != nil {
+			return fmt.Errorf("marshal action data: %w", err)
+		}
+	default:
+		return e.writeActionData(action.ActionData)
+	}
+
+	// The original bytes of JSON data, decoded with an ABI or coming
+	// from the API, are written as is, the ABI encoding of the JSON could
+	// differ from them (like trailing bytes ignored by the contract).
+	// Without them, the JSON is encoded with the ABI of the contract.
+	if len(action.ActionData.HexData) > 0 {
+		return e.writeByteArray(action.ActionData.HexData)
+	}
+
+	raw, err := e.actionRegistry().encodeActionData(action.Account, action.Name, jsonData)
+	if err != nil {
+		return err
+	}
+	return e.writeByteArray(raw)
+}
+
+// writeTransactionWithID writes the `transaction_id_type` or
+// `packed_transaction` variant of a transaction receipt, the packed
+// transaction when present.
+func (e *Encoder) writeTransactionWithID(trx TransactionWithID) error {
+	if trx.Packed == nil {
+		if err := e.writeByte(0); err != nil {
+			return err
+		}
+		return e.writeChecksum256(trx.ID)
+	}
+
+	if err := e.writeByte(1); err != nil {
+		return err
+	}
+	return e.Encode(trx.Packed)
+}
+
 func (e *Encoder) writeActionData(actionData ActionData) (err error) {
+	if _, isJSON := actionData.Data.(json.RawMessage); isJSON {
+		return e.writeByteArray(actionData.HexData)
+	}
+
 	if actionData.Data != nil {
 		//if reflect.TypeOf(actionData.Data) == reflect.TypeOf(&ActionData{}) {
 		//	log.Fatal("pas cool")
@@ -6,6 +6,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/eoscanada/eos-go/ecc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -88,3 +89,51 @@ func Test_OptionalPointerToPrimitiveType(t *testing.T) {
 
 	assert.Equal(t, []byte{0x1, 0xa, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0}, out)
 }
+
+func Test_OptionalPointerToNamedTypes(t *testing.T) {
+	type test struct {
+		Memo  *string        `eos:"optional"`
+		Name  *Name          `eos:"optional"`
+		Count *uint32        `eos:"optional"`
+		Key   *ecc.PublicKey `eos:"optional"`
+	}
+
+	out, err := MarshalBinary(test{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x0, 0x0, 0x0, 0x0}, out)
+
+	memo, name, count := "hi", Name("alice"), uint32(7)
+	key, err := ecc.NewPublicKey("EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV")
+	require.NoError(t, err)
+
+	out, err = MarshalBinary(test{Memo: &memo, Name: &name, Count: &count, Key: &key})
+	require.NoError(t, err)
+	assert.Equal(t, ""+
+		"01"+"026869"+ // hi
+		"01"+"0000000000855c34"+ // alice
+		"01"+"07000000"+
+		"01"+"0002c0ded2bc1f1305fb0faac5e6c03ee3a1924234985427b6167ca569d13df435cf",
+		hex.EncodeToString(out))
+
+	var decoded test
+	require.NoError(t, UnmarshalBinary(out, &decoded))
+	assert.Equal(t, memo, *decoded.Memo)
+	assert.Equal(t, name, *decoded.Name)
+	assert.Equal(t, count, *decoded.Count)
+	assert.Equal(t, key.String(), decoded.Key.String())
+}
+
+func TestEncoder_TransactionWithID(t *testing.T) {
+	id := Checksum256(bytes.Repeat([]byte{0xaa}, 32))
+	out, err := MarshalBinary(TransactionWithID{ID: id})
+	require.NoError(t, err)
+	assert.Equal(t, append([]byte{0x00}, id...), out)
+
+	packed := &PackedTransaction{
+		Compression:       CompressionNone,
+		PackedTransaction: HexBytes{0x01, 0x02},
+	}
+	out, err = MarshalBinary(&TransactionWithID{Packed: packed})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x00, 0x00, 0x00, 0x02, 0x01, 0x02}, out)
+}
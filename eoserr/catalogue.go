@@ -0,0 +1,138 @@
+package eoserr
+
+import "errors"
+
+// The chain exceptions are generated from the exceptions header of a
+// `nodeos` source tree, set in `NODEOS_SOURCE`.
+//go:generate go run genchainerrors.go -input ${NODEOS_SOURCE}/libraries/chain/include/eosio/chain/exceptions.hpp
+
+type errorInfo struct {
+	err    Error
+	parent Error
+	what   string
+}
+
+var (
+	errorsByCode = map[int]*errorInfo{}
+	errorsByName = map[string]*errorInfo{}
+	childrenOf   = map[int][]Error{}
+)
+
+// newError registers an error of the catalogue, `parent` being the
+// zero `Error` for root errors.
+func newError(parent Error, name string, code int, what string) Error {
+	e := Error{Name: name, Code: code}
+
+	info := &errorInfo{err: e, parent: parent, what: what}
+	errorsByCode[code] = info
+	errorsByName[name] = info
+	if parent.Code != 0 {
+		childrenOf[parent.Code] = append(childrenOf[parent.Code], e)
+	}
+
+	return e
+}
+
+// ByCode returns the error of the catalogue with the given code, as
+// found in `APIError.ErrorStruct.Code`.
+func ByCode(code int) (Error, bool) {
+	info, found := errorsByCode[code]
+	if !found {
+		return Error{}, false
+	}
+	return info.err, true
+}
+
+// ByName returns the error of the catalogue with the given name, as
+// found in `APIError.ErrorStruct.Name`.
+func ByName(name string) (Error, bool) {
+	info, found := errorsByName[name]
+	if !found {
+		return Error{}, false
+	}
+	return info.err, true
+}
+
+// What returns the description `nodeos` gives to the error.
+func (e Error) What() string {
+	if info, found := errorsByCode[e.Code]; found {
+		return info.what
+	}
+	return ""
+}
+
+// Parent returns the error `e` derives from, if any.
+func (e Error) Parent() (Error, bool) {
+	info, found := errorsByCode[e.Code]
+	if !found || info.parent.Code == 0 {
+		return Error{}, false
+	}
+	return info.parent, true
+}
+
+// Children returns the errors directly deriving from `e`.
+func (e Error) Children() []Error {
+	return childrenOf[e.Code]
+}
+
+// IsA returns whether `e` is `other` or derives from it.
+func (e Error) IsA(other Error) bool {
+	for current, ok := e, true; ok; current, ok = current.Parent() {
+		if current.Code == other.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// Is makes `errors.Is(err, eoserr.ErrResourceExhaustedException)` true
+// for any error deriving from it.
+func (e Error) Is(target error) bool {
+	var other Error
+	if !errors.As(target, &other) {
+		return false
+	}
+	return e.IsA(other)
+}
+
+// IsTransactionError returns whether `err` is, or wraps, a transaction
+// error (3040000 range).
+func IsTransactionError(err error) bool {
+	return errors.Is(err, ErrTransactionException)
+}
+
+// IsActionValidation returns whether `err` is, or wraps, an action
+// validation error (3050000 range), like `eosio_assert` failures.
+func IsActionValidation(err error) bool {
+	return errors.Is(err, ErrActionValidateException)
+}
+
+// IsResourceExhausted returns whether `err` is, or wraps, a resource
+// exhaustion error (3080000 range), like CPU, NET or RAM usage exceeded.
+func IsResourceExhausted(err error) bool {
+	return errors.Is(err, ErrResourceExhaustedException)
+}
+
+// IsAuthorization returns whether `err` is, or wraps, an authorization
+// error (3090000 range).
+func IsAuthorization(err error) bool {
+	return errors.Is(err, ErrAuthorizationException)
+}
+
+// IsWASM returns whether `err` is, or wraps, a WASM error (3070000
+// range).
+func IsWASM(err error) bool {
+	return errors.Is(err, ErrWASMException)
+}
+
+// IsContract returns whether `err` is, or wraps, a contract error
+// (3160000 range).
+func IsContract(err error) bool {
+	return errors.Is(err, ErrContractException)
+}
+
+// IsABI returns whether `err` is, or wraps, an ABI error (3015000
+// range).
+func IsABI(err error) bool {
+	return errors.Is(err, ErrABIException)
+}
@@ -0,0 +1,53 @@
+package eoserr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_Hierarchy(t *testing.T) {
+	parent, ok := ErrLeewayDeadlineException.Parent()
+	assert.True(t, ok)
+	assert.Equal(t, ErrDeadlineException, parent)
+
+	assert.True(t, ErrLeewayDeadlineException.IsA(ErrResourceExhaustedException))
+	assert.True(t, ErrLeewayDeadlineException.IsA(ErrChainException))
+	assert.False(t, ErrLeewayDeadlineException.IsA(ErrAuthorizationException))
+
+	_, ok = ErrChainException.Parent()
+	assert.False(t, ok)
+
+	assert.Contains(t, ErrResourceExhaustedException.Children(), ErrTxCPUUsageExceeded)
+	assert.Equal(t, "Transaction took too long", ErrDeadlineException.What())
+}
+
+func TestError_Is(t *testing.T) {
+	err := fmt.Errorf("push transaction: %w", ErrTxCPUUsageExceeded)
+
+	assert.True(t, errors.Is(err, ErrTxCPUUsageExceeded))
+	assert.True(t, errors.Is(err, ErrResourceExhaustedException))
+	assert.False(t, errors.Is(err, ErrTxNetUsageExceeded))
+
+	assert.True(t, IsResourceExhausted(err))
+	assert.False(t, IsAuthorization(err))
+	assert.True(t, IsAuthorization(ErrUnsatisfiedAuthorization))
+	assert.True(t, IsActionValidation(ErrEOSIOAssertMessageException))
+}
+
+func TestByCode(t *testing.T) {
+	e, ok := ByCode(3080004)
+	assert.True(t, ok)
+	assert.Equal(t, ErrTxCPUUsageExceeded, e)
+
+	e, ok = ByName("tx_cpu_usage_exceeded")
+	assert.True(t, ok)
+	assert.Equal(t, ErrTxCPUUsageExceeded, e)
+
+	_, ok = ByCode(42)
+	assert.False(t, ok)
+
+	assert.Len(t, errorsByName, len(errorsByCode), "names and codes should be unique")
+}
@@ -0,0 +1,247 @@
+// Code generated by genchainerrors.go from exceptions.hpp; DO NOT EDIT.
+
+// Chain exceptions, mirroring `libraries/chain/include/eosio/chain/exceptions.hpp`
+// of `nodeos`, in the same order so parents are declared first.
+
+package eoserr
+
+var ErrChainException = newError(Error{}, "chain_exception", 3000000, "blockchain exception")
+
+var ErrChainTypeException = newError(ErrChainException, "chain_type_exception", 3010000, "chain type exception")
+var ErrNameTypeException = newError(ErrChainTypeException, "name_type_exception", 3010001, "Invalid name")
+var ErrPublicKeyTypeException = newError(ErrChainTypeException, "public_key_type_exception", 3010002, "Invalid public key")
+var ErrPrivateKeyTypeException = newError(ErrChainTypeException, "private_key_type_exception", 3010003, "Invalid private key")
+var ErrAuthorityTypeException = newError(ErrChainTypeException, "authority_type_exception", 3010004, "Invalid authority")
+var ErrActionTypeException = newError(ErrChainTypeException, "action_type_exception", 3010005, "Invalid action")
+var ErrTransactionTypeException = newError(ErrChainTypeException, "transaction_type_exception", 3010006, "Invalid transaction")
+var ErrABITypeException = newError(ErrChainTypeException, "abi_type_exception", 3010007, "Invalid ABI")
+var ErrBlockIDTypeException = newError(ErrChainTypeException, "block_id_type_exception", 3010008, "Invalid block ID")
+var ErrTransactionIDTypeException = newError(ErrChainTypeException, "transaction_id_type_exception", 3010009, "Invalid transaction ID")
+var ErrPackedTransactionTypeException = newError(ErrChainTypeException, "packed_transaction_type_exception", 3010010, "Invalid packed transaction")
+var ErrAssetTypeException = newError(ErrChainTypeException, "asset_type_exception", 3010011, "Invalid asset")
+var ErrChainIDTypeException = newError(ErrChainTypeException, "chain_id_type_exception", 3010012, "Invalid chain ID")
+var ErrFixedKeyTypeException = newError(ErrChainTypeException, "fixed_key_type_exception", 3010013, "Invalid fixed key")
+var ErrSymbolTypeException = newError(ErrChainTypeException, "symbol_type_exception", 3010014, "Invalid symbol")
+var ErrUnactivatedKeyType = newError(ErrChainTypeException, "unactivated_key_type", 3010015, "Key type is not a currently activated type")
+var ErrUnactivatedSignatureType = newError(ErrChainTypeException, "unactivated_signature_type", 3010016, "Signature type is not a currently activated type")
+
+var ErrABIException = newError(ErrChainException, "abi_exception", 3015000, "ABI exception")
+var ErrABINotFoundException = newError(ErrABIException, "abi_not_found_exception", 3015001, "No ABI found")
+var ErrInvalidRicardianClauseException = newError(ErrABIException, "invalid_ricardian_clause_exception", 3015002, "Invalid Ricardian Clause")
+var ErrInvalidRicardianActionException = newError(ErrABIException, "invalid_ricardian_action_exception", 3015003, "Invalid Ricardian Action")
+var ErrInvalidTypeInsideABI = newError(ErrABIException, "invalid_type_inside_abi", 3015004, "The type defined in the ABI is invalid")
+var ErrDuplicateABITypeDefException = newError(ErrABIException, "duplicate_abi_type_def_exception", 3015005, "Duplicate type definition in the ABI")
+var ErrDuplicateABIStructDefException = newError(ErrABIException, "duplicate_abi_struct_def_exception", 3015006, "Duplicate struct definition in the ABI")
+var ErrDuplicateABIActionDefException = newError(ErrABIException, "duplicate_abi_action_def_exception", 3015007, "Duplicate action definition in the ABI")
+var ErrDuplicateABITableDefException = newError(ErrABIException, "duplicate_abi_table_def_exception", 3015008, "Duplicate table definition in the ABI")
+var ErrDuplicateABIErrMsgDefException = newError(ErrABIException, "duplicate_abi_err_msg_def_exception", 3015009, "Duplicate error message definition in the ABI")
+var ErrABISerializationDeadlineException = newError(ErrABIException, "abi_serialization_deadline_exception", 3015010, "ABI serialization time has exceeded the deadline")
+var ErrABIRecursionDepthException = newError(ErrABIException, "abi_recursion_depth_exception", 3015011, "ABI recursive definition has exceeded the max recursion depth")
+var ErrABICircularDefException = newError(ErrABIException, "abi_circular_def_exception", 3015012, "Circular definition is detected in the ABI")
+var ErrUnpackException = newError(ErrABIException, "unpack_exception", 3015013, "Unpack data exception")
+var ErrPackException = newError(ErrABIException, "pack_exception", 3015014, "Pack data exception")
+var ErrDuplicateABIVariantDefException = newError(ErrABIException, "duplicate_abi_variant_def_exception", 3015015, "Duplicate variant definition in the ABI")
+var ErrUnsupportedABIVersionException = newError(ErrABIException, "unsupported_abi_version_exception", 3015016, "ABI has an unsupported version")
+var ErrDuplicateABIActionResultsDefException = newError(ErrABIException, "duplicate_abi_action_results_def_exception", 3015017, "Duplicate action results definition in the ABI")
+var ErrDuplicateABIKVTableDefException = newError(ErrABIException, "duplicate_abi_kv_table_def_exception", 3015018, "Duplicate kv_table definition in the ABI")
+
+var ErrForkDatabaseException = newError(ErrChainException, "fork_database_exception", 3020000, "Fork database exception")
+var ErrForkDBBlockNotFound = newError(ErrForkDatabaseException, "fork_db_block_not_found", 3020001, "Block can not be found")
+
+var ErrBlockValidateException = newError(ErrChainException, "block_validate_exception", 3030000, "Block exception")
+var ErrUnlinkableBlockException = newError(ErrBlockValidateException, "unlinkable_block_exception", 3030001, "Unlinkable block")
+var ErrBlockTxOutputException = newError(ErrBlockValidateException, "block_tx_output_exception", 3030002, "Transaction outputs in block do not match transaction outputs from applying block")
+var ErrBlockConcurrencyException = newError(ErrBlockValidateException, "block_concurrency_exception", 3030003, "Block does not guarantee concurrent execution without conflicts")
+var ErrBlockLockException = newError(ErrBlockValidateException, "block_lock_exception", 3030004, "Shard locks in block are incorrect or mal-formed")
+var ErrBlockResourceExhausted = newError(ErrBlockValidateException, "block_resource_exhausted", 3030005, "Block exhausted allowed resources")
+var ErrBlockTooOldException = newError(ErrBlockValidateException, "block_too_old_exception", 3030006, "Block is too old to push")
+var ErrBlockFromTheFuture = newError(ErrBlockValidateException, "block_from_the_future", 3030007, "Block is from the future")
+var ErrWrongSigningKey = newError(ErrBlockValidateException, "wrong_signing_key", 3030008, "Block is not signed with expected key")
+var ErrWrongProducer = newError(ErrBlockValidateException, "wrong_producer", 3030009, "Block is not signed by expected producer")
+var ErrInvalidBlockHeaderExtension = newError(ErrBlockValidateException, "invalid_block_header_extension", 3030010, "Invalid block header extension")
+var ErrIllFormedProtocolFeatureActivation = newError(ErrBlockValidateException, "ill_formed_protocol_feature_activation", 3030011, "Block includes an ill-formed protocol feature activation extension")
+var ErrInvalidBlockExtension = newError(ErrBlockValidateException, "invalid_block_extension", 3030012, "Invalid block extension")
+var ErrIllFormedAdditionalBlockSignaturesExtension = newError(ErrBlockValidateException, "ill_formed_additional_block_signatures_extension", 3030013, "Block includes an ill-formed additional block signature extension")
+
+var ErrTransactionException = newError(ErrChainException, "transaction_exception", 3040000, "Transaction exception")
+var ErrTxDecompressionError = newError(ErrTransactionException, "tx_decompression_error", 3040001, "Error decompressing transaction")
+var ErrTxNoAction = newError(ErrTransactionException, "tx_no_action", 3040002, "Transaction should have at least one normal action")
+var ErrTxNoAuths = newError(ErrTransactionException, "tx_no_auths", 3040003, "Transaction should have at least one required authority")
+var ErrCFAIrrelevantAuth = newError(ErrTransactionException, "cfa_irrelevant_auth", 3040004, "Context-free action should have no required authority")
+var ErrExpiredTxException = newError(ErrTransactionException, "expired_tx_exception", 3040005, "Expired Transaction")
+var ErrTxExpTooFarException = newError(ErrTransactionException, "tx_exp_too_far_exception", 3040006, "Transaction Expiration Too Far")
+var ErrInvalidRefBlockException = newError(ErrTransactionException, "invalid_ref_block_exception", 3040007, "Invalid Reference Block")
+var ErrTxDuplicate = newError(ErrTransactionException, "tx_duplicate", 3040008, "Duplicate transaction")
+var ErrDeferredTxDuplicate = newError(ErrTransactionException, "deferred_tx_duplicate", 3040009, "Duplicate deferred transaction")
+var ErrCFAInsideGeneratedTx = newError(ErrTransactionException, "cfa_inside_generated_tx", 3040010, "Context free action is not allowed inside generated transaction")
+var ErrTxNotFound = newError(ErrTransactionException, "tx_not_found", 3040011, "The transaction can not be found")
+var ErrTooManyTxAtOnce = newError(ErrTransactionException, "too_many_tx_at_once", 3040012, "Pushing too many transactions at once")
+var ErrTxTooBig = newError(ErrTransactionException, "tx_too_big", 3040013, "Transaction is too big")
+var ErrUnknownTransactionCompression = newError(ErrTransactionException, "unknown_transaction_compression", 3040014, "Unknown transaction compression")
+var ErrInvalidTransactionExtension = newError(ErrTransactionException, "invalid_transaction_extension", 3040015, "Invalid transaction extension")
+var ErrIllFormedDeferredTransactionGenerationContext = newError(ErrTransactionException, "ill_formed_deferred_transaction_generation_context", 3040016, "Transaction includes an ill-formed deferred transaction generation context extension")
+var ErrDisallowedTransactionExtensionsBadBlockException = newError(ErrTransactionException, "disallowed_transaction_extensions_bad_block_exception", 3040017, "Transaction includes disallowed extensions (invalid block)")
+var ErrTxResourceExhaustion = newError(ErrTransactionException, "tx_resource_exhaustion", 3040018, "Transaction exceeded transient resource limit")
+
+var ErrActionValidateException = newError(ErrChainException, "action_validate_exception", 3050000, "Action validate exception")
+var ErrAccountNameExistsException = newError(ErrActionValidateException, "account_name_exists_exception", 3050001, "Account name already exists")
+var ErrInvalidActionArgsException = newError(ErrActionValidateException, "invalid_action_args_exception", 3050002, "Invalid Action Arguments")
+var ErrEOSIOAssertMessageException = newError(ErrActionValidateException, "eosio_assert_message_exception", 3050003, "eosio_assert_message assertion failure")
+var ErrEOSIOAssertCodeException = newError(ErrActionValidateException, "eosio_assert_code_exception", 3050004, "eosio_assert_code assertion failure")
+var ErrActionNotFoundException = newError(ErrActionValidateException, "action_not_found_exception", 3050005, "Action can not be found")
+var ErrActionDataAndStructMismatch = newError(ErrActionValidateException, "action_data_and_struct_mismatch", 3050006, "Mismatch between action data and its struct")
+var ErrUnaccessibleAPI = newError(ErrActionValidateException, "unaccessible_api", 3050007, "Attempt to use unaccessible API")
+var ErrAbortCalled = newError(ErrActionValidateException, "abort_called", 3050008, "Abort Called")
+var ErrInlineActionTooBig = newError(ErrActionValidateException, "inline_action_too_big", 3050009, "Inline Action exceeds maximum size limit")
+var ErrUnauthorizedRAMUsageIncrease = newError(ErrActionValidateException, "unauthorized_ram_usage_increase", 3050010, "Action attempts to increase RAM usage of account without authorization")
+var ErrRestrictedErrorCodeException = newError(ErrActionValidateException, "restricted_error_code_exception", 3050011, "eosio_assert_code assertion failure uses restricted error code value")
+var ErrInlineActionTooBigNonprivileged = newError(ErrActionValidateException, "inline_action_too_big_nonprivileged", 3050012, "Inline action exceeds maximum size limit for a non-privileged account")
+var ErrActionReturnValueException = newError(ErrActionValidateException, "action_return_value_exception", 3050013, "action return value size too big")
+
+var ErrDatabaseException = newError(ErrChainException, "database_exception", 3060000, "Database exception")
+var ErrPermissionQueryException = newError(ErrDatabaseException, "permission_query_exception", 3060001, "Permission Query Exception")
+var ErrAccountQueryException = newError(ErrDatabaseException, "account_query_exception", 3060002, "Account Query Exception")
+var ErrContractTableQueryException = newError(ErrDatabaseException, "contract_table_query_exception", 3060003, "Contract Table Query Exception")
+var ErrContractQueryException = newError(ErrDatabaseException, "contract_query_exception", 3060004, "Contract Query Exception")
+var ErrBadDatabaseVersionException = newError(ErrDatabaseException, "bad_database_version_exception", 3060005, "Database is an unknown or unsupported version")
+var ErrGuardException = newError(ErrDatabaseException, "guard_exception", 3060100, "Guard Exception")
+var ErrDatabaseGuardException = newError(ErrGuardException, "database_guard_exception", 3060101, "Database usage is at unsafe levels")
+
+var ErrWASMException = newError(ErrChainException, "wasm_exception", 3070000, "WASM Exception")
+var ErrPageMemoryError = newError(ErrWASMException, "page_memory_error", 3070001, "Error in WASM page memory")
+var ErrWASMExecutionError = newError(ErrWASMException, "wasm_execution_error", 3070002, "Runtime Error Processing WASM")
+var ErrWASMSerializationError = newError(ErrWASMException, "wasm_serialization_error", 3070003, "Serialization Error Processing WASM")
+var ErrOverlappingMemoryError = newError(ErrWASMException, "overlapping_memory_error", 3070004, "memcpy with overlapping memory")
+var ErrBinaryenException = newError(ErrWASMException, "binaryen_exception", 3070005, "binaryen exceptions")
+
+var ErrResourceExhaustedException = newError(ErrChainException, "resource_exhausted_exception", 3080000, "Resource exhausted exception")
+var ErrRAMUsageExceeded = newError(ErrResourceExhaustedException, "ram_usage_exceeded", 3080001, "Account using more than allotted RAM usage")
+var ErrTxNetUsageExceeded = newError(ErrResourceExhaustedException, "tx_net_usage_exceeded", 3080002, "Transaction exceeded the current network usage limit imposed on the transaction")
+var ErrBlockNetUsageExceeded = newError(ErrResourceExhaustedException, "block_net_usage_exceeded", 3080003, "Transaction network usage is too much for the remaining allowable usage of the current block")
+var ErrTxCPUUsageExceeded = newError(ErrResourceExhaustedException, "tx_cpu_usage_exceeded", 3080004, "Transaction exceeded the current CPU usage limit imposed on the transaction")
+var ErrBlockCPUUsageExceeded = newError(ErrResourceExhaustedException, "block_cpu_usage_exceeded", 3080005, "Transaction CPU usage is too much for the remaining allowable usage of the current block")
+var ErrDeadlineException = newError(ErrResourceExhaustedException, "deadline_exception", 3080006, "Transaction took too long")
+var ErrGreylistNetUsageExceeded = newError(ErrResourceExhaustedException, "greylist_net_usage_exceeded", 3080007, "Transaction exceeded the current greylisted account network usage limit")
+var ErrGreylistCPUUsageExceeded = newError(ErrResourceExhaustedException, "greylist_cpu_usage_exceeded", 3080008, "Transaction exceeded the current greylisted account CPU usage limit")
+var ErrLeewayDeadlineException = newError(ErrDeadlineException, "leeway_deadline_exception", 3081001, "Transaction reached the deadline set due to leeway on account CPU limits")
+
+var ErrAuthorizationException = newError(ErrChainException, "authorization_exception", 3090000, "Authorization exception")
+var ErrTxDuplicateSig = newError(ErrAuthorizationException, "tx_duplicate_sig", 3090001, "Duplicate signature included")
+var ErrTxIrrelevantSig = newError(ErrAuthorizationException, "tx_irrelevant_sig", 3090002, "Irrelevant signature included")
+var ErrUnsatisfiedAuthorization = newError(ErrAuthorizationException, "unsatisfied_authorization", 3090003, "Provided keys, permissions, and delays do not satisfy declared authorizations")
+var ErrMissingAuthException = newError(ErrAuthorizationException, "missing_auth_exception", 3090004, "Missing required authority")
+var ErrIrrelevantAuthException = newError(ErrAuthorizationException, "irrelevant_auth_exception", 3090005, "Irrelevant authority included")
+var ErrInsufficientDelayException = newError(ErrAuthorizationException, "insufficient_delay_exception", 3090006, "Insufficient delay")
+var ErrInvalidPermission = newError(ErrAuthorizationException, "invalid_permission", 3090007, "Invalid Permission")
+var ErrUnlinkableMinPermissionAction = newError(ErrAuthorizationException, "unlinkable_min_permission_action", 3090008, "The action is not allowed to be linked with minimum permission")
+var ErrInvalidParentPermission = newError(ErrAuthorizationException, "invalid_parent_permission", 3090009, "The parent permission is invalid")
+
+var ErrMiscException = newError(ErrChainException, "misc_exception", 3100000, "Miscellaneous exception")
+var ErrRateLimitingStateInconsistent = newError(ErrMiscException, "rate_limiting_state_inconsistent", 3100001, "Internal state is no longer consistent")
+var ErrUnknownBlockException = newError(ErrMiscException, "unknown_block_exception", 3100002, "Unknown block")
+var ErrUnknownTransactionException = newError(ErrMiscException, "unknown_transaction_exception", 3100003, "Unknown transaction")
+var ErrFixedReversibleDBException = newError(ErrMiscException, "fixed_reversible_db_exception", 3100004, "Corrupted reversible block database was fixed")
+var ErrExtractGenesisStateException = newError(ErrMiscException, "extract_genesis_state_exception", 3100005, "Extracted genesis state from blocks.log")
+var ErrSubjectiveBlockProductionException = newError(ErrMiscException, "subjective_block_production_exception", 3100006, "Subjective exception thrown during block production")
+var ErrMultipleVoterInfo = newError(ErrMiscException, "multiple_voter_info", 3100007, "Multiple voter info detected")
+var ErrUnsupportedFeature = newError(ErrMiscException, "unsupported_feature", 3100008, "Feature is currently unsupported")
+var ErrNodeManagementSuccess = newError(ErrMiscException, "node_management_success", 3100009, "Node management operation successfully executed")
+var ErrJSONParseException = newError(ErrMiscException, "json_parse_exception", 3100010, "JSON parse exception")
+var ErrSigVariableSizeLimitException = newError(ErrMiscException, "sig_variable_size_limit_exception", 3100011, "Variable length component of signature too large")
+
+var ErrPluginException = newError(ErrChainException, "plugin_exception", 3110000, "Plugin exception")
+var ErrMissingChainAPIPluginException = newError(ErrPluginException, "missing_chain_api_plugin_exception", 3110001, "Missing Chain API Plugin")
+var ErrMissingWalletAPIPluginException = newError(ErrPluginException, "missing_wallet_api_plugin_exception", 3110002, "Missing Wallet API Plugin")
+var ErrMissingHistoryAPIPluginException = newError(ErrPluginException, "missing_history_api_plugin_exception", 3110003, "Missing History API Plugin")
+var ErrMissingNetAPIPluginException = newError(ErrPluginException, "missing_net_api_plugin_exception", 3110004, "Missing Net API Plugin")
+var ErrMissingChainPluginException = newError(ErrPluginException, "missing_chain_plugin_exception", 3110005, "Missing Chain Plugin")
+var ErrPluginConfigException = newError(ErrPluginException, "plugin_config_exception", 3110006, "Incorrect plugin configuration")
+
+var ErrWalletException = newError(ErrChainException, "wallet_exception", 3120000, "Wallet exception")
+var ErrWalletExistException = newError(ErrWalletException, "wallet_exist_exception", 3120001, "Wallet already exists")
+var ErrWalletNonexistentException = newError(ErrWalletException, "wallet_nonexistent_exception", 3120002, "Nonexistent wallet")
+var ErrWalletLockedException = newError(ErrWalletException, "wallet_locked_exception", 3120003, "Locked wallet")
+var ErrWalletMissingPubKeyException = newError(ErrWalletException, "wallet_missing_pub_key_exception", 3120004, "Missing public key")
+var ErrWalletInvalidPasswordException = newError(ErrWalletException, "wallet_invalid_password_exception", 3120005, "Invalid wallet password")
+var ErrWalletNotAvailableException = newError(ErrWalletException, "wallet_not_available_exception", 3120006, "No available wallet")
+var ErrWalletUnlockedException = newError(ErrWalletException, "wallet_unlocked_exception", 3120007, "Already unlocked")
+var ErrKeyExistException = newError(ErrWalletException, "key_exist_exception", 3120008, "Key already exists")
+var ErrKeyNonexistentException = newError(ErrWalletException, "key_nonexistent_exception", 3120009, "Nonexistent key")
+var ErrUnsupportedKeyTypeException = newError(ErrWalletException, "unsupported_key_type_exception", 3120010, "Unsupported key type")
+var ErrInvalidLockTimeoutException = newError(ErrWalletException, "invalid_lock_timeout_exception", 3120011, "Wallet lock timeout is invalid")
+var ErrSecureEnclaveException = newError(ErrWalletException, "secure_enclave_exception", 3120012, "Secure Enclave Exception")
+
+var ErrWhitelistBlacklistException = newError(ErrChainException, "whitelist_blacklist_exception", 3130000, "Actor or contract whitelist/blacklist exception")
+var ErrActorWhitelistException = newError(ErrWhitelistBlacklistException, "actor_whitelist_exception", 3130001, "Authorizing actor of transaction is not on the whitelist")
+var ErrActorBlacklistException = newError(ErrWhitelistBlacklistException, "actor_blacklist_exception", 3130002, "Authorizing actor of transaction is on the blacklist")
+var ErrContractWhitelistException = newError(ErrWhitelistBlacklistException, "contract_whitelist_exception", 3130003, "Contract to execute is not on the whitelist")
+var ErrContractBlacklistException = newError(ErrWhitelistBlacklistException, "contract_blacklist_exception", 3130004, "Contract to execute is on the blacklist")
+var ErrActionBlacklistException = newError(ErrWhitelistBlacklistException, "action_blacklist_exception", 3130005, "Action to execute is on the blacklist")
+var ErrKeyBlacklistException = newError(ErrWhitelistBlacklistException, "key_blacklist_exception", 3130006, "Public key in authority is on the blacklist")
+
+var ErrControllerEmitSignalException = newError(ErrChainException, "controller_emit_signal_exception", 3140000, "Exceptions that are allowed to bubble out of emit calls in controller")
+var ErrCheckpointException = newError(ErrControllerEmitSignalException, "checkpoint_exception", 3140001, "Block does not match checkpoint")
+
+var ErrContractException = newError(ErrChainException, "contract_exception", 3160000, "Contract exception")
+var ErrInvalidTablePayer = newError(ErrContractException, "invalid_table_payer", 3160001, "The payer of the table data is invalid")
+var ErrTableAccessViolation = newError(ErrContractException, "table_access_violation", 3160002, "Table access violation")
+var ErrInvalidTableIterator = newError(ErrContractException, "invalid_table_iterator", 3160003, "Invalid table iterator")
+var ErrTableNotInCache = newError(ErrContractException, "table_not_in_cache", 3160004, "Table can not be found inside the cache")
+var ErrTableOperationNotPermitted = newError(ErrContractException, "table_operation_not_permitted", 3160005, "The table operation is not allowed")
+var ErrInvalidContractVMType = newError(ErrContractException, "invalid_contract_vm_type", 3160006, "Invalid contract vm type")
+var ErrInvalidContractVMVersion = newError(ErrContractException, "invalid_contract_vm_version", 3160007, "Invalid contract vm version")
+var ErrSetExactCode = newError(ErrContractException, "set_exact_code", 3160008, "Contract is already running this version of code")
+var ErrWASMFileNotFound = newError(ErrContractException, "wasm_file_not_found", 3160009, "No wasm file found")
+var ErrABIFileNotFound = newError(ErrContractException, "abi_file_not_found", 3160010, "No abi file found")
+
+var ErrProducerException = newError(ErrChainException, "producer_exception", 3170000, "Producer exception")
+var ErrProducerPrivKeyNotFound = newError(ErrProducerException, "producer_priv_key_not_found", 3170001, "Producer private key is not available")
+var ErrMissingPendingBlockState = newError(ErrProducerException, "missing_pending_block_state", 3170002, "Pending block state is missing")
+var ErrProducerDoubleConfirm = newError(ErrProducerException, "producer_double_confirm", 3170003, "Producer is double confirming known range")
+var ErrProducerScheduleException = newError(ErrProducerException, "producer_schedule_exception", 3170004, "Producer schedule exception")
+var ErrProducerNotInSchedule = newError(ErrProducerException, "producer_not_in_schedule", 3170006, "The producer is not part of current schedule")
+var ErrSnapshotDirectoryNotFoundException = newError(ErrProducerException, "snapshot_directory_not_found_exception", 3170007, "The configured snapshot directory does not exist")
+var ErrSnapshotExistsException = newError(ErrProducerException, "snapshot_exists_exception", 3170008, "The requested snapshot already exists")
+var ErrSnapshotFinalizationException = newError(ErrProducerException, "snapshot_finalization_exception", 3170009, "Snapshot Finalization Exception")
+var ErrInvalidProtocolFeaturesToActivate = newError(ErrProducerException, "invalid_protocol_features_to_activate", 3170010, "The protocol features to be activated were not valid")
+var ErrNoBlockSignatures = newError(ErrProducerException, "no_block_signatures", 3170011, "The signer returned no valid block signatures")
+var ErrUnsupportedMultipleBlockSignatures = newError(ErrProducerException, "unsupported_multiple_block_signatures", 3170012, "The signer returned multiple signatures but that is not supported")
+
+var ErrReversibleBlocksException = newError(ErrChainException, "reversible_blocks_exception", 3180000, "Reversible Blocks exception")
+var ErrInvalidReversibleBlocksDir = newError(ErrReversibleBlocksException, "invalid_reversible_blocks_dir", 3180001, "Invalid reversible blocks directory")
+var ErrReversibleBlocksBackupDirExist = newError(ErrReversibleBlocksException, "reversible_blocks_backup_dir_exist", 3180002, "Backup directory for reversible blocks already existg")
+var ErrGapInReversibleBlocksDB = newError(ErrReversibleBlocksException, "gap_in_reversible_blocks_db", 3180003, "Gap in the reversible blocks database")
+
+var ErrBlockLogException = newError(ErrChainException, "block_log_exception", 3190000, "Block log exception")
+var ErrBlockLogUnsupportedVersion = newError(ErrBlockLogException, "block_log_unsupported_version", 3190001, "unsupported version of block log")
+var ErrBlockLogAppendFail = newError(ErrBlockLogException, "block_log_append_fail", 3190002, "fail to append block to the block log")
+var ErrBlockLogNotFound = newError(ErrBlockLogException, "block_log_not_found", 3190003, "block log can not be found")
+var ErrBlockLogBackupDirExist = newError(ErrBlockLogException, "block_log_backup_dir_exist", 3190004, "block log backup dir already exists")
+var ErrBlockIndexNotFound = newError(ErrBlockLogException, "block_index_not_found", 3190005, "block index can not be found")
+
+var ErrHTTPException = newError(ErrChainException, "http_exception", 3200000, "http exception")
+var ErrInvalidHTTPClientRootCert = newError(ErrHTTPException, "invalid_http_client_root_cert", 3200001, "invalid http client root certificate")
+var ErrInvalidHTTPResponse = newError(ErrHTTPException, "invalid_http_response", 3200002, "invalid http response")
+var ErrResolvedToMultiplePorts = newError(ErrHTTPException, "resolved_to_multiple_ports", 3200003, "service resolved to multiple ports")
+var ErrFailToResolveHost = newError(ErrHTTPException, "fail_to_resolve_host", 3200004, "fail to resolve host")
+var ErrHTTPRequestFail = newError(ErrHTTPException, "http_request_fail", 3200005, "http request fail")
+var ErrInvalidHTTPRequest = newError(ErrHTTPException, "invalid_http_request", 3200006, "invalid http request")
+
+var ErrResourceLimitException = newError(ErrChainException, "resource_limit_exception", 3210000, "Resource limit exception")
+
+var ErrMongoDBException = newError(ErrChainException, "mongo_db_exception", 3220000, "Mongo DB exception")
+var ErrMongoDBInsertFail = newError(ErrMongoDBException, "mongo_db_insert_fail", 3220001, "Fail to insert new data to Mongo DB")
+var ErrMongoDBUpdateFail = newError(ErrMongoDBException, "mongo_db_update_fail", 3220002, "Fail to update existing data in Mongo DB")
+
+var ErrContractAPIException = newError(ErrChainException, "contract_api_exception", 3230000, "Contract API exception")
+var ErrCryptoAPIException = newError(ErrContractAPIException, "crypto_api_exception", 3230001, "Crypto API Exception")
+var ErrDBAPIException = newError(ErrContractAPIException, "db_api_exception", 3230002, "Database API Exception")
+var ErrArithmeticException = newError(ErrContractAPIException, "arithmetic_exception", 3230003, "Arithmetic Exception")
+
+var ErrSnapshotException = newError(ErrChainException, "snapshot_exception", 3240000, "Snapshot exception")
+var ErrSnapshotValidationException = newError(ErrSnapshotException, "snapshot_validation_exception", 3240001, "Snapshot Validation Exception")
+
+var ErrProtocolFeatureException = newError(ErrChainException, "protocol_feature_exception", 3250000, "Protocol feature exception")
+var ErrProtocolFeatureValidationException = newError(ErrProtocolFeatureException, "protocol_feature_validation_exception", 3250001, "Protocol feature validation exception")
+var ErrProtocolFeatureBadBlockException = newError(ErrProtocolFeatureException, "protocol_feature_bad_block_exception", 3250002, "Protocol feature exception (invalid block)")
+var ErrProtocolFeatureIteratorException = newError(ErrProtocolFeatureException, "protocol_feature_iterator_exception", 3250003, "Protocol feature iterator exception")
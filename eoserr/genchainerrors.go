@@ -0,0 +1,162 @@
+//go:build ignore
+// +build ignore
+
+// genchainerrors generates chainerrors.go from the exceptions header of
+// `nodeos`, `libraries/chain/include/eosio/chain/exceptions.hpp`:
+//
+//	go run genchainerrors.go -input path/to/exceptions.hpp
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const header = `// Code generated by genchainerrors.go from exceptions.hpp; DO NOT EDIT.
+
+// Chain exceptions, mirroring ` + "`libraries/chain/include/eosio/chain/exceptions.hpp`" + `
+// of ` + "`nodeos`" + `, in the same order so parents are declared first.
+
+package eoserr
+`
+
+// rootException is the parent of the root chain exception, which maps
+// to the zero `Error`.
+const rootException = "fc::exception"
+
+// initialisms are the words of the exception names written in capitals
+// in Go names.
+var initialisms = map[string]bool{
+	"abi":   true,
+	"api":   true,
+	"cfa":   true,
+	"cpu":   true,
+	"db":    true,
+	"eosio": true,
+	"http":  true,
+	"id":    true,
+	"json":  true,
+	"kv":    true,
+	"ram":   true,
+	"vm":    true,
+	"wasm":  true,
+}
+
+var (
+	blockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	lineComment  = regexp.MustCompile(`(?m)//.*$`)
+	define       = regexp.MustCompile(`(?m)^\s*#.*(\\\n.*)*$`)
+	declaration  = regexp.MustCompile(`FC_DECLARE_DERIVED_EXCEPTION(?:_WITH_ERROR_CODE)?\s*\(\s*(\w+)\s*,\s*([\w:]+)\s*,\s*(\d+)\s*,\s*("(?:[^"\\]|\\.)*")\s*\)`)
+)
+
+type exception struct {
+	name   string
+	parent string
+	code   int
+	what   string
+}
+
+func main() {
+	input := flag.String("input", "", "path to the exceptions.hpp header of nodeos")
+	output := flag.String("output", "chainerrors.go", "path of the generated file")
+	flag.Parse()
+
+	if *input == "" {
+		log.Fatal("the -input exceptions.hpp header is required")
+	}
+
+	source, err := os.ReadFile(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exceptions, err := parseExceptions(string(source))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := generate(exceptions)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*output, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parseExceptions(source string) ([]*exception, error) {
+	source = blockComment.ReplaceAllString(source, "")
+	source = lineComment.ReplaceAllString(source, "")
+	source = define.ReplaceAllString(source, "")
+
+	var exceptions []*exception
+	declared := map[string]bool{rootException: true}
+	for _, match := range declaration.FindAllStringSubmatch(source, -1) {
+		code, err := strconv.Atoi(match[3])
+		if err != nil {
+			return nil, fmt.Errorf("exception %s: invalid code %q", match[1], match[3])
+		}
+
+		what, err := strconv.Unquote(match[4])
+		if err != nil {
+			return nil, fmt.Errorf("exception %s: invalid message %s", match[1], match[4])
+		}
+
+		if !declared[match[2]] {
+			return nil, fmt.Errorf("exception %s: parent %s is not declared before", match[1], match[2])
+		}
+		declared[match[1]] = true
+
+		exceptions = append(exceptions, &exception{name: match[1], parent: match[2], code: code, what: what})
+	}
+
+	if len(exceptions) == 0 {
+		return nil, fmt.Errorf("no exception declaration found")
+	}
+
+	return exceptions, nil
+}
+
+// generate declares the exceptions in order, separating the groups
+// deriving from the root exception by a blank line.
+func generate(exceptions []*exception) ([]byte, error) {
+	buffer := bytes.NewBufferString(header)
+
+	root := exceptions[0].name
+	for _, e := range exceptions {
+		parent := "Error{}"
+		if e.parent != rootException {
+			parent = goName(e.parent)
+		}
+
+		if e.parent == rootException || e.parent == root {
+			buffer.WriteString("\n")
+		}
+
+		fmt.Fprintf(buffer, "var %s = newError(%s, %q, %d, %q)\n", goName(e.name), parent, e.name, e.code, e.what)
+	}
+
+	return format.Source(buffer.Bytes())
+}
+
+// goName returns the Go variable name of an exception, like
+// `ErrABITypeException` for `abi_type_exception`.
+func goName(name string) string {
+	words := strings.Split(name, "_")
+	for i, word := range words {
+		if initialisms[word] {
+			words[i] = strings.ToUpper(word)
+		} else if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return "Err" + strings.Join(words, "")
+}
@@ -1,7 +1,10 @@
 package eos
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/eoscanada/eos-go/eoserr"
@@ -17,6 +20,32 @@ type APIError struct {
 		What    string           `json:"what"`
 		Details []APIErrorDetail `json:"details"`
 	} `json:"error"`
+
+	// The following fields are parsed from the details of errors raised
+	// by contracts.
+
+	// AssertMessage is the message of a failed `eosio_assert`.
+	AssertMessage string `json:"-"`
+	// AssertCode is the code of a failed `eosio_assert_code`, see
+	// `ABI.ErrorMessage` to get its message.
+	AssertCode *uint64 `json:"-"`
+	// Receiver, Account and Action identify the failing action, when
+	// reported by `nodeos`.
+	Receiver AccountName `json:"-"`
+	Account  AccountName `json:"-"`
+	Action   ActionName  `json:"-"`
+	// Console is the console output of the failing action.
+	Console string `json:"-"`
+}
+
+func (e *APIError) UnmarshalJSON(data []byte) error {
+	type apiError APIError
+	if err := json.Unmarshal(data, (*apiError)(e)); err != nil {
+		return err
+	}
+
+	e.parseDetails()
+	return nil
 }
 
 func NewAPIError(httpCode int, msg string, e eoserr.Error) *APIError {
@@ -36,9 +65,51 @@ func NewAPIError(httpCode int, msg string, e eoserr.Error) *APIError {
 		},
 	}
 
+	newError.parseDetails()
+
 	return newError
 }
 
+var (
+	assertMessagePrefix = "assertion failure with message: "
+	assertCodePrefix    = "assertion failure with error code: "
+	// Older `nodeos` only report "pending console output: <output>".
+	pendingConsoleRegexp = regexp.MustCompile(`(?s)^(?:(\S+) <= (\S+)::(\S+) )?pending console output: (.*)$`)
+)
+
+func (e *APIError) parseDetails() {
+	for _, detail := range e.ErrorStruct.Details {
+		switch {
+		case strings.HasPrefix(detail.Message, assertMessagePrefix):
+			e.AssertMessage = strings.TrimPrefix(detail.Message, assertMessagePrefix)
+
+		case strings.HasPrefix(detail.Message, assertCodePrefix):
+			code, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(detail.Message, assertCodePrefix)), 10, 64)
+			if err == nil {
+				e.AssertCode = &code
+			}
+
+		default:
+			if matches := pendingConsoleRegexp.FindStringSubmatch(detail.Message); matches != nil {
+				e.Receiver = AccountName(matches[1])
+				e.Account = AccountName(matches[2])
+				e.Action = ActionName(matches[3])
+				e.Console = matches[4]
+			}
+		}
+	}
+}
+
+// AssertCodeMessage returns the message declared in `abi` for the code
+// of a failed `eosio_assert_code`.
+func (e APIError) AssertCodeMessage(abi *ABI) (string, bool) {
+	if e.AssertCode == nil || abi == nil {
+		return "", false
+	}
+
+	return abi.ErrorMessage(*e.AssertCode)
+}
+
 type APIErrorDetail struct {
 	Message    string `json:"message"`
 	File       string `json:"file"`
@@ -57,6 +128,16 @@ func (e APIError) Error() string {
 	return msg
 }
 
+// Unwrap returns the `eoserr.Error` matching the code of the error,
+// making `errors.Is(err, eoserr.ErrTxCPUUsageExceeded)` or
+// `eoserr.IsResourceExhausted(err)` work on errors returned by the API.
+func (e APIError) Unwrap() error {
+	if chainErr, found := eoserr.ByCode(e.ErrorStruct.Code); found {
+		return chainErr
+	}
+	return nil
+}
+
 // IsUnknowKeyError determines if the APIError is a 500 error
 // with an `unknown key` message in at least one of the detail element.
 // Some endpoint like `/v1/chain/get_account` returns a body in
@@ -0,0 +1,109 @@
+package eos
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/eoscanada/eos-go/eoserr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	var apiErr APIError
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"code": 500,
+		"message": "Internal Service Error",
+		"error": {
+			"code": 3080004,
+			"name": "tx_cpu_usage_exceeded",
+			"what": "Transaction exceeded the current CPU usage limit imposed on the transaction",
+			"details": []
+		}
+	}`), &apiErr))
+
+	var err error = apiErr
+	assert.True(t, errors.Is(err, eoserr.ErrTxCPUUsageExceeded))
+	assert.True(t, eoserr.IsResourceExhausted(err))
+	assert.False(t, eoserr.IsAuthorization(err))
+
+	var chainErr eoserr.Error
+	require.True(t, errors.As(err, &chainErr))
+	assert.Equal(t, eoserr.ErrTxCPUUsageExceeded, chainErr)
+
+	err = NewAPIError(500, "missing auth", eoserr.ErrMissingAuthException)
+	assert.True(t, eoserr.IsAuthorization(err))
+
+	apiErr.ErrorStruct.Code = 0
+	assert.False(t, errors.Is(apiErr, eoserr.ErrChainException))
+}
+
+func TestAPIError_Details(t *testing.T) {
+	tests := []struct {
+		name             string
+		details          string
+		expectedMessage  string
+		expectedCode     *uint64
+		expectedReceiver AccountName
+		expectedAccount  AccountName
+		expectedAction   ActionName
+		expectedConsole  string
+	}{
+		{
+			name: "assert message",
+			details: `[
+				{"message": "assertion failure with message: overdrawn balance", "file": "cf_system.cpp", "line_number": 14, "method": "eosio_assert"},
+				{"message": "pending console output: ", "file": "apply_context.cpp", "line_number": 143, "method": "exec_one"}
+			]`,
+			expectedMessage: "overdrawn balance",
+		},
+		{
+			name: "assert code with action",
+			details: `[
+				{"message": "assertion failure with error code: 8000000000000000000", "file": "cf_system.cpp", "line_number": 17, "method": "eosio_assert_code"},
+				{"message": "alice <= eosio.token::transfer pending console output: checking\nbalance", "file": "apply_context.cpp", "line_number": 124, "method": "exec_one"}
+			]`,
+			expectedCode:     func() *uint64 { v := uint64(8000000000000000000); return &v }(),
+			expectedReceiver: "alice",
+			expectedAccount:  "eosio.token",
+			expectedAction:   "transfer",
+			expectedConsole:  "checking\nbalance",
+		},
+		{
+			name:    "no contract details",
+			details: `[{"message": "unknown key", "file": "http_plugin.cpp", "line_number": 589, "method": "handle_exception"}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var apiErr APIError
+			require.NoError(t, json.Unmarshal([]byte(`{"code": 500, "error": {"code": 3050003, "details": `+test.details+`}}`), &apiErr))
+
+			assert.Equal(t, test.expectedMessage, apiErr.AssertMessage)
+			assert.Equal(t, test.expectedCode, apiErr.AssertCode)
+			assert.Equal(t, test.expectedReceiver, apiErr.Receiver)
+			assert.Equal(t, test.expectedAccount, apiErr.Account)
+			assert.Equal(t, test.expectedAction, apiErr.Action)
+			assert.Equal(t, test.expectedConsole, apiErr.Console)
+		})
+	}
+}
+
+func TestAPIError_AssertCodeMessage(t *testing.T) {
+	abi := &ABI{ErrorMessages: []ABIErrorMessage{{Code: 10, Message: "overdrawn balance"}}}
+
+	code := uint64(10)
+	apiErr := APIError{AssertCode: &code}
+	message, found := apiErr.AssertCodeMessage(abi)
+	assert.True(t, found)
+	assert.Equal(t, "overdrawn balance", message)
+
+	code = 11
+	_, found = apiErr.AssertCodeMessage(abi)
+	assert.False(t, found)
+
+	_, found = APIError{}.AssertCodeMessage(abi)
+	assert.False(t, found)
+}
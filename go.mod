@@ -3,11 +3,13 @@ module github.com/eoscanada/eos-go
 go 1.17
 
 require (
+	github.com/gorilla/websocket v1.5.0
 	github.com/jarcoal/httpmock v1.2.0
 	github.com/pkg/errors v0.9.1
 	github.com/streamingfast/logging v0.0.0-20221209193439-bff11742bf4c
-	github.com/stretchr/testify v1.7.0
+	github.com/stretchr/testify v1.8.1
 	github.com/tidwall/gjson v1.9.3
+	go.etcd.io/bbolt v1.3.7
 	go.uber.org/zap v1.21.0
 	golang.org/x/crypto v0.1.0
 )
@@ -22,8 +24,8 @@ require (
 	github.com/tidwall/pretty v1.2.0 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
-	golang.org/x/sys v0.1.0 // indirect
+	golang.org/x/sys v0.4.0 // indirect
 	golang.org/x/term v0.1.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
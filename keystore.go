@@ -0,0 +1,423 @@
+package eos
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/eoscanada/eos-go/ecc"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The keystore is a JSON document holding named private keys, encrypted
+// with AES-256-GCM under a key derived from a password with scrypt or
+// argon2id. The KDF parameters are authenticated along with the keys.
+const (
+	KeystoreVersion = 1
+
+	KeystoreKDFScrypt   = "scrypt"
+	KeystoreKDFArgon2id = "argon2id"
+
+	KeystoreCipherAES256GCM = "aes-256-gcm"
+)
+
+var (
+	ErrKeystoreWrongPassword = errors.New("wrong password or corrupted keystore")
+	ErrKeystoreKeyExists     = errors.New("a key with this name already exists")
+)
+
+// KeystoreKDF holds the parameters of the key derivation function.
+type KeystoreKDF struct {
+	Name string   `json:"name"`
+	Salt HexBytes `json:"salt"`
+
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+
+	// argon2id
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+func (k *KeystoreKDF) deriveKey(password string) ([]byte, error) {
+	switch k.Name {
+	case KeystoreKDFScrypt:
+		return scrypt.Key([]byte(password), k.Salt, k.N, k.R, k.P, 32)
+	case KeystoreKDFArgon2id:
+		if k.Time == 0 || k.Memory == 0 || k.Threads == 0 {
+			return nil, fmt.Errorf("invalid argon2id parameters")
+		}
+		return argon2.IDKey([]byte(password), k.Salt, k.Time, k.Memory, k.Threads, 32), nil
+	}
+
+	return nil, fmt.Errorf("unsupported kdf %q", k.Name)
+}
+
+type KeystoreCipher struct {
+	Name  string   `json:"name"`
+	Nonce HexBytes `json:"nonce"`
+}
+
+// KeystoreFile is the on-disk representation of a `Keystore`.
+type KeystoreFile struct {
+	Version    int            `json:"version"`
+	KDF        KeystoreKDF    `json:"kdf"`
+	Cipher     KeystoreCipher `json:"cipher"`
+	CipherText HexBytes       `json:"ciphertext"`
+}
+
+// NamedKey is a private key of a `Keystore`.
+type NamedKey struct {
+	Name       string          `json:"name"`
+	PrivateKey *ecc.PrivateKey `json:"private_key"`
+}
+
+// Keystore holds named K1 or R1 private keys, that can be saved
+// encrypted with a password. Use `KeyBag` to sign with them.
+type Keystore struct {
+	Keys []*NamedKey `json:"keys"`
+}
+
+func NewKeystore() *Keystore {
+	return &Keystore{
+		Keys: make([]*NamedKey, 0),
+	}
+}
+
+// Add adds a private key under `name`, which must be unique in the
+// keystore. WA keys, which have no string form, cannot be added.
+func (k *Keystore) Add(name string, privateKey *ecc.PrivateKey) error {
+	if privateKey == nil {
+		return errors.New("adding a nil private key is forbidden")
+	}
+
+	if k.Get(name) != nil {
+		return fmt.Errorf("%w: %q", ErrKeystoreKeyExists, name)
+	}
+
+	// Keys are saved in their string form, make sure it can be read back.
+	parsed, err := ecc.NewPrivateKey(privateKey.String())
+	if err != nil || parsed.PublicKey().String() != privateKey.PublicKey().String() {
+		return fmt.Errorf("%s private keys cannot be stored in a keystore", privateKey.Curve)
+	}
+
+	k.Keys = append(k.Keys, &NamedKey{Name: name, PrivateKey: privateKey})
+	return nil
+}
+
+// Get returns the private key named `name`, or nil.
+func (k *Keystore) Get(name string) *ecc.PrivateKey {
+	for _, key := range k.Keys {
+		if key.Name == name {
+			return key.PrivateKey
+		}
+	}
+	return nil
+}
+
+// Remove removes the private key named `name`, returning whether it
+// was found.
+func (k *Keystore) Remove(name string) bool {
+	for i, key := range k.Keys {
+		if key.Name == name {
+			k.Keys = append(k.Keys[:i], k.Keys[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// KeyBag returns a `KeyBag` holding all the keys of the keystore.
+func (k *Keystore) KeyBag() *KeyBag {
+	bag := NewKeyBag()
+	for _, key := range k.Keys {
+		bag.Keys = append(bag.Keys, key.PrivateKey)
+	}
+	return bag
+}
+
+type KeystoreOption interface {
+	apply(kdf *KeystoreKDF)
+}
+
+type keystoreOptionFunc func(kdf *KeystoreKDF)
+
+func (o keystoreOptionFunc) apply(kdf *KeystoreKDF) {
+	o(kdf)
+}
+
+// KeystoreScrypt derives the encryption key with scrypt, which is the
+// default, with N=32768, r=8 and p=1.
+func KeystoreScrypt(n, r, p int) KeystoreOption {
+	return keystoreOptionFunc(func(kdf *KeystoreKDF) {
+		*kdf = KeystoreKDF{Name: KeystoreKDFScrypt, N: n, R: r, P: p}
+	})
+}
+
+// KeystoreArgon2id derives the encryption key with argon2id, `memory`
+// being in KiB.
+func KeystoreArgon2id(time, memory uint32, threads uint8) KeystoreOption {
+	return keystoreOptionFunc(func(kdf *KeystoreKDF) {
+		*kdf = KeystoreKDF{Name: KeystoreKDFArgon2id, Time: time, Memory: memory, Threads: threads}
+	})
+}
+
+// Encrypt returns the keystore encrypted with `password`, with a new
+// salt and nonce each time.
+func (k *Keystore) Encrypt(password string, opts ...KeystoreOption) ([]byte, error) {
+	file := &KeystoreFile{
+		Version: KeystoreVersion,
+		KDF:     KeystoreKDF{Name: KeystoreKDFScrypt, N: 1 << 15, R: 8, P: 1},
+		Cipher:  KeystoreCipher{Name: KeystoreCipherAES256GCM},
+	}
+	for _, opt := range opts {
+		opt.apply(&file.KDF)
+	}
+
+	file.KDF.Salt = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, file.KDF.Salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+
+	aead, err := file.aead(password)
+	if err != nil {
+		return nil, err
+	}
+
+	file.Cipher.Nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, file.Cipher.Nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	plainText, err := json.Marshal(k)
+	if err != nil {
+		return nil, fmt.Errorf("marshal keys: %w", err)
+	}
+
+	additionalData, err := file.additionalData()
+	if err != nil {
+		return nil, err
+	}
+
+	file.CipherText = aead.Seal(nil, file.Cipher.Nonce, plainText, additionalData)
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// DecryptKeystore decrypts a keystore produced by `Keystore.Encrypt`.
+func DecryptKeystore(data []byte, password string) (*Keystore, error) {
+	var file KeystoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unmarshal keystore: %w", err)
+	}
+
+	if file.Version != KeystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", file.Version)
+	}
+
+	aead, err := file.aead(password)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(file.Cipher.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("nonce should be %d bytes, got %d", aead.NonceSize(), len(file.Cipher.Nonce))
+	}
+
+	additionalData, err := file.additionalData()
+	if err != nil {
+		return nil, err
+	}
+
+	plainText, err := aead.Open(nil, file.Cipher.Nonce, file.CipherText, additionalData)
+	if err != nil {
+		return nil, ErrKeystoreWrongPassword
+	}
+
+	keystore := NewKeystore()
+	if err := json.Unmarshal(plainText, keystore); err != nil {
+		return nil, fmt.Errorf("unmarshal keys: %w", err)
+	}
+
+	return keystore, nil
+}
+
+func (f *KeystoreFile) aead(password string) (cipher.AEAD, error) {
+	if f.Cipher.Name != KeystoreCipherAES256GCM {
+		return nil, fmt.Errorf("unsupported cipher %q", f.Cipher.Name)
+	}
+
+	key, err := f.KDF.deriveKey(password)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// additionalData authenticates the parameters of the file, so they
+// cannot be tampered with.
+func (f *KeystoreFile) additionalData() ([]byte, error) {
+	return json.Marshal(struct {
+		Version int            `json:"version"`
+		KDF     KeystoreKDF    `json:"kdf"`
+		Cipher  KeystoreCipher `json:"cipher"`
+	}{f.Version, f.KDF, f.Cipher})
+}
+
+// LoadKeystore reads and decrypts the keystore file at `path`.
+func LoadKeystore(path string, password string) (*Keystore, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keystore [%s]: %w", path, err)
+	}
+
+	return DecryptKeystore(data, password)
+}
+
+// Save encrypts the keystore with `password` and writes it to `path`,
+// readable only by its owner. The file is replaced atomically.
+func (k *Keystore) Save(path string, password string, opts ...KeystoreOption) error {
+	data, err := k.Encrypt(password, opts...)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("create keystore [%s]: %w", path, err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if err := tmpFile.Chmod(0600); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write keystore [%s]: %w", path, err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// RotateKeystorePassword re-encrypts the keystore at `path` with
+// `newPassword`.
+func RotateKeystorePassword(path string, oldPassword, newPassword string, opts ...KeystoreOption) error {
+	keystore, err := LoadKeystore(path, oldPassword)
+	if err != nil {
+		return err
+	}
+
+	return keystore.Save(path, newPassword, opts...)
+}
+
+// KeosdWallet is the format of the `.wallet` files of `keosd`.
+type KeosdWallet struct {
+	CipherKeys HexBytes `json:"cipher_keys"`
+}
+
+// ImportKeosdWallet reads the `keosd` wallet file at `path`, naming the
+// keys after their public key.
+func ImportKeosdWallet(path string, password string) (*Keystore, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read wallet [%s]: %w", path, err)
+	}
+
+	return DecryptKeosdWallet(data, password)
+}
+
+// DecryptKeosdWallet decrypts the content of a `keosd` wallet file.
+// `keosd` encrypts its keys with AES-256-CBC, using the SHA-512 of the
+// password as key and IV, and stores that hash along with the keys to
+// check the password.
+func DecryptKeosdWallet(data []byte, password string) (*Keystore, error) {
+	var wallet KeosdWallet
+	if err := json.Unmarshal(data, &wallet); err != nil {
+		return nil, fmt.Errorf("unmarshal wallet: %w", err)
+	}
+
+	checksum := sha512.Sum512([]byte(password))
+	block, err := aes.NewCipher(checksum[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wallet.CipherKeys) == 0 || len(wallet.CipherKeys)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid cipher keys length %d", len(wallet.CipherKeys))
+	}
+
+	plainText := make([]byte, len(wallet.CipherKeys))
+	cipher.NewCBCDecrypter(block, checksum[32:48]).CryptBlocks(plainText, wallet.CipherKeys)
+
+	padding := int(plainText[len(plainText)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(plainText) {
+		return nil, ErrKeystoreWrongPassword
+	}
+	plainText = plainText[:len(plainText)-padding]
+
+	if len(plainText) < sha512.Size || !bytes.Equal(plainText[:sha512.Size], checksum[:]) {
+		return nil, ErrKeystoreWrongPassword
+	}
+
+	decoder := NewDecoder(plainText[sha512.Size:])
+	count, err := decoder.ReadUvarint32()
+	if err != nil {
+		return nil, fmt.Errorf("read keys count: %w", err)
+	}
+
+	keystore := NewKeystore()
+	for i := uint32(0); i < count; i++ {
+		publicKey, err := decoder.ReadPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("read public key: %w", err)
+		}
+
+		curveID, err := decoder.ReadUint8()
+		if err != nil {
+			return nil, fmt.Errorf("read private key type: %w", err)
+		}
+		keyMaterial, err := decoder.ReadChecksum256()
+		if err != nil {
+			return nil, fmt.Errorf("read private key: %w", err)
+		}
+
+		privateKey, err := ecc.NewPrivateKeyFromData(append([]byte{curveID}, keyMaterial...))
+		if err != nil {
+			return nil, fmt.Errorf("private key of %s: %w", publicKey, err)
+		}
+
+		if privateKey.PublicKey().String() != publicKey.String() {
+			return nil, fmt.Errorf("private key does not match public key %s", publicKey)
+		}
+
+		if err := keystore.Add(publicKey.String(), privateKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return keystore, nil
+}
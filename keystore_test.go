@@ -0,0 +1,191 @@
+package eos
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eoscanada/eos-go/btcsuite/btcutil"
+	"github.com/eoscanada/eos-go/ecc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKeystoreKDF keeps the key derivation fast in tests.
+var testKeystoreKDF = KeystoreScrypt(1<<10, 8, 1)
+
+func newTestKeystore(t *testing.T) *Keystore {
+	keystore := NewKeystore()
+	for _, name := range []string{"owner", "active"} {
+		privateKey, err := ecc.NewRandomPrivateKey()
+		require.NoError(t, err)
+		require.NoError(t, keystore.Add(name, privateKey))
+	}
+	return keystore
+}
+
+// testR1KeyData is the binary representation of an R1 private key.
+var testR1KeyData = append([]byte{byte(ecc.CurveR1)}, bytes.Repeat([]byte{0x42}, 32)...)
+
+func TestKeystore_EncryptDecrypt(t *testing.T) {
+	keystore := newTestKeystore(t)
+
+	for _, opt := range []KeystoreOption{testKeystoreKDF, KeystoreArgon2id(1, 1024, 1)} {
+		data, err := keystore.Encrypt("secret", opt)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), keystore.Get("owner").String())
+
+		decrypted, err := DecryptKeystore(data, "secret")
+		require.NoError(t, err)
+		assert.Equal(t, keystore.Get("owner").String(), decrypted.Get("owner").String())
+		assert.Equal(t, keystore.Get("active").String(), decrypted.Get("active").String())
+
+		_, err = DecryptKeystore(data, "wrong")
+		assert.Equal(t, ErrKeystoreWrongPassword, err)
+	}
+}
+
+func TestKeystore_R1Keys(t *testing.T) {
+	r1Key, err := ecc.NewPrivateKeyFromData(testR1KeyData)
+	require.NoError(t, err)
+
+	keystore := newTestKeystore(t)
+	require.NoError(t, keystore.Add("r1", r1Key))
+
+	data, err := keystore.Encrypt("secret", testKeystoreKDF)
+	require.NoError(t, err)
+
+	decrypted, err := DecryptKeystore(data, "secret")
+	require.NoError(t, err)
+
+	decryptedKey := decrypted.Get("r1")
+	require.NotNil(t, decryptedKey)
+	assert.Equal(t, ecc.CurveR1, decryptedKey.Curve)
+	assert.Equal(t, r1Key.String(), decryptedKey.String())
+	assert.Equal(t, r1Key.PublicKey().String(), decryptedKey.PublicKey().String())
+
+	digest := SigDigest(testMessageChainID, []byte("payload"), nil)
+	signature, err := decryptedKey.Sign(digest)
+	require.NoError(t, err)
+	assert.True(t, signature.Verify(digest, r1Key.PublicKey()))
+}
+
+func TestKeystore_TamperedParameters(t *testing.T) {
+	data, err := newTestKeystore(t).Encrypt("secret", testKeystoreKDF)
+	require.NoError(t, err)
+
+	var file KeystoreFile
+	require.NoError(t, json.Unmarshal(data, &file))
+	file.KDF.R = 4
+	file.KDF.Salt = file.KDF.Salt[:16]
+
+	tampered, err := json.Marshal(file)
+	require.NoError(t, err)
+
+	_, err = DecryptKeystore(tampered, "secret")
+	assert.Equal(t, ErrKeystoreWrongPassword, err)
+}
+
+func TestKeystore_Keys(t *testing.T) {
+	keystore := newTestKeystore(t)
+
+	assert.ErrorIs(t, keystore.Add("owner", keystore.Get("active")), ErrKeystoreKeyExists)
+	assert.Nil(t, keystore.Get("unknown"))
+
+	keyBag := keystore.KeyBag()
+	keys, err := keyBag.AvailableKeys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []ecc.PublicKey{keystore.Get("owner").PublicKey(), keystore.Get("active").PublicKey()}, keys)
+
+	assert.True(t, keystore.Remove("owner"))
+	assert.False(t, keystore.Remove("owner"))
+	assert.Len(t, keystore.Keys, 1)
+}
+
+func TestKeystore_SaveAndRotatePassword(t *testing.T) {
+	keystore := newTestKeystore(t)
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	require.NoError(t, keystore.Save(path, "secret", testKeystoreKDF))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	require.NoError(t, RotateKeystorePassword(path, "secret", "new secret", testKeystoreKDF))
+
+	_, err = LoadKeystore(path, "secret")
+	assert.Equal(t, ErrKeystoreWrongPassword, err)
+
+	loaded, err := LoadKeystore(path, "new secret")
+	require.NoError(t, err)
+	assert.Equal(t, keystore.Get("active").String(), loaded.Get("active").String())
+
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "temporary files should be cleaned up")
+}
+
+// encryptTestKeosdWallet encrypts keys, given in their binary
+// representation, the way `keosd` does in `soft_wallet::encrypt_keys`.
+func encryptTestKeosdWallet(t *testing.T, password string, keys ...[]byte) []byte {
+	checksum := sha512.Sum512([]byte(password))
+
+	buf := &bytes.Buffer{}
+	encoder := NewEncoder(buf)
+	require.NoError(t, encoder.Encode(Checksum512(checksum[:])))
+	require.NoError(t, encoder.writeUVarInt(len(keys)))
+	for _, data := range keys {
+		key, err := ecc.NewPrivateKeyFromData(data)
+		require.NoError(t, err)
+		require.NoError(t, encoder.Encode(key.PublicKey()))
+		require.NoError(t, encoder.writeByte(data[0]))
+		require.NoError(t, encoder.Encode(Checksum256(data[1:])))
+	}
+
+	plainText := buf.Bytes()
+	padding := aes.BlockSize - len(plainText)%aes.BlockSize
+	plainText = append(plainText, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	block, err := aes.NewCipher(checksum[:32])
+	require.NoError(t, err)
+	cipherText := make([]byte, len(plainText))
+	cipher.NewCBCEncrypter(block, checksum[32:48]).CryptBlocks(cipherText, plainText)
+
+	data, err := json.Marshal(&KeosdWallet{CipherKeys: cipherText})
+	require.NoError(t, err)
+	return data
+}
+
+func TestDecryptKeosdWallet(t *testing.T) {
+	privateKey, err := ecc.NewPrivateKey("5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3")
+	require.NoError(t, err)
+
+	wif, err := btcutil.DecodeWIF(privateKey.String())
+	require.NoError(t, err)
+	k1KeyData := append([]byte{byte(ecc.CurveK1)}, wif.PrivKey.Serialize()...)
+
+	r1Key, err := ecc.NewPrivateKeyFromData(testR1KeyData)
+	require.NoError(t, err)
+
+	data := encryptTestKeosdWallet(t, "PW5KFWYKqvt63d4iNvedfDEPVZL227D3RQ1zpVFzuUwhMAJmRAYyX", k1KeyData, testR1KeyData)
+
+	keystore, err := DecryptKeosdWallet(data, "PW5KFWYKqvt63d4iNvedfDEPVZL227D3RQ1zpVFzuUwhMAJmRAYyX")
+	require.NoError(t, err)
+	require.Len(t, keystore.Keys, 2)
+	assert.Equal(t, privateKey.PublicKey().String(), keystore.Keys[0].Name)
+	assert.Equal(t, privateKey.String(), keystore.Keys[0].PrivateKey.String())
+	assert.Equal(t, r1Key.PublicKey().String(), keystore.Keys[1].Name)
+	assert.Equal(t, r1Key.String(), keystore.Keys[1].PrivateKey.String())
+	assert.Regexp(t, `^PUB_R1_`, keystore.Keys[1].Name)
+
+	_, err = DecryptKeosdWallet(data, "wrong")
+	assert.Equal(t, ErrKeystoreWrongPassword, err)
+}
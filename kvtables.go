@@ -0,0 +1,244 @@
+package eos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// IndexType returns the key type of the index `indexName` of the
+// table, either its primary index or one of its secondary indices.
+func (t *KVTableDef) IndexType(indexName Name) (string, bool) {
+	if t.PrimaryIndex.Name == indexName {
+		return t.PrimaryIndex.Type, true
+	}
+	if index, found := t.SecondaryIndices[indexName]; found {
+		return index.Type, true
+	}
+	return "", false
+}
+
+// EncodeKVTableKey encodes `value` as a key of the index `indexName` of
+// the kv table `tableName`, the order-preserving way contracts store
+// them. The value can be a Go bool, integer, float or string (names
+// and numbers can be given as strings).
+func (a *ABI) EncodeKVTableKey(tableName TableName, indexName Name, value interface{}) ([]byte, error) {
+	table := a.KVTableForName(tableName)
+	if table == nil {
+		return nil, fmt.Errorf("kv table %s not found in abi", tableName)
+	}
+
+	keyType, found := table.IndexType(indexName)
+	if !found {
+		return nil, fmt.Errorf("index %s not found in kv table %s", indexName, tableName)
+	}
+
+	keyType, _ = a.TypeNameForNewTypeName(keyType)
+	key, err := EncodeKVKey(keyType, value)
+	if err != nil {
+		return nil, fmt.Errorf("kv table %s, index %s: %w", tableName, indexName, err)
+	}
+	return key, nil
+}
+
+// EncodeKVKey encodes `value` as a kv table key of the ABI type
+// `keyType`: integers and names are big endian with the sign bit of
+// signed integers flipped, and strings are escaped and terminated so
+// that keys sort like their values.
+func EncodeKVKey(keyType string, value interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+
+	switch keyType {
+	case "bool":
+		v, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a bool for key type %s, got %T", keyType, value)
+		}
+		if v {
+			buffer.WriteByte(1)
+		} else {
+			buffer.WriteByte(0)
+		}
+
+	case "uint8", "uint16", "uint32", "uint64":
+		v, err := kvKeyUint(value)
+		if err != nil {
+			return nil, fmt.Errorf("key type %s: %w", keyType, err)
+		}
+		size := kvKeySize(keyType)
+		if size < 8 && v >= 1<<(size*8) {
+			return nil, fmt.Errorf("value %d overflows key type %s", v, keyType)
+		}
+		writeKVKeyUint(buffer, v, size)
+
+	case "int8", "int16", "int32", "int64":
+		v, err := kvKeyInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("key type %s: %w", keyType, err)
+		}
+		size := kvKeySize(keyType)
+		if size < 8 && (v < -(1<<(size*8-1)) || v >= 1<<(size*8-1)) {
+			return nil, fmt.Errorf("value %d overflows key type %s", v, keyType)
+		}
+		writeKVKeyUint(buffer, uint64(v)^(1<<(size*8-1)), size)
+
+	case "float32":
+		v, err := kvKeyFloat(value)
+		if err != nil {
+			return nil, fmt.Errorf("key type %s: %w", keyType, err)
+		}
+		bits := math.Float32bits(float32(v))
+		if bits&(1<<31) != 0 {
+			bits = ^bits
+		} else {
+			bits |= 1 << 31
+		}
+		writeKVKeyUint(buffer, uint64(bits), 4)
+
+	case "float64":
+		v, err := kvKeyFloat(value)
+		if err != nil {
+			return nil, fmt.Errorf("key type %s: %w", keyType, err)
+		}
+		bits := math.Float64bits(v)
+		if bits&(1<<63) != 0 {
+			bits = ^bits
+		} else {
+			bits |= 1 << 63
+		}
+		writeKVKeyUint(buffer, bits, 8)
+
+	case "name":
+		v, ok := kvKeyString(value)
+		if !ok {
+			return nil, fmt.Errorf("expected a name for key type %s, got %T", keyType, value)
+		}
+		name, err := StringToName(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name %q for key type %s: %w", v, keyType, err)
+		}
+		if NameToString(name) != v {
+			return nil, fmt.Errorf("%q is not a valid name for key type %s", v, keyType)
+		}
+		writeKVKeyUint(buffer, name, 8)
+
+	case "string":
+		v, ok := kvKeyString(value)
+		if !ok {
+			return nil, fmt.Errorf("expected a string for key type %s, got %T", keyType, value)
+		}
+		for i := 0; i < len(v); i++ {
+			buffer.WriteByte(v[i])
+			if v[i] == 0 {
+				buffer.WriteByte(1)
+			}
+		}
+		buffer.Write([]byte{0, 0})
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", keyType)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// EncodeBounds sets the lower and upper bounds of the request to the
+// keys encoded from `lower` and `upper` (nil to leave a bound unset)
+// with the ABI of the contract, using the `bytes` encode type.
+func (r *GetKVTableRowsRequest) EncodeBounds(abi *ABI, lower, upper interface{}) error {
+	if lower != nil {
+		key, err := abi.EncodeKVTableKey(r.Table, r.IndexName, lower)
+		if err != nil {
+			return fmt.Errorf("lower bound: %w", err)
+		}
+		r.LowerBound = hex.EncodeToString(key)
+	}
+
+	if upper != nil {
+		key, err := abi.EncodeKVTableKey(r.Table, r.IndexName, upper)
+		if err != nil {
+			return fmt.Errorf("upper bound: %w", err)
+		}
+		r.UpperBound = hex.EncodeToString(key)
+	}
+
+	r.EncodeType = "bytes"
+	return nil
+}
+
+func kvKeySize(keyType string) int {
+	switch keyType {
+	case "uint8", "int8":
+		return 1
+	case "uint16", "int16":
+		return 2
+	case "uint32", "int32":
+		return 4
+	}
+	return 8
+}
+
+func writeKVKeyUint(buffer *bytes.Buffer, v uint64, size int) {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, v)
+	buffer.Write(data[8-size:])
+}
+
+func kvKeyString(value interface{}) (string, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
+
+func kvKeyUint(value interface{}) (uint64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 {
+			return 0, fmt.Errorf("negative value %d", v.Int())
+		}
+		return uint64(v.Int()), nil
+	case reflect.String:
+		return strconv.ParseUint(v.String(), 10, 64)
+	}
+	return 0, fmt.Errorf("expected an unsigned integer, got %T", value)
+}
+
+func kvKeyInt(value interface{}) (int64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", v.Uint())
+		}
+		return int64(v.Uint()), nil
+	case reflect.String:
+		return strconv.ParseInt(v.String(), 10, 64)
+	}
+	return 0, fmt.Errorf("expected an integer, got %T", value)
+}
+
+func kvKeyFloat(value interface{}) (float64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.String:
+		return strconv.ParseFloat(v.String(), 64)
+	}
+	return 0, fmt.Errorf("expected a number, got %T", value)
+}
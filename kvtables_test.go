@@ -0,0 +1,145 @@
+package eos
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kvTablesTestABI = `{
+	"version": "eosio::abi/1.2",
+	"types": [{"new_type_name": "account_name", "type": "name"}],
+	"structs": [{"name": "row", "base": "", "fields": [
+		{"name": "owner", "type": "account_name"},
+		{"name": "title", "type": "string"},
+		{"name": "score", "type": "int32"}
+	]}],
+	"kv_tables": {
+		"scores": {
+			"type": "row",
+			"primary_index": {"name": "owner", "type": "account_name"},
+			"secondary_indices": {"title": {"type": "string"}, "score": {"type": "int32"}}
+		}
+	}
+}`
+
+func TestEncodeKVKey(t *testing.T) {
+	tests := []struct {
+		keyType  string
+		value    interface{}
+		expected string
+	}{
+		{"uint8", 5, "05"},
+		{"uint16", uint16(0x1234), "1234"},
+		{"uint64", "1", "0000000000000001"},
+		{"int32", -1, "7fffffff"},
+		{"int32", 1, "80000001"},
+		{"int64", int64(0), "8000000000000000"},
+		{"float64", 1.0, "bff0000000000000"},
+		{"float64", -1.0, "400fffffffffffff"},
+		{"name", "eosio", "5530ea0000000000"},
+		{"name", AccountName("eosio"), "5530ea0000000000"},
+		{"string", "ab", "61620000"},
+		{"string", "a\x00b", "610001620000"},
+		{"bool", true, "01"},
+	}
+
+	for _, test := range tests {
+		key, err := EncodeKVKey(test.keyType, test.value)
+		require.NoError(t, err, "%s %v", test.keyType, test.value)
+		assert.Equal(t, test.expected, hex.EncodeToString(key), "%s %v", test.keyType, test.value)
+	}
+
+	_, err := EncodeKVKey("uint8", 256)
+	assert.EqualError(t, err, "value 256 overflows key type uint8")
+	_, err = EncodeKVKey("uint32", -1)
+	assert.EqualError(t, err, "key type uint32: negative value -1")
+	_, err = EncodeKVKey("checksum256", "00")
+	assert.EqualError(t, err, "unsupported key type checksum256")
+	_, err = EncodeKVKey("name", "Alice!")
+	assert.Error(t, err)
+	_, err = EncodeKVKey("name", "eosio.tokenzzzz")
+	assert.Error(t, err)
+}
+
+func TestEncodeKVKey_Ordering(t *testing.T) {
+	checkOrdering := func(keyType string, values ...interface{}) {
+		var keys [][]byte
+		for _, value := range values {
+			key, err := EncodeKVKey(keyType, value)
+			require.NoError(t, err)
+			keys = append(keys, key)
+		}
+		assert.True(t, sort.SliceIsSorted(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 }), keyType)
+	}
+
+	checkOrdering("int64", -1000, -1, 0, 1, 1000)
+	checkOrdering("float64", -10.5, -1, 0, 0.5, 1, 100)
+	checkOrdering("float32", -10.5, -1, 0, 0.5, 1, 100)
+	checkOrdering("string", "", "a", "a\x00", "aa", "b")
+	checkOrdering("name", "a", "alice", "bob", "eosio", "eosio.token")
+}
+
+func TestGetKVTableRows(t *testing.T) {
+	abi, err := NewABI(strings.NewReader(kvTablesTestABI))
+	require.NoError(t, err)
+	assert.Empty(t, abi.Validate())
+
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chain/get_kv_table_rows", r.URL.Path)
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+
+		w.Write([]byte(`{"rows": [{"owner": "alice", "title": "first", "score": -5}], "more": true, "next_key": "8000000a", "next_key_bytes": "8000000a"}`))
+	}))
+	defer server.Close()
+
+	request := GetKVTableRowsRequest{Code: "contract", Table: "scores", IndexName: "score", Limit: 1, JSON: true}
+	require.NoError(t, request.EncodeBounds(abi, -10, nil))
+
+	api := New(server.URL)
+	api.HttpClient = server.Client()
+	resp, err := api.GetKVTableRows(context.Background(), request)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"code":        "contract",
+		"table":       "scores",
+		"index_name":  "score",
+		"encode_type": "bytes",
+		"lower_bound": "7ffffff6",
+		"limit":       float64(1),
+		"json":        true,
+	}, received)
+
+	assert.True(t, resp.More)
+	assert.Equal(t, "8000000a", resp.NextKeyBytes)
+
+	type row struct {
+		Owner AccountName `json:"owner"`
+		Title string      `json:"title"`
+		Score int32       `json:"score"`
+	}
+	var rows []row
+	require.NoError(t, resp.JSONToStructs(&rows))
+	assert.Equal(t, []row{{Owner: "alice", Title: "first", Score: -5}}, rows)
+
+	key, err := abi.EncodeKVTableKey("scores", "owner", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "345c850000000000", hex.EncodeToString(key))
+
+	_, err = abi.EncodeKVTableKey("scores", "missing", "alice")
+	assert.EqualError(t, err, "index missing not found in kv table scores")
+}
@@ -0,0 +1,261 @@
+package eos
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eoscanada/eos-go/ecc"
+)
+
+// IdentityProofPrefix prefixes the string format of identity proofs,
+// as used in `Authorization: EOSIO <proof>` headers.
+const IdentityProofPrefix = "EOSIO "
+
+var ErrUnsatisfiedPermission = errors.New("permission not satisfied")
+var ErrIdentityProofExpired = errors.New("identity proof expired")
+var ErrIdentityProofScope = errors.New("identity proof scope mismatch")
+
+// MessageDigest returns the digest signed for `message` on the chain
+// `chainID`, in the domain `domain` (like `example.com login`), which
+// keeps signatures made for an application from being replayed to
+// another one, or on another chain.
+//
+// The digest is the SHA256 hash of the chain ID followed by the SHA256
+// hashes of the domain and of the message. It has the layout of a
+// transaction signing digest, but of a 32 bytes transaction which
+// cannot hold an authorized action, so that signing a message never
+// signs a transaction.
+func MessageDigest(chainID Checksum256, domain string, message []byte) Checksum256 {
+	domainHash := sha256.Sum256([]byte(domain))
+	messageHash := sha256.Sum256(message)
+
+	h := sha256.New()
+	if len(chainID) == 0 {
+		_, _ = h.Write(make([]byte, 32))
+	} else {
+		_, _ = h.Write(chainID)
+	}
+	_, _ = h.Write(domainHash[:])
+	_, _ = h.Write(messageHash[:])
+	return h.Sum(nil)
+}
+
+// SignMessage signs `message` with `privKey` for the chain `chainID`,
+// in the domain `domain`, see `MessageDigest`.
+func SignMessage(privKey *ecc.PrivateKey, chainID Checksum256, domain string, message []byte) (ecc.Signature, error) {
+	return privKey.Sign(MessageDigest(chainID, domain, message))
+}
+
+// RecoverMessageKey returns the public key which signed `message` for
+// the chain `chainID`, in the domain `domain`.
+func RecoverMessageKey(signature ecc.Signature, chainID Checksum256, domain string, message []byte) (ecc.PublicKey, error) {
+	return signature.PublicKey(MessageDigest(chainID, domain, message))
+}
+
+// VerifyMessage checks that `message` was signed for the chain
+// `chainID`, in the domain `domain`, by a key satisfying alone the
+// permission `signer`, see `VerifyPermissionKey`. It returns the key
+// which signed.
+func (api *API) VerifyMessage(ctx context.Context, signer PermissionLevel, signature ecc.Signature, chainID Checksum256, domain string, message []byte) (ecc.PublicKey, error) {
+	key, err := RecoverMessageKey(signature, chainID, domain, message)
+	if err != nil {
+		return key, fmt.Errorf("recover key: %w", err)
+	}
+
+	return key, api.VerifyPermissionKey(ctx, signer, key)
+}
+
+// VerifyPermissionKey checks that `key` alone satisfies the authority
+// of the permission `level`, fetching with `GetAccount` the account of
+// the permission and the ones its authority delegates to.
+func (api *API) VerifyPermissionKey(ctx context.Context, level PermissionLevel, key ecc.PublicKey) error {
+	evaluator := NewAuthorizationEvaluator()
+
+	accounts := map[AccountName]*AccountResp{}
+	pending := []PermissionLevel{level}
+	for depth := 0; len(pending) > 0 && depth <= evaluator.MaxAuthorityDepth; depth++ {
+		var next []PermissionLevel
+		for _, pendingLevel := range pending {
+			account := accounts[pendingLevel.Actor]
+			if account == nil {
+				var err error
+				if account, err = api.GetAccount(ctx, pendingLevel.Actor); err != nil {
+					return fmt.Errorf("get account %s: %w", pendingLevel.Actor, err)
+				}
+				accounts[pendingLevel.Actor] = account
+				evaluator.AddAccount(account)
+			}
+
+			for _, permission := range account.Permissions {
+				if PermissionName(permission.PermName) != pendingLevel.Permission {
+					continue
+				}
+				for _, delegated := range permission.RequiredAuth.Accounts {
+					next = append(next, delegated.Permission)
+				}
+			}
+		}
+		pending = next
+	}
+
+	report := evaluator.EvaluatePermission(level, []ecc.PublicKey{key})
+	if report.Err != nil {
+		return report.Err
+	}
+
+	if !report.Satisfied {
+		return fmt.Errorf("%w: key %s does not satisfy %s@%s", ErrUnsatisfiedPermission, key, level.Actor, level.Permission)
+	}
+
+	return nil
+}
+
+// IdentityProof is the proof, returned by wallets like Anchor for
+// identity signing requests (ESR), that the signer controls a
+// permission of an account. The signature is the one of an identity
+// transaction, see `IdentityProof.Transaction`, which cannot be
+// executed.
+type IdentityProof struct {
+	ChainID    Checksum256     `json:"chainId"`
+	Scope      Name            `json:"scope"`
+	Expiration TimePointSec    `json:"expiration"`
+	Signer     PermissionLevel `json:"signer"`
+	Signature  ecc.Signature   `json:"signature"`
+}
+
+// identityV3 is the `identity` action data of version 3 signing
+// requests.
+type identityV3 struct {
+	Scope      Name
+	Permission *PermissionLevel `eos:"optional"`
+}
+
+// NewIdentityProof signs with `privKey` an identity proof of `signer`
+// for the application `scope` on the chain `chainID`, valid until
+// `expiration`.
+func NewIdentityProof(privKey *ecc.PrivateKey, chainID Checksum256, scope Name, signer PermissionLevel, expiration time.Time) (*IdentityProof, error) {
+	proof := &IdentityProof{
+		ChainID:    chainID,
+		Scope:      scope,
+		Expiration: TimePointSec(expiration.Unix()),
+		Signer:     signer,
+	}
+
+	digest, err := proof.SigningDigest()
+	if err != nil {
+		return nil, err
+	}
+
+	if proof.Signature, err = privKey.Sign(digest); err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// ParseIdentityProof parses the string format of identity proofs,
+// `EOSIO ` followed by the URL-safe base64 encoding of the proof.
+func ParseIdentityProof(in string) (*IdentityProof, error) {
+	if !strings.HasPrefix(in, IdentityProofPrefix) {
+		return nil, fmt.Errorf("identity proof should start with %q", IdentityProofPrefix)
+	}
+
+	encoded := strings.TrimRight(in[len(IdentityProofPrefix):], "=")
+	encoded = strings.NewReplacer("+", "-", "/", "_").Replace(encoded)
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("identity proof base64: %w", err)
+	}
+
+	proof := &IdentityProof{}
+	if err := UnmarshalBinary(data, proof); err != nil {
+		return nil, fmt.Errorf("identity proof: %w", err)
+	}
+
+	return proof, nil
+}
+
+// String returns the proof in the format of `ParseIdentityProof`.
+func (p *IdentityProof) String() string {
+	data, err := MarshalBinary(p)
+	if err != nil {
+		return ""
+	}
+	return IdentityProofPrefix + base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Transaction returns the identity transaction signed by the proof, an
+// `identity` action of the empty account authorized by the signer, with
+// no reference block.
+func (p *IdentityProof) Transaction() *Transaction {
+	signer := p.Signer
+	action := &Action{
+		Account:       AccountName(""),
+		Name:          ActionName("identity"),
+		Authorization: []PermissionLevel{signer},
+		ActionData:    NewActionData(&identityV3{Scope: p.Scope, Permission: &signer}),
+	}
+
+	return &Transaction{
+		TransactionHeader: TransactionHeader{
+			Expiration: JSONTime{p.Expiration.AsTime()},
+		},
+		Actions: []*Action{action},
+	}
+}
+
+// SigningDigest returns the signing digest of the identity transaction
+// on the chain of the proof.
+func (p *IdentityProof) SigningDigest() ([]byte, error) {
+	packed, err := MarshalBinary(p.Transaction())
+	if err != nil {
+		return nil, fmt.Errorf("pack identity transaction: %w", err)
+	}
+	return SigDigest(p.ChainID, packed, nil), nil
+}
+
+// RecoverKey returns the public key which signed the proof.
+func (p *IdentityProof) RecoverKey() (ecc.PublicKey, error) {
+	digest, err := p.SigningDigest()
+	if err != nil {
+		return ecc.PublicKey{}, err
+	}
+	return p.Signature.PublicKey(digest)
+}
+
+// VerifyIdentityProof checks that the proof was made for the application
+// `scope`, that it is not expired, that it was made for the chain of the
+// node and that the key which signed it satisfies alone the signer's
+// permission, see `VerifyPermissionKey`. It returns the key which
+// signed.
+func (api *API) VerifyIdentityProof(ctx context.Context, proof *IdentityProof, scope Name) (ecc.PublicKey, error) {
+	if proof.Scope != scope {
+		return ecc.PublicKey{}, fmt.Errorf("%w: identity proof is for scope %s, not %s", ErrIdentityProofScope, proof.Scope, scope)
+	}
+
+	if !time.Now().Before(proof.Expiration.AsTime()) {
+		return ecc.PublicKey{}, fmt.Errorf("%w at %s", ErrIdentityProofExpired, proof.Expiration)
+	}
+
+	info, err := api.GetInfo(ctx)
+	if err != nil {
+		return ecc.PublicKey{}, fmt.Errorf("get info: %w", err)
+	}
+
+	if !bytes.Equal(info.ChainID, proof.ChainID) {
+		return ecc.PublicKey{}, fmt.Errorf("identity proof is for chain %s, not %s", proof.ChainID, info.ChainID)
+	}
+
+	key, err := proof.RecoverKey()
+	if err != nil {
+		return key, fmt.Errorf("recover key: %w", err)
+	}
+
+	return key, api.VerifyPermissionKey(ctx, proof.Signer, key)
+}
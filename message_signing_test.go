@@ -0,0 +1,252 @@
+package eos
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eoscanada/eos-go/ecc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMessageChainID = hexToChecksum256("aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906")
+
+func TestMessageDigest(t *testing.T) {
+	digest := MessageDigest(testMessageChainID, "example.com login", []byte("hello"))
+	assert.Len(t, digest, 32)
+
+	assert.NotEqual(t, digest, MessageDigest(testMessageChainID, "example.org login", []byte("hello")))
+	assert.NotEqual(t, digest, MessageDigest(Checksum256(make([]byte, 32)), "example.com login", []byte("hello")))
+	assert.NotEqual(t, digest, MessageDigest(testMessageChainID, "example.com login", []byte("hellO")))
+	assert.Equal(t, MessageDigest(nil, "", nil), MessageDigest(Checksum256(make([]byte, 32)), "", nil))
+}
+
+func TestSignMessage(t *testing.T) {
+	privKey, err := ecc.NewPrivateKey("5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3")
+	require.NoError(t, err)
+
+	signature, err := SignMessage(privKey, testMessageChainID, "example.com login", []byte("hello"))
+	require.NoError(t, err)
+
+	key, err := RecoverMessageKey(signature, testMessageChainID, "example.com login", []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, privKey.PublicKey().String(), key.String())
+
+	key, err = RecoverMessageKey(signature, testMessageChainID, "example.org login", []byte("hello"))
+	require.NoError(t, err)
+	assert.NotEqual(t, privKey.PublicKey().String(), key.String())
+}
+
+func TestIdentityProof(t *testing.T) {
+	privKey, err := ecc.NewPrivateKey("5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3")
+	require.NoError(t, err)
+
+	expiration := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	proof, err := NewIdentityProof(privKey, testMessageChainID, "example", PermissionLevel{Actor: "alice", Permission: "active"}, expiration)
+	require.NoError(t, err)
+
+	packed, err := MarshalBinary(proof.Transaction())
+	require.NoError(t, err)
+	assert.Equal(t, ""+
+		"2555dd70"+ // expiration
+		"0000"+"00000000"+ // ref block num and prefix
+		"00"+"00"+"00"+ // max net usage, max cpu usage and delay
+		"00"+ // context free actions
+		"01"+ // actions
+		"0000000000000000"+"0000003ebb3c5572"+ // '' and identity
+		"01"+"0000000000855c34"+"00000000a8ed3232"+ // alice@active
+		"19"+"00000040c52a4d57"+"01"+"0000000000855c34"+"00000000a8ed3232"+ // example and alice@active
+		"00", // transaction extensions
+		hex.EncodeToString(packed))
+
+	key, err := proof.RecoverKey()
+	require.NoError(t, err)
+	assert.Equal(t, privKey.PublicKey().String(), key.String())
+
+	signed := NewSignedTransaction(proof.Transaction())
+	signed.Signatures = []ecc.Signature{proof.Signature}
+	keys, err := signed.SignedByKeys(testMessageChainID)
+	require.NoError(t, err)
+	assert.Equal(t, []ecc.PublicKey{key}, keys)
+
+	encoded := proof.String()
+	assert.Regexp(t, `^EOSIO [A-Za-z0-9_-]+$`, encoded)
+
+	parsed, err := ParseIdentityProof(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, proof.ChainID, parsed.ChainID)
+	assert.Equal(t, proof.Scope, parsed.Scope)
+	assert.Equal(t, proof.Expiration, parsed.Expiration)
+	assert.Equal(t, proof.Signer, parsed.Signer)
+	assert.Equal(t, proof.Signature.String(), parsed.Signature.String())
+
+	_, err = ParseIdentityProof("Bearer abc")
+	assert.Error(t, err)
+}
+
+// identityProofTestABI holds the `identity` (version 3) and
+// `identity_proof` types of the signing request (ESR) ABI.
+const identityProofTestABI = `{
+	"version": "eosio::abi/1.1",
+	"structs": [
+		{"name": "permission_level", "base": "", "fields": [
+			{"name": "actor", "type": "name"},
+			{"name": "permission", "type": "name"}
+		]},
+		{"name": "identity", "base": "", "fields": [
+			{"name": "scope", "type": "name"},
+			{"name": "permission", "type": "permission_level?"}
+		]},
+		{"name": "identity_proof", "base": "", "fields": [
+			{"name": "chain_id", "type": "checksum256"},
+			{"name": "scope", "type": "name"},
+			{"name": "expiration", "type": "time_point_sec"},
+			{"name": "signer", "type": "permission_level"},
+			{"name": "signature", "type": "signature"}
+		]}
+	]
+}`
+
+func TestIdentityProof_ESRABI(t *testing.T) {
+	abi, err := NewABI(strings.NewReader(identityProofTestABI))
+	require.NoError(t, err)
+
+	privKey, err := ecc.NewPrivateKey("5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3")
+	require.NoError(t, err)
+
+	proof, err := NewIdentityProof(privKey, testMessageChainID, "example", PermissionLevel{Actor: "alice", Permission: "active"}, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+
+	identity, err := abi.EncodeStruct("identity", []byte(`{"scope": "example", "permission": {"actor": "alice", "permission": "active"}}`))
+	require.NoError(t, err)
+	action := proof.Transaction().Actions[0]
+	packedAction, err := MarshalBinary(action)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(identity), hex.EncodeToString(packedAction[len(packedAction)-len(identity):]))
+
+	encoded, err := abi.EncodeStruct("identity_proof", []byte(`{
+		"chain_id": "`+proof.ChainID.String()+`",
+		"scope": "example",
+		"expiration": "2030-01-02T03:04:05",
+		"signer": {"actor": "alice", "permission": "active"},
+		"signature": "`+proof.Signature.String()+`"
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, IdentityProofPrefix+base64.RawURLEncoding.EncodeToString(encoded), proof.String())
+}
+
+func TestVerifyIdentityProof(t *testing.T) {
+	alice, err := ecc.NewPrivateKey("5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3")
+	require.NoError(t, err)
+	other, err := ecc.NewPrivateKey("5KYZdUEo39z3FPrtuX2QbbwGnNP5zTd7yyr2SC1j299sBCnWjss")
+	require.NoError(t, err)
+
+	accounts := map[string]*AccountResp{
+		"alice": {
+			AccountName: "alice",
+			Permissions: []Permission{
+				{PermName: "active", Parent: "owner", RequiredAuth: Authority{Threshold: 1, Keys: []KeyWeight{{PublicKey: alice.PublicKey(), Weight: 1}}}},
+				{PermName: "owner", RequiredAuth: Authority{Threshold: 1, Keys: []KeyWeight{{PublicKey: other.PublicKey(), Weight: 1}}}},
+			},
+		},
+		"bob": {
+			AccountName: "bob",
+			Permissions: []Permission{
+				{PermName: "active", Parent: "owner", RequiredAuth: Authority{Threshold: 1, Accounts: []PermissionLevelWeight{{Permission: PermissionLevel{Actor: "alice", Permission: "active"}, Weight: 1}}}},
+				{PermName: "owner", RequiredAuth: Authority{Threshold: 2, Keys: []KeyWeight{{PublicKey: alice.PublicKey(), Weight: 1}, {PublicKey: other.PublicKey(), Weight: 1}}}},
+			},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chain/get_info":
+			json.NewEncoder(w).Encode(&InfoResp{ChainID: testMessageChainID})
+		case "/v1/chain/get_account":
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var request struct {
+				AccountName string `json:"account_name"`
+			}
+			require.NoError(t, json.Unmarshal(body, &request))
+
+			account := accounts[request.AccountName]
+			if account == nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"code": 500, "message": "Internal Service Error", "error": {"code": 0, "name": "exception", "what": "unknown key"}}`))
+				return
+			}
+			json.NewEncoder(w).Encode(account)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+	api.HttpClient = server.Client()
+	ctx := context.Background()
+	expiration := time.Now().Add(time.Minute)
+
+	verifyScope := func(privKey *ecc.PrivateKey, chainID Checksum256, scope Name, signer string, expiration time.Time) error {
+		level, err := NewPermissionLevel(signer)
+		require.NoError(t, err)
+
+		proof, err := NewIdentityProof(privKey, chainID, scope, level, expiration)
+		require.NoError(t, err)
+
+		parsed, err := ParseIdentityProof(proof.String())
+		require.NoError(t, err)
+
+		key, err := api.VerifyIdentityProof(ctx, parsed, "example")
+		if err == nil {
+			assert.Equal(t, privKey.PublicKey().String(), key.String())
+		}
+		return err
+	}
+	verify := func(privKey *ecc.PrivateKey, chainID Checksum256, signer string, expiration time.Time) error {
+		return verifyScope(privKey, chainID, "example", signer, expiration)
+	}
+
+	assert.NoError(t, verify(alice, testMessageChainID, "alice@active", expiration))
+	assert.NoError(t, verify(other, testMessageChainID, "alice@owner", expiration))
+	assert.NoError(t, verify(alice, testMessageChainID, "bob@active", expiration))
+
+	err = verify(other, testMessageChainID, "alice@active", expiration)
+	assert.True(t, errors.Is(err, ErrUnsatisfiedPermission), err)
+
+	err = verify(alice, testMessageChainID, "bob@owner", expiration)
+	assert.True(t, errors.Is(err, ErrUnsatisfiedPermission), err)
+
+	err = verify(alice, testMessageChainID, "alice@custom", expiration)
+	assert.True(t, errors.Is(err, ErrUnknownPermission), err)
+
+	err = verify(alice, testMessageChainID, "alice@active", time.Now().Add(-time.Minute))
+	assert.True(t, errors.Is(err, ErrIdentityProofExpired), err)
+
+	err = verifyScope(alice, testMessageChainID, "otherapp", "alice@active", expiration)
+	assert.True(t, errors.Is(err, ErrIdentityProofScope), err)
+
+	assert.Error(t, verify(alice, Checksum256(make([]byte, 32)), "alice@active", expiration))
+	assert.Error(t, verify(alice, testMessageChainID, "carol@active", expiration))
+
+	signature, err := SignMessage(alice, testMessageChainID, "example.com login", []byte("hello"))
+	require.NoError(t, err)
+
+	key, err := api.VerifyMessage(ctx, PermissionLevel{Actor: "bob", Permission: "active"}, signature, testMessageChainID, "example.com login", []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, alice.PublicKey().String(), key.String())
+
+	_, err = api.VerifyMessage(ctx, PermissionLevel{Actor: "bob", Permission: "active"}, signature, testMessageChainID, "example.org login", []byte("hello"))
+	assert.True(t, errors.Is(err, ErrUnsatisfiedPermission), err)
+}
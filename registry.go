@@ -0,0 +1,147 @@
+package eos
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Registry maps the actions of contracts to the Go types their data
+// is decoded into. It can also hold the ABI of contracts, used to
+// decode the data of actions without a registered type to JSON.
+//
+// A `Registry` is safe for concurrent use. Packages like `token` or
+// `system` register their actions in `DefaultRegistry`, a `Decoder` or
+// an `Encoder` can be given their own registry with `SetRegistry`, to
+// use different contracts deployed under the same account names.
+type Registry struct {
+	lock    sync.RWMutex
+	actions map[AccountName]map[ActionName]reflect.Type
+	abis    map[AccountName]*ABI
+}
+
+// DefaultRegistry is the registry used when none is set.
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{
+		actions: map[AccountName]map[ActionName]reflect.Type{},
+		abis:    map[AccountName]*ABI{},
+	}
+}
+
+// RegisterAction registers the type of `obj` for the data of the action
+// `actionName` of the contract `accountName`.
+func (r *Registry) RegisterAction(accountName AccountName, actionName ActionName, obj interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.actions[accountName] == nil {
+		r.actions[accountName] = make(map[ActionName]reflect.Type)
+	}
+	r.actions[accountName][actionName] = reflect.TypeOf(obj)
+}
+
+// RegisterABI registers the ABI of the contract `accountName`, used to
+// decode the actions without a registered type to JSON, and to encode
+// back JSON data without its original bytes. A nil ABI removes the
+// registered one.
+func (r *Registry) RegisterABI(accountName AccountName, abi *ABI) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if abi == nil {
+		delete(r.abis, accountName)
+		return
+	}
+	r.abis[accountName] = abi
+}
+
+// ActionType returns the type registered for an action, or nil.
+func (r *Registry) ActionType(accountName AccountName, actionName ActionName) reflect.Type {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.actions[accountName][actionName]
+}
+
+// ABI returns the ABI registered for a contract, or nil.
+func (r *Registry) ABI(accountName AccountName) *ABI {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.abis[accountName]
+}
+
+// Clone returns a copy of the registry, for example to start from the
+// actions registered in `DefaultRegistry`.
+func (r *Registry) Clone() *Registry {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	out := NewRegistry()
+	for accountName, actions := range r.actions {
+		out.actions[accountName] = make(map[ActionName]reflect.Type, len(actions))
+		for actionName, objType := range actions {
+			out.actions[accountName][actionName] = objType
+		}
+	}
+	for accountName, abi := range r.abis {
+		out.abis[accountName] = abi
+	}
+	return out
+}
+
+// decodeActionData decodes the data of an action into its registered
+// type, or to a `json.RawMessage` through the ABI of the contract. It
+// returns nil when the action is unknown to the registry.
+func (r *Registry) decodeActionData(action *Action) (interface{}, error) {
+	if objType := r.ActionType(action.Account, action.Name); objType != nil {
+		if tracer.Enabled() {
+			zlog.Debug("reflect type", zap.String("type", objType.Name()))
+		}
+
+		obj := reflect.New(objType)
+		iface := obj.Interface()
+
+		decoder := NewDecoder(action.ActionData.HexData)
+		decoder.registry = r
+		if err := decoder.Decode(iface); err != nil {
+			return nil, fmt.Errorf("decoding Action [%s], %s", obj.Type().Name(), err)
+		}
+		return iface, nil
+	}
+
+	abi := r.ABI(action.Account)
+	if abi == nil {
+		return nil, nil
+	}
+
+	data, err := abi.DecodeAction(action.ActionData.HexData, action.Name)
+	if err != nil {
+		// The ABI might not match the data, like for actions of a
+		// contract updated since, keep the raw data only.
+		zlog.Debug("unable to decode action with abi",
+			zap.Stringer("account", action.Account),
+			zap.Stringer("action", action.Name),
+			zap.Error(err),
+		)
+		return nil, nil
+	}
+
+	return json.RawMessage(data), nil
+}
+
+// encodeActionData encodes the JSON data of an action through the ABI
+// of the contract.
+func (r *Registry) encodeActionData(account AccountName, name ActionName, data json.RawMessage) ([]byte, error) {
+	abi := r.ABI(account)
+	if abi == nil {
+		return nil, fmt.Errorf("no abi registered for %s to encode action %s", account, name)
+	}
+
+	return abi.EncodeAction(name, data)
+}
@@ -0,0 +1,172 @@
+package eos
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registryTestTransfer struct {
+	From   AccountName `json:"from"`
+	To     AccountName `json:"to"`
+	Amount Uint64      `json:"amount"`
+}
+
+type registryTestGreeting struct {
+	Message string `json:"message"`
+}
+
+const registryTestABI = `{
+	"version": "eosio::abi/1.1",
+	"structs": [{"name": "transfer", "base": "", "fields": [
+		{"name": "from", "type": "name"},
+		{"name": "to", "type": "name"},
+		{"name": "amount", "type": "uint64"}
+	]}],
+	"actions": [{"name": "transfer", "type": "transfer", "ricardian_contract": ""}]
+}`
+
+func registryTestAction(t *testing.T) *Action {
+	data, err := MarshalBinary(&registryTestTransfer{From: "alice", To: "bob", Amount: 10})
+	require.NoError(t, err)
+
+	return &Action{
+		Account:       "contract",
+		Name:          "transfer",
+		Authorization: []PermissionLevel{{Actor: "alice", Permission: "active"}},
+		ActionData:    NewActionDataFromHexData(data),
+	}
+}
+
+func decodeRegistryTestAction(t *testing.T, registry *Registry, action *Action) *Action {
+	data, err := MarshalBinary(action)
+	require.NoError(t, err)
+
+	decoder := NewDecoder(data)
+	decoder.SetRegistry(registry)
+
+	var out *Action
+	require.NoError(t, decoder.Decode(&out))
+	return out
+}
+
+func TestRegistry_Scoped(t *testing.T) {
+	chainA := NewRegistry()
+	chainA.RegisterAction("contract", "transfer", registryTestTransfer{})
+
+	chainB := NewRegistry()
+	chainB.RegisterAction("contract", "transfer", registryTestGreeting{})
+
+	action := registryTestAction(t)
+
+	decoded := decodeRegistryTestAction(t, chainA, action)
+	assert.Equal(t, &registryTestTransfer{From: "alice", To: "bob", Amount: 10}, decoded.Data)
+
+	// the same bytes are a (bogus) greeting for the other chain
+	decoded = decodeRegistryTestAction(t, chainB, action)
+	assert.IsType(t, &registryTestGreeting{}, decoded.Data)
+
+	decoded = decodeRegistryTestAction(t, NewRegistry(), action)
+	assert.Nil(t, decoded.Data)
+	assert.Equal(t, action.HexData, decoded.HexData)
+}
+
+func TestRegistry_ABIFallback(t *testing.T) {
+	abi, err := NewABI(strings.NewReader(registryTestABI))
+	require.NoError(t, err)
+
+	registry := NewRegistry()
+	registry.RegisterABI("contract", abi)
+
+	action := registryTestAction(t)
+	decoded := decodeRegistryTestAction(t, registry, action)
+	require.IsType(t, json.RawMessage{}, decoded.Data)
+	assert.JSONEq(t, `{"from":"alice","to":"bob","amount":10}`, string(decoded.Data.(json.RawMessage)))
+
+	encoder := func(action *Action) []byte {
+		out := &strings.Builder{}
+		e := NewEncoder(out)
+		e.SetRegistry(registry)
+		require.NoError(t, e.Encode(action))
+		return []byte(out.String())
+	}
+
+	expected, err := MarshalBinary(action)
+	require.NoError(t, err)
+	assert.Equal(t, expected, encoder(decoded))
+
+	// modified JSON data, without its original bytes, is encoded with
+	// the ABI
+	decoded.Data = json.RawMessage(`{"from":"alice","to":"carol","amount":10}`)
+	decoded.HexData = nil
+	action.HexData, err = MarshalBinary(&registryTestTransfer{From: "alice", To: "carol", Amount: 10})
+	require.NoError(t, err)
+	expected, err = MarshalBinary(action)
+	require.NoError(t, err)
+	assert.Equal(t, expected, encoder(decoded))
+
+	// registered types take precedence over the ABI
+	registry.RegisterAction("contract", "transfer", registryTestTransfer{})
+	decoded = decodeRegistryTestAction(t, registry, action)
+	assert.Equal(t, &registryTestTransfer{From: "alice", To: "carol", Amount: 10}, decoded.Data)
+
+	registry.RegisterABI("contract", nil)
+	assert.Nil(t, registry.ABI("contract"))
+}
+
+func TestRegistry_ABIFallbackRepack(t *testing.T) {
+	abi, err := NewABI(strings.NewReader(registryTestABI))
+	require.NoError(t, err)
+
+	registry := NewRegistry()
+	registry.RegisterABI("contract", abi)
+
+	// the trailing byte is ignored by the ABI, but part of the signed
+	// transaction
+	action := registryTestAction(t)
+	action.HexData = append(action.HexData, 0xaa)
+
+	tx := NewSignedTransaction(&Transaction{Actions: []*Action{action}})
+	packed, err := tx.Pack(CompressionNone)
+	require.NoError(t, err)
+
+	unpacked, err := packed.UnpackWithRegistry(registry)
+	require.NoError(t, err)
+	require.IsType(t, json.RawMessage{}, unpacked.Actions[0].Data)
+
+	repacked, err := unpacked.Pack(CompressionNone)
+	require.NoError(t, err)
+	assert.Equal(t, packed.PackedTransaction, repacked.PackedTransaction)
+
+	out := &strings.Builder{}
+	encoder := NewEncoder(out)
+	encoder.SetRegistry(registry)
+	require.NoError(t, encoder.Encode(unpacked.Transaction))
+	assert.Equal(t, []byte(packed.PackedTransaction), []byte(out.String()))
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	registry := DefaultRegistry.Clone()
+	action := registryTestAction(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				registry.RegisterAction("contract", ActionName(fmt.Sprintf("act%d", i)), registryTestGreeting{})
+				_, _ = registry.decodeActionData(action)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.NotNil(t, registry.ActionType("eosio.token", "transfer"), "clone should keep the default registrations")
+	assert.Nil(t, DefaultRegistry.ActionType("contract", "act0"), "clone should not modify the default registry")
+}
@@ -0,0 +1,182 @@
+package eos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/eoscanada/eos-go/ecc"
+)
+
+// The remote signer protocol is made of JSON requests, POSTed to the
+// following paths of the signer. Errors are returned with a non-200
+// status code and a `RemoteSignerError` body.
+const (
+	RemoteSignerKeysPath            = "/v1/signer/keys"
+	RemoteSignerSignDigestPath      = "/v1/signer/sign_digest"
+	RemoteSignerSignTransactionPath = "/v1/signer/sign_transaction"
+)
+
+// Error codes of the remote signer protocol.
+const (
+	RemoteSignerErrorBadRequest     = "bad_request"
+	RemoteSignerErrorKeyNotFound    = "key_not_found"
+	RemoteSignerErrorPolicyRejected = "policy_rejected"
+	RemoteSignerErrorInternal       = "internal_error"
+)
+
+var ErrRemoteSignerImportNotSupported = errors.New("importing private keys is not supported by remote signers")
+
+type RemoteSignerKeysResp struct {
+	Keys []ecc.PublicKey `json:"keys"`
+}
+
+type RemoteSignerSignDigestRequest struct {
+	ChainID   Checksum256   `json:"chain_id"`
+	Digest    Checksum256   `json:"digest"`
+	PublicKey ecc.PublicKey `json:"public_key"`
+}
+
+type RemoteSignerSignDigestResp struct {
+	Signature ecc.Signature `json:"signature"`
+}
+
+// RemoteSignerSignTransactionRequest holds the transaction to sign,
+// packed without compression, so that the signer computes the digest
+// from the exact bytes it inspects.
+type RemoteSignerSignTransactionRequest struct {
+	ChainID     Checksum256        `json:"chain_id"`
+	Transaction *PackedTransaction `json:"transaction"`
+	PublicKeys  []ecc.PublicKey    `json:"public_keys"`
+}
+
+type RemoteSignerSignTransactionResp struct {
+	Signatures []ecc.Signature `json:"signatures"`
+}
+
+type RemoteSignerError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *RemoteSignerError) Error() string {
+	return fmt.Sprintf("remote signer: %s: %s", e.Code, e.Message)
+}
+
+// RemoteSigner is a `Signer` delegating signatures to an out-of-process
+// signer, like an HSM front-end, speaking the remote signer protocol
+// (see `RemoteSignerServer`). Private keys never leave the signer.
+type RemoteSigner struct {
+	HttpClient *http.Client
+	BaseURL    string
+
+	// Header is one or more headers to be added to all outgoing calls,
+	// for example to authenticate with the signer.
+	Header http.Header
+}
+
+func NewRemoteSigner(baseURL string) *RemoteSigner {
+	return &RemoteSigner{
+		HttpClient: &http.Client{},
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Header:     http.Header{},
+	}
+}
+
+func (s *RemoteSigner) AvailableKeys(ctx context.Context) (out []ecc.PublicKey, err error) {
+	var resp RemoteSignerKeysResp
+	if err := s.call(ctx, RemoteSignerKeysPath, struct{}{}, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Keys, nil
+}
+
+// SignDigest signs a digest computed for the chain `chainID`.
+func (s *RemoteSigner) SignDigest(ctx context.Context, chainID Checksum256, digest Checksum256, key ecc.PublicKey) (ecc.Signature, error) {
+	var resp RemoteSignerSignDigestResp
+	err := s.call(ctx, RemoteSignerSignDigestPath, &RemoteSignerSignDigestRequest{
+		ChainID:   chainID,
+		Digest:    digest,
+		PublicKey: key,
+	}, &resp)
+	if err != nil {
+		return ecc.Signature{}, err
+	}
+
+	return resp.Signature, nil
+}
+
+func (s *RemoteSigner) Sign(ctx context.Context, tx *SignedTransaction, chainID []byte, requiredKeys ...ecc.PublicKey) (*SignedTransaction, error) {
+	packed, err := tx.Pack(CompressionNone)
+	if err != nil {
+		return nil, fmt.Errorf("pack transaction: %w", err)
+	}
+
+	var resp RemoteSignerSignTransactionResp
+	err = s.call(ctx, RemoteSignerSignTransactionPath, &RemoteSignerSignTransactionRequest{
+		ChainID:     chainID,
+		Transaction: packed,
+		PublicKeys:  requiredKeys,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.Signatures = append(tx.Signatures, resp.Signatures...)
+	return tx, nil
+}
+
+func (s *RemoteSigner) ImportPrivateKey(ctx context.Context, wifPrivKey string) error {
+	return ErrRemoteSignerImportNotSupported
+}
+
+func (s *RemoteSigner) call(ctx context.Context, path string, body interface{}, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.BaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	for k, v := range s.Header {
+		req.Header[k] = append(req.Header[k], v...)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.HttpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	cnt, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		signerErr := &RemoteSignerError{}
+		if err := json.Unmarshal(cnt, signerErr); err != nil || signerErr.Code == "" {
+			return fmt.Errorf("%s: status code=%d, body=%s", req.URL.String(), resp.StatusCode, string(cnt))
+		}
+
+		signerErr.StatusCode = resp.StatusCode
+		return signerErr
+	}
+
+	if err := json.Unmarshal(cnt, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
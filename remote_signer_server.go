@@ -0,0 +1,454 @@
+package eos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/eoscanada/eos-go/ecc"
+)
+
+// RemoteSignerBackend holds the private keys of a `RemoteSignerServer`,
+// like a `KeyBag` or an HSM.
+type RemoteSignerBackend interface {
+	AvailableKeys(ctx context.Context) (out []ecc.PublicKey, err error)
+	SignDigest(digest []byte, requiredKey ecc.PublicKey) (ecc.Signature, error)
+}
+
+// SigningRequest is what a `SigningPolicy` gets to inspect before
+// anything is signed.
+type SigningRequest struct {
+	ChainID Checksum256
+	// Transaction is nil when signing a raw digest. Its actions are not
+	// decoded, the `HexData` of each action holds the raw data.
+	Transaction *SignedTransaction
+	Digest      Checksum256
+	PublicKeys  []ecc.PublicKey
+}
+
+// SigningPolicy accepts or rejects a signing request, by returning an
+// error.
+type SigningPolicy interface {
+	Check(ctx context.Context, req *SigningRequest) error
+}
+
+// SigningPolicyCommitter is implemented by policies keeping state across
+// requests, like spent amounts. Once a policy accepted a request, either
+// `Commit` is called, when all the policies accepted it and it was
+// signed, or `Rollback`.
+type SigningPolicyCommitter interface {
+	Commit(ctx context.Context, req *SigningRequest)
+	Rollback(ctx context.Context, req *SigningRequest)
+}
+
+type SigningPolicyFunc func(ctx context.Context, req *SigningRequest) error
+
+func (f SigningPolicyFunc) Check(ctx context.Context, req *SigningRequest) error {
+	return f(ctx, req)
+}
+
+var ErrDigestSigningNotAllowed = errors.New("signing raw digests is not allowed")
+
+// RemoteSignerServer serves the remote signer protocol over HTTP, for
+// `RemoteSigner` clients, signing with the keys of `backend`. Each
+// request must be accepted by all policies before being signed.
+type RemoteSignerServer struct {
+	backend  RemoteSignerBackend
+	policies []SigningPolicy
+}
+
+func NewRemoteSignerServer(backend RemoteSignerBackend, policies ...SigningPolicy) *RemoteSignerServer {
+	return &RemoteSignerServer{
+		backend:  backend,
+		policies: policies,
+	}
+}
+
+func (s *RemoteSignerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeRemoteSignerError(w, http.StatusMethodNotAllowed, RemoteSignerErrorBadRequest, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	switch r.URL.Path {
+	case RemoteSignerKeysPath:
+		s.serveKeys(w, r)
+	case RemoteSignerSignDigestPath:
+		s.serveSignDigest(w, r)
+	case RemoteSignerSignTransactionPath:
+		s.serveSignTransaction(w, r)
+	default:
+		writeRemoteSignerError(w, http.StatusNotFound, RemoteSignerErrorBadRequest, fmt.Errorf("unknown path %q", r.URL.Path))
+	}
+}
+
+func (s *RemoteSignerServer) serveKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := s.backend.AvailableKeys(r.Context())
+	if err != nil {
+		writeRemoteSignerError(w, http.StatusInternalServerError, RemoteSignerErrorInternal, err)
+		return
+	}
+
+	writeRemoteSignerResponse(w, &RemoteSignerKeysResp{Keys: keys})
+}
+
+func (s *RemoteSignerServer) serveSignDigest(w http.ResponseWriter, r *http.Request) {
+	var req RemoteSignerSignDigestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeRemoteSignerError(w, http.StatusBadRequest, RemoteSignerErrorBadRequest, fmt.Errorf("decode request: %w", err))
+		return
+	}
+
+	if len(req.Digest) != 32 {
+		writeRemoteSignerError(w, http.StatusBadRequest, RemoteSignerErrorBadRequest, fmt.Errorf("digest should be 32 bytes, got %d", len(req.Digest)))
+		return
+	}
+
+	signatures, ok := s.sign(w, r.Context(), &SigningRequest{
+		ChainID:    req.ChainID,
+		Digest:     req.Digest,
+		PublicKeys: []ecc.PublicKey{req.PublicKey},
+	})
+	if !ok {
+		return
+	}
+
+	writeRemoteSignerResponse(w, &RemoteSignerSignDigestResp{Signature: signatures[0]})
+}
+
+func (s *RemoteSignerServer) serveSignTransaction(w http.ResponseWriter, r *http.Request) {
+	var req RemoteSignerSignTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeRemoteSignerError(w, http.StatusBadRequest, RemoteSignerErrorBadRequest, fmt.Errorf("decode request: %w", err))
+		return
+	}
+
+	packed := req.Transaction
+	if packed == nil {
+		writeRemoteSignerError(w, http.StatusBadRequest, RemoteSignerErrorBadRequest, errors.New("missing transaction"))
+		return
+	}
+
+	// The digest is computed over the uncompressed bytes, which are the
+	// ones the policies inspect.
+	if packed.Compression != CompressionNone {
+		writeRemoteSignerError(w, http.StatusBadRequest, RemoteSignerErrorBadRequest, errors.New("transaction should not be compressed"))
+		return
+	}
+
+	tx, err := packed.UnpackBare()
+	if err != nil {
+		writeRemoteSignerError(w, http.StatusBadRequest, RemoteSignerErrorBadRequest, err)
+		return
+	}
+
+	signatures, ok := s.sign(w, r.Context(), &SigningRequest{
+		ChainID:     req.ChainID,
+		Transaction: tx,
+		Digest:      SigDigest(req.ChainID, packed.PackedTransaction, packed.PackedContextFreeData),
+		PublicKeys:  req.PublicKeys,
+	})
+	if !ok {
+		return
+	}
+
+	writeRemoteSignerResponse(w, &RemoteSignerSignTransactionResp{Signatures: signatures})
+}
+
+// sign checks the policies then signs the digest with every requested
+// key, writing the error response when it fails. The policies keeping
+// state commit the request only once it is signed.
+func (s *RemoteSignerServer) sign(w http.ResponseWriter, ctx context.Context, req *SigningRequest) ([]ecc.Signature, bool) {
+	available, err := s.backend.AvailableKeys(ctx)
+	if err != nil {
+		writeRemoteSignerError(w, http.StatusInternalServerError, RemoteSignerErrorInternal, err)
+		return nil, false
+	}
+
+	for _, key := range req.PublicKeys {
+		if !containsPublicKey(available, key) {
+			writeRemoteSignerError(w, http.StatusNotFound, RemoteSignerErrorKeyNotFound, fmt.Errorf("no private key for %s", key))
+			return nil, false
+		}
+	}
+
+	var accepted []SigningPolicy
+	rollback := func() {
+		for _, policy := range accepted {
+			if committer, ok := policy.(SigningPolicyCommitter); ok {
+				committer.Rollback(ctx, req)
+			}
+		}
+	}
+
+	for _, policy := range s.policies {
+		if err := policy.Check(ctx, req); err != nil {
+			rollback()
+			zlog.Debug("remote signer policy rejected request")
+			writeRemoteSignerError(w, http.StatusForbidden, RemoteSignerErrorPolicyRejected, err)
+			return nil, false
+		}
+		accepted = append(accepted, policy)
+	}
+
+	signatures := make([]ecc.Signature, 0, len(req.PublicKeys))
+	for _, key := range req.PublicKeys {
+		sig, err := s.backend.SignDigest(req.Digest, key)
+		if err != nil {
+			rollback()
+			writeRemoteSignerError(w, http.StatusInternalServerError, RemoteSignerErrorInternal, err)
+			return nil, false
+		}
+		signatures = append(signatures, sig)
+	}
+
+	for _, policy := range accepted {
+		if committer, ok := policy.(SigningPolicyCommitter); ok {
+			committer.Commit(ctx, req)
+		}
+	}
+
+	return signatures, true
+}
+
+func containsPublicKey(keys []ecc.PublicKey, key ecc.PublicKey) bool {
+	for _, k := range keys {
+		if k.String() == key.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func writeRemoteSignerResponse(w http.ResponseWriter, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func writeRemoteSignerError(w http.ResponseWriter, statusCode int, code string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(&RemoteSignerError{Code: code, Message: err.Error()})
+}
+
+// AllowActions is a policy only accepting transactions made of the
+// listed actions, written `contract::action`, or `contract::*` to allow
+// all actions of a contract. Raw digests are rejected.
+func AllowActions(actions ...string) SigningPolicy {
+	allowed := map[string]bool{}
+	for _, action := range actions {
+		allowed[action] = true
+	}
+
+	return SigningPolicyFunc(func(ctx context.Context, req *SigningRequest) error {
+		if req.Transaction == nil {
+			return ErrDigestSigningNotAllowed
+		}
+
+		actions := make([]*Action, 0, len(req.Transaction.ContextFreeActions)+len(req.Transaction.Actions))
+		actions = append(actions, req.Transaction.ContextFreeActions...)
+		for _, action := range append(actions, req.Transaction.Actions...) {
+			if allowed[string(action.Account)+"::"+string(action.Name)] || allowed[string(action.Account)+"::*"] {
+				continue
+			}
+			return fmt.Errorf("action %s::%s is not allowed", action.Account, action.Name)
+		}
+		return nil
+	})
+}
+
+// SpendLimitPolicy limits the total quantity of tokens transferred, per
+// key, by the `transfer` actions of a token contract. Transactions
+// exceeding the remaining limit of any of their signing keys are
+// rejected, and raw digests too, as they could hide transfers. The
+// spent amounts are kept in memory.
+//
+// `Check` reserves the quantities of a request until the server either
+// commits them as spent, once the request is signed, or rolls them back.
+type SpendLimitPolicy struct {
+	Contract AccountName
+
+	lock     sync.Mutex
+	limits   map[string]Asset
+	spent    map[string]Asset
+	reserved map[string]Asset
+}
+
+func NewSpendLimitPolicy(contract AccountName) *SpendLimitPolicy {
+	return &SpendLimitPolicy{
+		Contract: contract,
+		limits:   map[string]Asset{},
+		spent:    map[string]Asset{},
+		reserved: map[string]Asset{},
+	}
+}
+
+// SetLimit sets the total quantity of `limit.Symbol` tokens that `key`
+// can transfer. Transfers of symbols without a limit are rejected.
+func (p *SpendLimitPolicy) SetLimit(key ecc.PublicKey, limit Asset) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.limits[spendLimitKey(key, limit.Symbol)] = limit
+}
+
+// Spent returns the quantity of `symbol` tokens transferred so far with
+// `key`.
+func (p *SpendLimitPolicy) Spent(key ecc.PublicKey, symbol Symbol) Asset {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return amountOrZero(p.spent, spendLimitKey(key, symbol), symbol)
+}
+
+func (p *SpendLimitPolicy) Check(ctx context.Context, req *SigningRequest) error {
+	if req.Transaction == nil {
+		return ErrDigestSigningNotAllowed
+	}
+
+	totals, err := p.transferTotals(req.Transaction)
+	if err != nil {
+		return err
+	}
+
+	if len(totals) == 0 {
+		return nil
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, key := range req.PublicKeys {
+		for _, total := range totals {
+			k := spendLimitKey(key, total.Symbol)
+			limit, ok := p.limits[k]
+			if !ok || limit.Precision != total.Precision {
+				return fmt.Errorf("no spend limit for %s with key %s", total.Symbol, key)
+			}
+
+			spent := amountOrZero(p.spent, k, total.Symbol)
+			used, ok := addAmounts(spent.Amount, amountOrZero(p.reserved, k, total.Symbol).Amount)
+			if ok {
+				used, ok = addAmounts(used, total.Amount)
+			}
+			if !ok || used > limit.Amount {
+				return fmt.Errorf("transferring %s exceeds the spend limit of key %s, %s already spent out of %s", total, key, spent, limit)
+			}
+		}
+	}
+
+	p.addLocked(p.reserved, req.PublicKeys, totals, 1)
+	return nil
+}
+
+// Commit counts the quantities reserved by `Check` as spent.
+func (p *SpendLimitPolicy) Commit(ctx context.Context, req *SigningRequest) {
+	totals := p.checkedTransferTotals(req)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.addLocked(p.reserved, req.PublicKeys, totals, -1)
+	p.addLocked(p.spent, req.PublicKeys, totals, 1)
+}
+
+// Rollback releases the quantities reserved by `Check`.
+func (p *SpendLimitPolicy) Rollback(ctx context.Context, req *SigningRequest) {
+	totals := p.checkedTransferTotals(req)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.addLocked(p.reserved, req.PublicKeys, totals, -1)
+}
+
+// transferTotals sums the quantities of the transfers of `tx` per
+// symbol. Non-positive quantities, overflowing sums and symbols
+// transferred with different precisions are rejected.
+func (p *SpendLimitPolicy) transferTotals(tx *SignedTransaction) (map[Symbol]Asset, error) {
+	totals := map[Symbol]Asset{}
+	precisions := map[string]uint8{}
+	for _, action := range tx.Actions {
+		if action.Account != p.Contract || action.Name != ActN("transfer") {
+			continue
+		}
+
+		var transfer struct {
+			From     AccountName
+			To       AccountName
+			Quantity Asset
+			Memo     string
+		}
+		if err := UnmarshalBinary(action.HexData, &transfer); err != nil {
+			return nil, fmt.Errorf("decode %s::transfer: %w", action.Account, err)
+		}
+
+		quantity := transfer.Quantity
+		if quantity.Amount <= 0 {
+			return nil, fmt.Errorf("transfer quantity %s should be positive", quantity)
+		}
+
+		if precision, ok := precisions[quantity.Symbol.Symbol]; ok && precision != quantity.Precision {
+			return nil, fmt.Errorf("transfers of %s have different precisions", quantity.Symbol.Symbol)
+		}
+		precisions[quantity.Symbol.Symbol] = quantity.Precision
+
+		symbol := Symbol{Precision: quantity.Precision, Symbol: quantity.Symbol.Symbol}
+		total, ok := totals[symbol]
+		if !ok {
+			total = Asset{Symbol: symbol}
+		}
+		if total.Amount, ok = addAmounts(total.Amount, quantity.Amount); !ok {
+			return nil, fmt.Errorf("total quantity of %s overflows", symbol.Symbol)
+		}
+		totals[symbol] = total
+	}
+
+	return totals, nil
+}
+
+// checkedTransferTotals returns the totals of a request accepted by
+// `Check`, which cannot fail.
+func (p *SpendLimitPolicy) checkedTransferTotals(req *SigningRequest) map[Symbol]Asset {
+	if req.Transaction == nil {
+		return nil
+	}
+
+	totals, _ := p.transferTotals(req.Transaction)
+	return totals
+}
+
+func (p *SpendLimitPolicy) addLocked(amounts map[string]Asset, keys []ecc.PublicKey, totals map[Symbol]Asset, sign Int64) {
+	for _, key := range keys {
+		for _, total := range totals {
+			k := spendLimitKey(key, total.Symbol)
+			amount := amountOrZero(amounts, k, total.Symbol)
+			amount.Amount += sign * total.Amount
+			amounts[k] = amount
+		}
+	}
+}
+
+func amountOrZero(amounts map[string]Asset, key string, symbol Symbol) Asset {
+	if amount, ok := amounts[key]; ok {
+		return amount
+	}
+	return Asset{Symbol: symbol}
+}
+
+// addAmounts adds two non-negative amounts, reporting an overflow.
+func addAmounts(a, b Int64) (Int64, bool) {
+	if a > math.MaxInt64-b {
+		return 0, false
+	}
+	return a + b, true
+}
+
+func spendLimitKey(key ecc.PublicKey, symbol Symbol) string {
+	return strings.Join([]string{key.String(), symbol.Symbol}, ":")
+}
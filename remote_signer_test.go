@@ -0,0 +1,225 @@
+package eos
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eoscanada/eos-go/ecc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRemoteSignerChainID = Checksum256(make([]byte, 32))
+
+func newTestRemoteSigner(t *testing.T, policies ...SigningPolicy) (*RemoteSigner, *KeyBag) {
+	keyBag := NewKeyBag()
+	require.NoError(t, keyBag.Add("5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3"))
+
+	server := httptest.NewServer(NewRemoteSignerServer(keyBag, policies...))
+	t.Cleanup(server.Close)
+
+	signer := NewRemoteSigner(server.URL)
+	signer.HttpClient = server.Client()
+	return signer, keyBag
+}
+
+type remoteSignerTransfer struct {
+	From     AccountName
+	To       AccountName
+	Quantity Asset
+	Memo     string
+}
+
+func testRemoteSignerTransaction(t *testing.T, quantities ...string) *SignedTransaction {
+	var actions []*Action
+	for _, quantity := range quantities {
+		asset, err := NewAssetFromString(quantity)
+		require.NoError(t, err)
+
+		actions = append(actions, &Action{
+			Account:       "eosio.token",
+			Name:          "transfer",
+			Authorization: []PermissionLevel{{Actor: "alice", Permission: "active"}},
+			ActionData:    NewActionData(&remoteSignerTransfer{From: "alice", To: "bob", Quantity: asset, Memo: "memo"}),
+		})
+	}
+
+	return NewSignedTransaction(NewTransaction(actions, nil))
+}
+
+func TestRemoteSigner_Sign(t *testing.T) {
+	signer, keyBag := newTestRemoteSigner(t)
+	ctx := context.Background()
+
+	keys, err := signer.AvailableKeys(ctx)
+	require.NoError(t, err)
+	expectedKeys, _ := keyBag.AvailableKeys(ctx)
+	assert.Equal(t, expectedKeys, keys)
+
+	tx := testRemoteSignerTransaction(t, "1.0000 EOS")
+	expected, err := keyBag.Sign(ctx, testRemoteSignerTransaction(t, "1.0000 EOS"), testRemoteSignerChainID, keys[0])
+	require.NoError(t, err)
+
+	signed, err := signer.Sign(ctx, tx, testRemoteSignerChainID, keys[0])
+	require.NoError(t, err)
+	assert.Equal(t, expected.Signatures, signed.Signatures)
+
+	digest := Checksum256(SigDigest(testRemoteSignerChainID, []byte("payload"), nil))
+	sig, err := signer.SignDigest(ctx, testRemoteSignerChainID, digest, keys[0])
+	require.NoError(t, err)
+	recovered, err := sig.PublicKey(digest)
+	require.NoError(t, err)
+	assert.Equal(t, keys[0].String(), recovered.String())
+
+	assert.Equal(t, ErrRemoteSignerImportNotSupported, signer.ImportPrivateKey(ctx, "5KQwrPbwdL6PhXujxW37FSSQZ1JiwsST4cqQzDeyXtP79zkvFD3"))
+}
+
+func TestRemoteSigner_Errors(t *testing.T) {
+	signer, _ := newTestRemoteSigner(t, AllowActions("eosio.token::*"))
+	ctx := context.Background()
+
+	unknownKey, err := ecc.NewRandomPrivateKey()
+	require.NoError(t, err)
+
+	_, err = signer.Sign(ctx, testRemoteSignerTransaction(t, "1.0000 EOS"), testRemoteSignerChainID, unknownKey.PublicKey())
+	var signerErr *RemoteSignerError
+	require.True(t, errors.As(err, &signerErr))
+	assert.Equal(t, RemoteSignerErrorKeyNotFound, signerErr.Code)
+	assert.Equal(t, http.StatusNotFound, signerErr.StatusCode)
+
+	keys, err := signer.AvailableKeys(ctx)
+	require.NoError(t, err)
+
+	_, err = signer.SignDigest(ctx, testRemoteSignerChainID, make([]byte, 32), keys[0])
+	require.True(t, errors.As(err, &signerErr))
+	assert.Equal(t, RemoteSignerErrorPolicyRejected, signerErr.Code)
+	assert.Contains(t, signerErr.Message, ErrDigestSigningNotAllowed.Error())
+
+	_, err = signer.SignDigest(ctx, testRemoteSignerChainID, []byte{0x01}, keys[0])
+	require.True(t, errors.As(err, &signerErr))
+	assert.Equal(t, RemoteSignerErrorBadRequest, signerErr.Code)
+}
+
+func TestAllowActions(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowed     []string
+		expectedErr bool
+	}{
+		{"exact action", []string{"eosio.token::transfer"}, false},
+		{"wildcard", []string{"eosio::*", "eosio.token::*"}, false},
+		{"other action", []string{"eosio.token::open"}, true},
+		{"nothing allowed", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := AllowActions(test.allowed...).Check(context.Background(), &SigningRequest{
+				Transaction: testRemoteSignerTransaction(t, "1.0000 EOS"),
+			})
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSpendLimitPolicy(t *testing.T) {
+	policy := NewSpendLimitPolicy("eosio.token")
+	signer, _ := newTestRemoteSigner(t, policy)
+	ctx := context.Background()
+
+	keys, err := signer.AvailableKeys(ctx)
+	require.NoError(t, err)
+	policy.SetLimit(keys[0], Asset{Amount: 50000, Symbol: Symbol{Precision: 4, Symbol: "EOS"}})
+
+	_, err = signer.Sign(ctx, testRemoteSignerTransaction(t, "2.0000 EOS", "1.0000 EOS"), testRemoteSignerChainID, keys[0])
+	require.NoError(t, err)
+	assert.Equal(t, Int64(30000), policy.Spent(keys[0], EOSSymbol).Amount)
+
+	_, err = signer.Sign(ctx, testRemoteSignerTransaction(t, "2.5000 EOS"), testRemoteSignerChainID, keys[0])
+	var signerErr *RemoteSignerError
+	require.True(t, errors.As(err, &signerErr))
+	assert.Equal(t, RemoteSignerErrorPolicyRejected, signerErr.Code)
+	assert.Equal(t, Int64(30000), policy.Spent(keys[0], EOSSymbol).Amount, "rejected transfers are not spent")
+
+	_, err = signer.Sign(ctx, testRemoteSignerTransaction(t, "1.0000 SYS"), testRemoteSignerChainID, keys[0])
+	require.True(t, errors.As(err, &signerErr), "symbols without a limit are rejected")
+
+	_, err = signer.Sign(ctx, testRemoteSignerTransaction(t, "2.0000 EOS"), testRemoteSignerChainID, keys[0])
+	require.NoError(t, err)
+	assert.Equal(t, Int64(50000), policy.Spent(keys[0], EOSSymbol).Amount)
+}
+
+func TestSpendLimitPolicy_InvalidTransfers(t *testing.T) {
+	policy := NewSpendLimitPolicy("eosio.token")
+	signer, _ := newTestRemoteSigner(t, policy)
+	ctx := context.Background()
+
+	keys, err := signer.AvailableKeys(ctx)
+	require.NoError(t, err)
+	policy.SetLimit(keys[0], Asset{Amount: math.MaxInt64, Symbol: EOSSymbol})
+
+	tests := []struct {
+		name       string
+		quantities []string
+	}{
+		{"negative quantity", []string{"2.0000 EOS", "-1.0000 EOS"}},
+		{"zero quantity", []string{"0.0000 EOS"}},
+		{"precision mismatch", []string{"1.0000 EOS", "1.000 EOS"}},
+		{"overflow", []string{"922337203685477.5807 EOS", "0.0001 EOS"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := signer.Sign(ctx, testRemoteSignerTransaction(t, test.quantities...), testRemoteSignerChainID, keys[0])
+			var signerErr *RemoteSignerError
+			require.True(t, errors.As(err, &signerErr))
+			assert.Equal(t, RemoteSignerErrorPolicyRejected, signerErr.Code)
+			assert.Equal(t, Int64(0), policy.Spent(keys[0], EOSSymbol).Amount)
+		})
+	}
+
+	policy.SetLimit(keys[0], Asset{Amount: 10000, Symbol: EOSSymbol})
+	_, err = signer.Sign(ctx, testRemoteSignerTransaction(t, "1.0000 EOS"), testRemoteSignerChainID, keys[0])
+	require.NoError(t, err)
+
+	_, err = signer.Sign(ctx, testRemoteSignerTransaction(t, "922337203685477.5807 EOS"), testRemoteSignerChainID, keys[0])
+	assert.Error(t, err, "spent amount and quantity overflow")
+	assert.Equal(t, Int64(10000), policy.Spent(keys[0], EOSSymbol).Amount)
+}
+
+func TestSpendLimitPolicy_LaterPolicyRejects(t *testing.T) {
+	policy := NewSpendLimitPolicy("eosio.token")
+	reject := true
+	signer, _ := newTestRemoteSigner(t, policy, SigningPolicyFunc(func(ctx context.Context, req *SigningRequest) error {
+		if reject {
+			return errors.New("rejected")
+		}
+		return nil
+	}))
+	ctx := context.Background()
+
+	keys, err := signer.AvailableKeys(ctx)
+	require.NoError(t, err)
+	policy.SetLimit(keys[0], Asset{Amount: 50000, Symbol: EOSSymbol})
+
+	for i := 0; i < 3; i++ {
+		_, err = signer.Sign(ctx, testRemoteSignerTransaction(t, "5.0000 EOS"), testRemoteSignerChainID, keys[0])
+		var signerErr *RemoteSignerError
+		require.True(t, errors.As(err, &signerErr))
+		assert.Equal(t, RemoteSignerErrorPolicyRejected, signerErr.Code)
+		assert.Equal(t, Int64(0), policy.Spent(keys[0], EOSSymbol).Amount)
+	}
+
+	reject = false
+	_, err = signer.Sign(ctx, testRemoteSignerTransaction(t, "5.0000 EOS"), testRemoteSignerChainID, keys[0])
+	require.NoError(t, err, "rejected requests release their reservation")
+	assert.Equal(t, Int64(50000), policy.Spent(keys[0], EOSSymbol).Amount)
+}
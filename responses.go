@@ -1,6 +1,8 @@
 package eos
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -261,10 +263,11 @@ type GetTableByScopeRequest struct {
 	LowerBound string `json:"lower_bound,omitempty"`
 	UpperBound string `json:"upper_bound,omitempty"`
 	Limit      uint32 `json:"limit,omitempty"`
+	Reverse    bool   `json:"reverse,omitempty"`
 }
 
 type GetTableByScopeResp struct {
-	More string          `json:"more"`
+	More string          `json:"more"` // Lower bound of the next scopes, empty when there are no more
 	Rows json.RawMessage `json:"rows"`
 }
 
@@ -283,8 +286,9 @@ type GetTableRowsRequest struct {
 }
 
 type GetTableRowsResp struct {
-	More bool            `json:"more"`
-	Rows json.RawMessage `json:"rows"` // defer loading, as it depends on `JSON` being true/false.
+	More    bool            `json:"more"`
+	NextKey string          `json:"next_key,omitempty"` // Lower bound of the next rows (upper bound when reversed), encoded like the request's `key_type`
+	Rows    json.RawMessage `json:"rows"`               // defer loading, as it depends on `JSON` being true/false.
 }
 
 func (resp *GetTableRowsResp) JSONToStructs(v interface{}) error {
@@ -292,9 +296,42 @@ func (resp *GetTableRowsResp) JSONToStructs(v interface{}) error {
 }
 
 func (resp *GetTableRowsResp) BinaryToStructs(v interface{}) error {
+	return binaryRowsToStructs(resp.Rows, v)
+}
+
+type GetKVTableRowsRequest struct {
+	Code       AccountName `json:"code"`
+	Table      TableName   `json:"table"`
+	IndexName  Name        `json:"index_name"`
+	EncodeType string      `json:"encode_type"`           // Encoding of the index value and bounds, `bytes` (hex of the key, see `EncodeBounds`), `string`, `name`, `dec` or `hex`
+	IndexValue string      `json:"index_value,omitempty"` // Exact index value to look up, bounds are ignored when set
+	LowerBound string      `json:"lower_bound,omitempty"`
+	UpperBound string      `json:"upper_bound,omitempty"`
+	Limit      uint32      `json:"limit,omitempty"` // defaults to 10
+	Reverse    bool        `json:"reverse,omitempty"`
+	JSON       bool        `json:"json"`
+	ShowPayer  bool        `json:"show_payer,omitempty"`
+}
+
+type GetKVTableRowsResp struct {
+	More         bool            `json:"more"`
+	NextKey      string          `json:"next_key"`       // Lower bound of the next rows, in the request's encode type
+	NextKeyBytes string          `json:"next_key_bytes"` // Lower bound of the next rows, with the `bytes` encode type
+	Rows         json.RawMessage `json:"rows"`
+}
+
+func (resp *GetKVTableRowsResp) JSONToStructs(v interface{}) error {
+	return json.Unmarshal(resp.Rows, v)
+}
+
+func (resp *GetKVTableRowsResp) BinaryToStructs(v interface{}) error {
+	return binaryRowsToStructs(resp.Rows, v)
+}
+
+func binaryRowsToStructs(data json.RawMessage, v interface{}) error {
 	var rows []string
 
-	err := json.Unmarshal(resp.Rows, &rows)
+	err := json.Unmarshal(data, &rows)
 	if err != nil {
 		return err
 	}
@@ -351,6 +388,32 @@ type GetRawABIResp struct {
 	ABI         Blob        `json:"abi"`
 }
 
+// DecodeABI unpacks the returned ABI, verifying that its hash matches
+// `ABIHash`. The ABI is not returned when the account has none, or
+// when the request's `ABIHash` is the one of the current ABI.
+func (resp *GetRawABIResp) DecodeABI() (*ABI, error) {
+	data, err := resp.ABI.Data()
+	if err != nil {
+		return nil, fmt.Errorf("decode abi blob: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no abi returned for account %s", resp.AccountName)
+	}
+
+	hash := sha256.Sum256(data)
+	if !bytes.Equal(hash[:], resp.ABIHash) {
+		return nil, fmt.Errorf("abi hash mismatch for account %s, expected %s, got %s", resp.AccountName, resp.ABIHash, Checksum256(hash[:]))
+	}
+
+	abi := &ABI{}
+	if err := UnmarshalBinary(data, abi); err != nil {
+		return nil, fmt.Errorf("unpack abi: %w", err)
+	}
+
+	return abi, nil
+}
+
 type GetRequiredKeysResp struct {
 	RequiredKeys []ecc.PublicKey `json:"required_keys"`
 }
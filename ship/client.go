@@ -0,0 +1,402 @@
+package ship
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// BlockResult is a decoded `get_blocks_result_v0` as delivered by
+// `Client.StreamBlocks`.
+type BlockResult struct {
+	Head             *BlockPosition
+	LastIrreversible *BlockPosition
+	ThisBlock        *BlockPosition
+	PrevBlock        *BlockPosition
+
+	// Block, Traces and Deltas are filled only when requested through
+	// `FetchBlock`, `FetchTraces` and `FetchDeltas` respectively.
+	Block  *SignedBlock
+	Traces []*TransactionTraceV0
+	Deltas []*TableDeltaV0
+}
+
+// BlockHandler receives the blocks streamed by `Client.StreamBlocks`.
+// Returning an error stops the stream, the error is then returned by
+// `StreamBlocks` as is.
+type BlockHandler func(result *BlockResult) error
+
+// Client is a state history plugin (SHiP) websocket client. It reads
+// the ABI sent by the server on connection, and streams blocks while
+// acknowledging them so that at most `MaxMessagesInFlight` are pending.
+//
+// On connection failure, `StreamBlocks` reconnects and resumes right
+// after the last delivered block, passing the reversible blocks it
+// knows about in `HavePositions` so the server can detect forks that
+// happened while disconnected.
+type Client struct {
+	url                 string
+	dialer              *websocket.Dialer
+	maxMessagesInFlight uint32
+	reconnectDelay      time.Duration
+	maxReconnects       int
+
+	// ABI is the state history ABI sent by the server, available once
+	// connected.
+	ABI *eos.ABI
+
+	conn      *websocket.Conn
+	writeLock sync.Mutex
+
+	// positions are the delivered blocks that are not irreversible yet,
+	// last one being the last delivered block.
+	positions []*BlockPosition
+}
+
+type ClientOption interface {
+	apply(c *Client)
+}
+
+type clientOptionFunc func(c *Client)
+
+func (f clientOptionFunc) apply(c *Client) {
+	f(c)
+}
+
+// WithDialer overrides the websocket dialer, `websocket.DefaultDialer`
+// by default.
+func WithDialer(dialer *websocket.Dialer) ClientOption {
+	return clientOptionFunc(func(c *Client) {
+		c.dialer = dialer
+	})
+}
+
+// WithMaxMessagesInFlight sets the `MaxMessagesInFlight` of block
+// requests that don't specify one, defaults to 10.
+func WithMaxMessagesInFlight(count uint32) ClientOption {
+	return clientOptionFunc(func(c *Client) {
+		c.maxMessagesInFlight = count
+	})
+}
+
+// WithReconnect configures how many times in a row `StreamBlocks`
+// reconnects after a failure (negative for unlimited, 0 to disable),
+// and the delay between reconnections. Defaults to unlimited
+// reconnections every second.
+func WithReconnect(maxReconnects int, delay time.Duration) ClientOption {
+	return clientOptionFunc(func(c *Client) {
+		c.maxReconnects = maxReconnects
+		c.reconnectDelay = delay
+	})
+}
+
+func NewClient(url string, opts ...ClientOption) *Client {
+	c := &Client{
+		url:                 url,
+		dialer:              websocket.DefaultDialer,
+		maxMessagesInFlight: 10,
+		reconnectDelay:      time.Second,
+		maxReconnects:       -1,
+	}
+
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+
+	return c
+}
+
+// Connect dials the server and reads its ABI. It is called
+// automatically by `Status` and `StreamBlocks` when not connected.
+func (c *Client) Connect(ctx context.Context) error {
+	conn, _, err := c.dialer.DialContext(ctx, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", c.url, err)
+	}
+
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("read abi: %w", err)
+	}
+
+	abi, err := eos.NewABI(bytes.NewReader(message))
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("decode abi: %w", err)
+	}
+
+	c.conn = conn
+	c.ABI = abi
+	return nil
+}
+
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Status sends a `get_status_request_v0` and waits for its result. It
+// must not be called while `StreamBlocks` is running.
+func (c *Client) Status(ctx context.Context) (result *GetStatusResultV0, err error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	defer c.closeOnError(&err)
+	defer c.watchContext(ctx)()
+
+	if err := c.write(NewGetStatusRequest()); err != nil {
+		return nil, err
+	}
+
+	_, message, err := c.conn.ReadMessage()
+	if err != nil {
+		return nil, c.contextError(ctx, fmt.Errorf("read status: %w", err))
+	}
+
+	return ParseGetStatusResultV0(message)
+}
+
+// StreamBlocks requests blocks with `req` and calls `handler` for each
+// one of them, until `EndBlockNum` is reached, the handler returns an
+// error, the context is done or reconnections are exhausted.
+//
+// A `MaxMessagesInFlight` of 0 in `req` is replaced by the client's
+// default. After a reconnection, `StartBlockNum` and `HavePositions`
+// of `req` are replaced to resume after the last delivered block.
+func (c *Client) StreamBlocks(ctx context.Context, req *GetBlocksRequestV0, handler BlockHandler) error {
+	request := *req
+	if request.MaxMessagesInFlight == 0 {
+		request.MaxMessagesInFlight = c.maxMessagesInFlight
+	}
+
+	failures := 0
+	for {
+		delivered, err := c.streamOnce(ctx, &request, handler)
+		if err == nil {
+			return nil
+		}
+
+		var handlerErr *handlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if delivered {
+			failures = 0
+		}
+
+		if c.maxReconnects >= 0 && failures >= c.maxReconnects {
+			return err
+		}
+		failures++
+
+		zlog.Info("state history connection failed, reconnecting", zap.String("url", c.url), zap.Int("attempt", failures), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.reconnectDelay):
+		}
+
+		c.resumeRequest(&request)
+	}
+}
+
+// Blocks is the channel version of `StreamBlocks`. The blocks channel
+// is closed when the stream ends, after which the error channel
+// receives the outcome of the stream (nil when `EndBlockNum` was
+// reached).
+func (c *Client) Blocks(ctx context.Context, req *GetBlocksRequestV0) (<-chan *BlockResult, <-chan error) {
+	blocks := make(chan *BlockResult)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		err := c.StreamBlocks(ctx, req, func(result *BlockResult) error {
+			select {
+			case blocks <- result:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+
+		close(blocks)
+		errs <- err
+	}()
+
+	return blocks, errs
+}
+
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string {
+	return e.err.Error()
+}
+
+// streamOnce runs a single connection of `StreamBlocks`, returning
+// whether at least a block was delivered. The connection is closed
+// when it fails, handler errors included, as it can then be left in
+// the middle of the stream.
+func (c *Client) streamOnce(ctx context.Context, req *GetBlocksRequestV0, handler BlockHandler) (delivered bool, err error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return false, err
+	}
+	defer c.closeOnError(&err)
+	defer c.watchContext(ctx)()
+
+	if err := c.write(NewRequest(req)); err != nil {
+		return false, err
+	}
+
+	ackEvery := req.MaxMessagesInFlight / 2
+	if ackEvery == 0 {
+		ackEvery = 1
+	}
+
+	unacked := uint32(0)
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			return delivered, c.contextError(ctx, fmt.Errorf("read blocks: %w", err))
+		}
+
+		result, err := ParseGetBlockResultV0(message)
+		if err != nil {
+			return delivered, fmt.Errorf("decode blocks result: %w", err)
+		}
+
+		if result.ThisBlock != nil {
+			if err := handler(newBlockResult(result)); err != nil {
+				return delivered, &handlerError{err}
+			}
+
+			delivered = true
+			c.trackPosition(result.ThisBlock, result.LastIrreversible)
+		}
+
+		unacked++
+		if unacked >= ackEvery {
+			if err := c.write(NewGetBlocksAck(unacked)); err != nil {
+				return delivered, err
+			}
+			unacked = 0
+		}
+
+		if result.ThisBlock != nil && req.EndBlockNum != 0 && result.ThisBlock.BlockNum+1 >= req.EndBlockNum {
+			return delivered, nil
+		}
+	}
+}
+
+func newBlockResult(result *GetBlocksResultV0) *BlockResult {
+	return &BlockResult{
+		Head:             result.Head,
+		LastIrreversible: result.LastIrreversible,
+		ThisBlock:        result.ThisBlock,
+		PrevBlock:        result.PrevBlock,
+		Block:            result.Block.AsSignedBlock(),
+		Traces:           result.Traces.AsTransactionTracesV0(),
+		Deltas:           result.Deltas.AsTableDeltasV0(),
+	}
+}
+
+func (c *Client) trackPosition(block *BlockPosition, lastIrreversible *BlockPosition) {
+	// A block at or below one we already delivered means a fork, the
+	// forked out positions are discarded.
+	kept := c.positions[:0]
+	for _, position := range c.positions {
+		if position.BlockNum >= block.BlockNum {
+			break
+		}
+
+		if lastIrreversible != nil && position.BlockNum < lastIrreversible.BlockNum {
+			continue
+		}
+
+		kept = append(kept, position)
+	}
+
+	c.positions = append(kept, block)
+}
+
+func (c *Client) resumeRequest(req *GetBlocksRequestV0) {
+	if len(c.positions) == 0 {
+		return
+	}
+
+	last := c.positions[len(c.positions)-1]
+	req.StartBlockNum = last.BlockNum + 1
+	req.HavePositions = append([]*BlockPosition(nil), c.positions...)
+}
+
+func (c *Client) ensureConnected(ctx context.Context) error {
+	if c.conn != nil {
+		return nil
+	}
+
+	return c.Connect(ctx)
+}
+
+func (c *Client) write(message []byte) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	if err := c.conn.WriteMessage(websocket.BinaryMessage, message); err != nil {
+		return fmt.Errorf("write request: %w", err)
+	}
+
+	return nil
+}
+
+// closeOnError closes the connection if `*err` is set, to be deferred
+// by the methods using the connection.
+func (c *Client) closeOnError(err *error) {
+	if *err != nil {
+		c.Close()
+	}
+}
+
+// watchContext unblocks pending reads when the context is done by
+// closing the connection, returning a function to stop watching.
+func (c *Client) watchContext(ctx context.Context) func() {
+	conn := c.conn
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func (c *Client) contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
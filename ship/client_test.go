@@ -0,0 +1,223 @@
+package ship
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shipStub is an in-process state history server streaming a linear
+// chain of blocks, dropping the connection after `dropAfter` blocks on
+// the first connection.
+type shipStub struct {
+	t         *testing.T
+	abi       []byte
+	head      uint32
+	dropAfter int
+
+	lock        sync.Mutex
+	requests    []*GetBlocksRequestV0
+	acks        []uint32
+	connections int
+}
+
+func testShipBlockID(num uint32) eos.Checksum256 {
+	id := make(eos.Checksum256, 32)
+	binary.BigEndian.PutUint32(id, num)
+	return id
+}
+
+func testShipPosition(num uint32) *BlockPosition {
+	return &BlockPosition{BlockNum: num, BlockID: testShipBlockID(num)}
+}
+
+func (s *shipStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	require.NoError(s.t, err)
+	defer conn.Close()
+
+	s.lock.Lock()
+	s.connections++
+	connection := s.connections
+	s.lock.Unlock()
+
+	require.NoError(s.t, conn.WriteMessage(websocket.TextMessage, s.abi))
+
+	sent := 0
+	inFlight := uint32(0)
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		request := &Request{}
+		require.NoError(s.t, eos.UnmarshalBinary(message, request))
+
+		switch req := request.Impl.(type) {
+		case *GetStatusRequestV0:
+			s.write(conn, "get_status_result_v0", &GetStatusResultV0{
+				Head:             testShipPosition(s.head),
+				LastIrreversible: testShipPosition(s.head - 2),
+				TraceEndBlock:    s.head + 1,
+			})
+
+		case *GetBlocksAckRequestV0:
+			s.lock.Lock()
+			s.acks = append(s.acks, req.NumMessages)
+			s.lock.Unlock()
+			inFlight -= req.NumMessages
+
+		case *GetBlocksRequestV0:
+			s.lock.Lock()
+			s.requests = append(s.requests, req)
+			s.lock.Unlock()
+
+			for num := req.StartBlockNum; num < req.EndBlockNum && num <= s.head; num++ {
+				for inFlight >= req.MaxMessagesInFlight {
+					_, message, err := conn.ReadMessage()
+					if err != nil {
+						return
+					}
+
+					ack := &Request{}
+					require.NoError(s.t, eos.UnmarshalBinary(message, ack))
+					numMessages := ack.Impl.(*GetBlocksAckRequestV0).NumMessages
+
+					s.lock.Lock()
+					s.acks = append(s.acks, numMessages)
+					s.lock.Unlock()
+					inFlight -= numMessages
+				}
+
+				if connection == 1 && sent == s.dropAfter {
+					return
+				}
+
+				s.write(conn, "get_blocks_result_v0", &GetBlocksResultV0{
+					Head:             testShipPosition(s.head),
+					LastIrreversible: testShipPosition(s.head - 2),
+					ThisBlock:        testShipPosition(num),
+					PrevBlock:        testShipPosition(num - 1),
+					Deltas: &TableDeltaArray{Elem: []*TableDelta{{BaseVariant: eos.BaseVariant{
+						TypeID: TableDeltaVariant.TypeID("table_delta_v0"),
+						Impl:   &TableDeltaV0{Name: "account", Rows: []Row{{Present: true, Data: []byte{0x01}}}},
+					}}}},
+				})
+				sent++
+				inFlight++
+			}
+		}
+	}
+}
+
+func (s *shipStub) write(conn *websocket.Conn, typeName string, impl interface{}) {
+	data, err := eos.MarshalBinary(&Result{BaseVariant: eos.BaseVariant{TypeID: ResultVariant.TypeID(typeName), Impl: impl}})
+	require.NoError(s.t, err)
+	require.NoError(s.t, conn.WriteMessage(websocket.BinaryMessage, data))
+}
+
+func newShipStub(t *testing.T, head uint32, dropAfter int) (*shipStub, string) {
+	abi, err := os.ReadFile("wsabi.json")
+	require.NoError(t, err)
+
+	stub := &shipStub{t: t, abi: abi, head: head, dropAfter: dropAfter}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	return stub, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestClient_Status(t *testing.T) {
+	_, url := newShipStub(t, 20, -1)
+
+	client := NewClient(url)
+	defer client.Close()
+
+	status, err := client.Status(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32(20), status.Head.BlockNum)
+	assert.Equal(t, uint32(18), status.LastIrreversible.BlockNum)
+	assert.NotNil(t, client.ABI.StructForName("get_blocks_request_v0"))
+}
+
+func TestClient_StreamBlocksAcks(t *testing.T) {
+	stub, url := newShipStub(t, 20, -1)
+
+	client := NewClient(url, WithMaxMessagesInFlight(4))
+	defer client.Close()
+
+	var blockNums []uint32
+	err := client.StreamBlocks(context.Background(), &GetBlocksRequestV0{StartBlockNum: 5, EndBlockNum: 15, FetchDeltas: true}, func(result *BlockResult) error {
+		blockNums = append(blockNums, result.ThisBlock.BlockNum)
+		require.Len(t, result.Deltas, 1)
+		assert.Equal(t, "account", result.Deltas[0].Name)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint32{5, 6, 7, 8, 9, 10, 11, 12, 13, 14}, blockNums)
+	assert.Equal(t, uint32(4), stub.requests[0].MaxMessagesInFlight)
+	assert.Eventually(t, func() bool {
+		stub.lock.Lock()
+		defer stub.lock.Unlock()
+		return assert.ObjectsAreEqual([]uint32{2, 2, 2, 2, 2}, stub.acks)
+	}, time.Second, time.Millisecond)
+}
+
+func TestClient_StreamBlocksReconnects(t *testing.T) {
+	stub, url := newShipStub(t, 20, 3)
+
+	client := NewClient(url, WithReconnect(2, time.Millisecond))
+	defer client.Close()
+
+	blocks, errs := client.Blocks(context.Background(), &GetBlocksRequestV0{StartBlockNum: 10, EndBlockNum: 16, MaxMessagesInFlight: 100})
+
+	var blockNums []uint32
+	for block := range blocks {
+		blockNums = append(blockNums, block.ThisBlock.BlockNum)
+	}
+	require.NoError(t, <-errs)
+
+	assert.Equal(t, []uint32{10, 11, 12, 13, 14, 15}, blockNums)
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, uint32(13), stub.requests[1].StartBlockNum)
+
+	// Delivered blocks are all below the last irreversible block (18),
+	// only the last one is kept to resume from.
+	assert.Equal(t, []*BlockPosition{testShipPosition(12)}, stub.requests[1].HavePositions)
+}
+
+func TestClient_StreamBlocksHandlerError(t *testing.T) {
+	stub, url := newShipStub(t, 20, -1)
+
+	client := NewClient(url)
+	defer client.Close()
+
+	err := client.StreamBlocks(context.Background(), &GetBlocksRequestV0{StartBlockNum: 5, EndBlockNum: 15}, func(result *BlockResult) error {
+		return assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+
+	// The stream was left in the middle of the blocks, a new connection
+	// must be used.
+	status, err := client.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint32(20), status.Head.BlockNum)
+
+	stub.lock.Lock()
+	defer stub.lock.Unlock()
+	assert.Equal(t, 2, stub.connections)
+}
@@ -0,0 +1,331 @@
+package ship
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/eoscanada/eos-go"
+)
+
+//go:embed wsabi.json
+var stateHistoryABIJSON []byte
+
+// StateHistoryABI returns the state history ABI bundled with this
+// package (`wsabi.json`). Prefer the ABI sent by the server
+// (`Client.ABI`) when connected, it matches the server's version.
+func StateHistoryABI() (*eos.ABI, error) {
+	return eos.NewABI(bytes.NewReader(stateHistoryABIJSON))
+}
+
+// State History table delta rows
+
+type AccountV0 struct {
+	Name         eos.Name
+	CreationDate eos.BlockTimestamp
+	ABI          []byte
+}
+
+type CodeID struct {
+	VMType    uint8
+	VMVersion uint8
+	CodeHash  eos.Checksum256
+}
+
+type AccountMetadataV0 struct {
+	Name           eos.Name
+	Privileged     bool
+	LastCodeUpdate eos.TimePoint
+	Code           *CodeID `eos:"optional"`
+}
+
+type CodeV0 struct {
+	VMType    uint8
+	VMVersion uint8
+	CodeHash  eos.Checksum256
+	Code      []byte
+}
+
+type ContractTableV0 struct {
+	Code  eos.Name
+	Scope eos.Name
+	Table eos.Name
+	Payer eos.Name
+}
+
+type ContractRowV0 struct {
+	Code       eos.Name
+	Scope      eos.Name
+	Table      eos.Name
+	PrimaryKey uint64
+	Payer      eos.Name
+	Value      []byte
+}
+
+type ContractIndex64V0 struct {
+	Code         eos.Name
+	Scope        eos.Name
+	Table        eos.Name
+	PrimaryKey   uint64
+	Payer        eos.Name
+	SecondaryKey uint64
+}
+
+type ContractIndex128V0 struct {
+	Code         eos.Name
+	Scope        eos.Name
+	Table        eos.Name
+	PrimaryKey   uint64
+	Payer        eos.Name
+	SecondaryKey eos.Uint128
+}
+
+type ContractIndex256V0 struct {
+	Code         eos.Name
+	Scope        eos.Name
+	Table        eos.Name
+	PrimaryKey   uint64
+	Payer        eos.Name
+	SecondaryKey eos.Checksum256
+}
+
+type ContractIndexDoubleV0 struct {
+	Code         eos.Name
+	Scope        eos.Name
+	Table        eos.Name
+	PrimaryKey   uint64
+	Payer        eos.Name
+	SecondaryKey float64
+}
+
+type ContractIndexLongDoubleV0 struct {
+	Code         eos.Name
+	Scope        eos.Name
+	Table        eos.Name
+	PrimaryKey   uint64
+	Payer        eos.Name
+	SecondaryKey eos.Float128
+}
+
+type PermissionV0 struct {
+	Owner       eos.Name
+	Name        eos.Name
+	Parent      eos.Name
+	LastUpdated eos.TimePoint
+	Auth        eos.Authority
+}
+
+type PermissionLinkV0 struct {
+	Account            eos.Name
+	Code               eos.Name
+	MessageType        eos.Name
+	RequiredPermission eos.Name
+}
+
+type ResourceLimitsV0 struct {
+	Owner     eos.Name
+	NetWeight int64
+	CPUWeight int64
+	RAMBytes  int64
+}
+
+type UsageAccumulatorV0 struct {
+	LastOrdinal uint32
+	ValueEx     uint64
+	Consumed    uint64
+}
+
+type ResourceUsageV0 struct {
+	Owner    eos.Name
+	NetUsage *UsageAccumulator
+	CPUUsage *UsageAccumulator
+	RAMUsage uint64
+}
+
+// TableDeltaRow is a single row of a table delta, decoded by
+// `DeltaDecoder.DecodeDelta`.
+type TableDeltaRow struct {
+	// Present is false when the row was removed, `Value` is then the
+	// last value of the row.
+	Present bool
+
+	// Value is the row typed struct (like `*ContractRowV0`) for the
+	// tables listed in `DeltaTableVariants`, nil otherwise.
+	Value interface{}
+
+	// JSON is the row decoded through the state history ABI.
+	JSON json.RawMessage
+}
+
+// ContractRowChange is a `contract_row` delta row whose value was
+// decoded with the contract's ABI.
+type ContractRowChange struct {
+	Present    bool
+	Code       eos.Name
+	Scope      eos.Name
+	Table      eos.Name
+	PrimaryKey uint64
+	Payer      eos.Name
+
+	// Value is the row decoded with the contract's ABI, or nil when the
+	// ABI of the contract is not known to the decoder.
+	Value json.RawMessage
+
+	// RawValue is the binary row as stored by the contract.
+	RawValue []byte
+}
+
+// DeltaDecoder decodes the rows of state history table deltas, using
+// the state history ABI for the chain tables and the ABI of each
+// contract for the `contract_row` values. It is safe for concurrent
+// use.
+type DeltaDecoder struct {
+	abi *eos.ABI
+
+	contractABIs     map[eos.Name]*eos.ABI
+	contractABIsLock sync.RWMutex
+}
+
+// NewDeltaDecoder creates a decoder based on the given state history
+// ABI, which is usually `Client.ABI`. When nil, the bundled ABI is
+// used (see `StateHistoryABI`).
+func NewDeltaDecoder(stateHistoryABI *eos.ABI) (*DeltaDecoder, error) {
+	if stateHistoryABI == nil {
+		var err error
+		if stateHistoryABI, err = StateHistoryABI(); err != nil {
+			return nil, fmt.Errorf("load bundled state history abi: %w", err)
+		}
+	}
+
+	return &DeltaDecoder{
+		abi:          stateHistoryABI,
+		contractABIs: make(map[eos.Name]*eos.ABI),
+	}, nil
+}
+
+// SetContractABI registers the ABI used to decode the `contract_row`
+// values of `account`. A nil ABI removes it.
+func (d *DeltaDecoder) SetContractABI(account eos.Name, abi *eos.ABI) {
+	d.contractABIsLock.Lock()
+	defer d.contractABIsLock.Unlock()
+
+	if abi == nil {
+		delete(d.contractABIs, account)
+		return
+	}
+	d.contractABIs[account] = abi
+}
+
+// ContractABI returns the ABI registered for `account`, if any.
+func (d *DeltaDecoder) ContractABI(account eos.Name) *eos.ABI {
+	d.contractABIsLock.RLock()
+	defer d.contractABIsLock.RUnlock()
+
+	return d.contractABIs[account]
+}
+
+// DecodeRowJSON decodes a row of table `tableName` (like `account` or
+// `contract_row`) to JSON, through the state history ABI. This works
+// for all tables of the ABI, including the ones without typed struct.
+func (d *DeltaDecoder) DecodeRowJSON(tableName string, data []byte) ([]byte, error) {
+	return d.abi.DecodeTableRow(eos.TableName(tableName), data)
+}
+
+// DecodeRow decodes a row of table `tableName` into its typed struct
+// (like `*ContractRowV0` for `contract_row`).
+func (d *DeltaDecoder) DecodeRow(tableName string, data []byte) (interface{}, error) {
+	definition, found := DeltaTableVariants[tableName]
+	if !found {
+		return nil, fmt.Errorf("no typed struct for table %q, use DecodeRowJSON instead", tableName)
+	}
+
+	variant := &deltaRowVariant{definition: definition}
+	if err := eos.UnmarshalBinary(data, variant); err != nil {
+		return nil, fmt.Errorf("decode %s row: %w", tableName, err)
+	}
+
+	return variant.Impl, nil
+}
+
+// DecodeDelta decodes all the rows of a table delta, both as typed
+// struct (when available) and JSON.
+func (d *DeltaDecoder) DecodeDelta(delta *TableDeltaV0) ([]*TableDeltaRow, error) {
+	_, typed := DeltaTableVariants[delta.Name]
+
+	out := make([]*TableDeltaRow, len(delta.Rows))
+	for i, row := range delta.Rows {
+		decoded := &TableDeltaRow{Present: row.Present}
+
+		jsonRow, err := d.DecodeRowJSON(delta.Name, row.Data)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s row %d: %w", delta.Name, i, err)
+		}
+		decoded.JSON = jsonRow
+
+		if typed {
+			if decoded.Value, err = d.DecodeRow(delta.Name, row.Data); err != nil {
+				return nil, err
+			}
+		}
+
+		out[i] = decoded
+	}
+
+	return out, nil
+}
+
+// DecodeContractRow decodes the value of a `contract_row` with the ABI
+// registered for its contract. It returns nil without error when no
+// ABI is registered for the contract.
+func (d *DeltaDecoder) DecodeContractRow(row *ContractRowV0) ([]byte, error) {
+	abi := d.ContractABI(row.Code)
+	if abi == nil {
+		return nil, nil
+	}
+
+	value, err := abi.DecodeTableRow(eos.TableName(row.Table), row.Value)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s row of table %s (scope %s, primary key %d): %w", row.Code, row.Table, row.Scope, row.PrimaryKey, err)
+	}
+
+	return value, nil
+}
+
+// ContractRowChanges extracts the `contract_row` deltas and decodes
+// their values with the registered contract ABIs, giving a table-level
+// change stream. Other deltas are ignored.
+func (d *DeltaDecoder) ContractRowChanges(deltas []*TableDeltaV0) (out []*ContractRowChange, err error) {
+	for _, delta := range deltas {
+		if delta.Name != "contract_row" {
+			continue
+		}
+
+		for _, deltaRow := range delta.Rows {
+			decoded, err := d.DecodeRow(delta.Name, deltaRow.Data)
+			if err != nil {
+				return nil, err
+			}
+
+			row := decoded.(*ContractRowV0)
+			value, err := d.DecodeContractRow(row)
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, &ContractRowChange{
+				Present:    deltaRow.Present,
+				Code:       row.Code,
+				Scope:      row.Scope,
+				Table:      row.Table,
+				PrimaryKey: row.PrimaryKey,
+				Payer:      row.Payer,
+				Value:      value,
+				RawValue:   row.Value,
+			})
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package ship
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/ecc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deltaRowData packs a `_v0` table delta row, prefixed with its variant
+// index.
+func deltaRowData(t *testing.T, row interface{}) []byte {
+	data, err := eos.MarshalBinary(row)
+	require.NoError(t, err)
+
+	return append([]byte{0x00}, data...)
+}
+
+func newTestDeltaDecoder(t *testing.T) *DeltaDecoder {
+	decoder, err := NewDeltaDecoder(nil)
+	require.NoError(t, err)
+
+	return decoder
+}
+
+func TestDeltaDecoder_DecodeDelta(t *testing.T) {
+	decoder := newTestDeltaDecoder(t)
+
+	key, err := ecc.NewPublicKey("EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV")
+	require.NoError(t, err)
+
+	permission := &PermissionV0{
+		Owner:       eos.Name("eosio"),
+		Name:        eos.Name("active"),
+		Parent:      eos.Name("owner"),
+		LastUpdated: eos.TimePoint(1577836800000000),
+		Auth: eos.Authority{
+			Threshold: 1,
+			Keys:      []eos.KeyWeight{{PublicKey: key, Weight: 1}},
+			Accounts:  []eos.PermissionLevelWeight{},
+			Waits:     []eos.WaitWeight{},
+		},
+	}
+
+	rows, err := decoder.DecodeDelta(&TableDeltaV0{
+		Name: "permission",
+		Rows: []Row{{Present: true, Data: deltaRowData(t, permission)}},
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	assert.True(t, rows[0].Present)
+	assert.Equal(t, permission, rows[0].Value)
+	assert.JSONEq(t, `{
+		"owner": "eosio",
+		"name": "active",
+		"parent": "owner",
+		"last_updated": "2020-01-01T00:00:00",
+		"auth": {
+			"threshold": 1,
+			"keys": [{"key": "EOS6MRyAjQq8ud7hVNYcfnVPJqcVpscN5So8BhtHuGYqET5GDW5CV", "weight": 1}],
+			"accounts": [],
+			"waits": []
+		}
+	}`, string(rows[0].JSON))
+}
+
+func TestDeltaDecoder_DecodeRow(t *testing.T) {
+	decoder := newTestDeltaDecoder(t)
+
+	tests := []struct {
+		table string
+		row   interface{}
+	}{
+		{"account_metadata", &AccountMetadataV0{Name: "eosio.token", Privileged: false, LastCodeUpdate: 1, Code: &CodeID{CodeHash: make(eos.Checksum256, 32)}}},
+		{"contract_table", &ContractTableV0{Code: "eosio.token", Scope: "alice", Table: "accounts", Payer: "alice"}},
+		{"contract_index64", &ContractIndex64V0{Code: "eosio", Scope: "eosio", Table: "voters", PrimaryKey: 1, Payer: "alice", SecondaryKey: 42}},
+		{"contract_index128", &ContractIndex128V0{Code: "eosio", Scope: "eosio", Table: "voters", PrimaryKey: 1, Payer: "alice", SecondaryKey: eos.Uint128{Lo: 1, Hi: 2}}},
+		{"permission_link", &PermissionLinkV0{Account: "alice", Code: "eosio.token", MessageType: "transfer", RequiredPermission: "transfer"}},
+		{"resource_limits", &ResourceLimitsV0{Owner: "alice", NetWeight: 10, CPUWeight: 20, RAMBytes: 4096}},
+		{"resource_usage", &ResourceUsageV0{
+			Owner:    "alice",
+			NetUsage: &UsageAccumulator{BaseVariant: eos.BaseVariant{TypeID: 0, Impl: &UsageAccumulatorV0{LastOrdinal: 1, ValueEx: 2, Consumed: 3}}},
+			CPUUsage: &UsageAccumulator{BaseVariant: eos.BaseVariant{TypeID: 0, Impl: &UsageAccumulatorV0{LastOrdinal: 4, ValueEx: 5, Consumed: 6}}},
+			RAMUsage: 7,
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.table, func(t *testing.T) {
+			data := deltaRowData(t, test.row)
+
+			value, err := decoder.DecodeRow(test.table, data)
+			require.NoError(t, err)
+			assert.Equal(t, test.row, value)
+
+			_, err = decoder.DecodeRowJSON(test.table, data)
+			require.NoError(t, err)
+		})
+	}
+
+	_, err := decoder.DecodeRow("global_property", []byte{0x00})
+	assert.Error(t, err)
+}
+
+func TestDeltaDecoder_DecodeAccountRow(t *testing.T) {
+	decoder := newTestDeltaDecoder(t)
+
+	creationDate := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := deltaRowData(t, &AccountV0{Name: "eosio.token", CreationDate: eos.BlockTimestamp{Time: creationDate}, ABI: []byte{0x01, 0x02}})
+
+	value, err := decoder.DecodeRow("account", data)
+	require.NoError(t, err)
+
+	account := value.(*AccountV0)
+	assert.Equal(t, eos.Name("eosio.token"), account.Name)
+	assert.True(t, creationDate.Equal(account.CreationDate.Time))
+	assert.Equal(t, []byte{0x01, 0x02}, account.ABI)
+
+	jsonRow, err := decoder.DecodeRowJSON("account", data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "eosio.token", "creation_date": "2020-01-01T00:00:00", "abi": "0102"}`, string(jsonRow))
+}
+
+const testTokenABI = `{
+	"version": "eosio::abi/1.1",
+	"structs": [{"name": "account", "base": "", "fields": [{"name": "balance", "type": "asset"}]}],
+	"tables": [{"name": "accounts", "index_type": "i64", "key_names": [], "key_types": [], "type": "account"}]
+}`
+
+func TestDeltaDecoder_ContractRowChanges(t *testing.T) {
+	decoder := newTestDeltaDecoder(t)
+
+	abi, err := eos.NewABI(strings.NewReader(testTokenABI))
+	require.NoError(t, err)
+	decoder.SetContractABI("eosio.token", abi)
+
+	balance, err := eos.NewAssetFromString("1.0000 EOS")
+	require.NoError(t, err)
+
+	value, err := eos.MarshalBinary(balance)
+	require.NoError(t, err)
+
+	tokenRow := &ContractRowV0{Code: "eosio.token", Scope: "alice", Table: "accounts", PrimaryKey: 1397703940, Payer: "alice", Value: value}
+	otherRow := &ContractRowV0{Code: "unknown", Scope: "alice", Table: "things", PrimaryKey: 1, Payer: "alice", Value: []byte{0x01}}
+
+	changes, err := decoder.ContractRowChanges([]*TableDeltaV0{
+		{Name: "resource_limits", Rows: []Row{{Present: true, Data: deltaRowData(t, &ResourceLimitsV0{Owner: "alice"})}}},
+		{Name: "contract_row", Rows: []Row{
+			{Present: true, Data: deltaRowData(t, tokenRow)},
+			{Present: false, Data: deltaRowData(t, otherRow)},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	assert.True(t, changes[0].Present)
+	assert.Equal(t, eos.Name("accounts"), changes[0].Table)
+	assert.JSONEq(t, `{"balance": "1.0000 EOS"}`, string(changes[0].Value))
+
+	assert.False(t, changes[1].Present)
+	assert.Nil(t, changes[1].Value)
+	assert.Equal(t, []byte{0x01}, changes[1].RawValue)
+}
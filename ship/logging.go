@@ -0,0 +1,7 @@
+package ship
+
+import (
+	"github.com/streamingfast/logging"
+)
+
+var zlog, _ = logging.PackageLogger("eos-go", "github.com/eoscanada/eos-go/ship")
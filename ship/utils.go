@@ -23,6 +23,21 @@ func NewGetBlocksAck(num uint32) []byte {
 	return bytes
 }
 
+func NewGetStatusRequest() []byte {
+	myReq := &Request{
+		BaseVariant: eos.BaseVariant{
+			TypeID: RequestVariant.TypeID("get_status_request_v0"),
+			Impl:   &GetStatusRequestV0{},
+		},
+	}
+	bytes, err := eos.MarshalBinary(myReq)
+	if err != nil {
+		panic(err)
+	}
+
+	return bytes
+}
+
 func NewRequest(req *GetBlocksRequestV0) []byte {
 	myReq := &Request{
 		BaseVariant: eos.BaseVariant{
@@ -50,3 +65,16 @@ func ParseGetBlockResultV0(in []byte) (*GetBlocksResultV0, error) {
 	}
 	return v, nil
 }
+
+func ParseGetStatusResultV0(in []byte) (*GetStatusResultV0, error) {
+	variant := &Result{}
+	if err := eos.UnmarshalBinary(in, &variant); err != nil {
+		return nil, err
+	}
+
+	v, ok := variant.Impl.(*GetStatusResultV0)
+	if !ok {
+		return nil, fmt.Errorf("invalid response type: %d", variant.TypeID)
+	}
+	return v, nil
+}
@@ -184,3 +184,72 @@ type ActionReceipt struct {
 func (r *ActionReceipt) UnmarshalBinary(decoder *eos.Decoder) error {
 	return r.BaseVariant.UnmarshalBinaryVariant(decoder, ActionReceiptVariant)
 }
+
+// UsageAccumulator
+var UsageAccumulatorVariant = eos.NewVariantDefinition([]eos.VariantType{
+	{"usage_accumulator_v0", (*UsageAccumulatorV0)(nil)},
+})
+
+type UsageAccumulator struct {
+	eos.BaseVariant
+}
+
+func (r *UsageAccumulator) UnmarshalBinary(decoder *eos.Decoder) error {
+	return r.BaseVariant.UnmarshalBinaryVariant(decoder, UsageAccumulatorVariant)
+}
+
+// DeltaTableVariants are the variant definitions of the table delta
+// rows that have a typed struct, keyed by table name.
+var DeltaTableVariants = map[string]*eos.VariantDefinition{
+	"account": eos.NewVariantDefinition([]eos.VariantType{
+		{"account_v0", (*AccountV0)(nil)},
+	}),
+	"account_metadata": eos.NewVariantDefinition([]eos.VariantType{
+		{"account_metadata_v0", (*AccountMetadataV0)(nil)},
+	}),
+	"code": eos.NewVariantDefinition([]eos.VariantType{
+		{"code_v0", (*CodeV0)(nil)},
+	}),
+	"contract_table": eos.NewVariantDefinition([]eos.VariantType{
+		{"contract_table_v0", (*ContractTableV0)(nil)},
+	}),
+	"contract_row": eos.NewVariantDefinition([]eos.VariantType{
+		{"contract_row_v0", (*ContractRowV0)(nil)},
+	}),
+	"contract_index64": eos.NewVariantDefinition([]eos.VariantType{
+		{"contract_index64_v0", (*ContractIndex64V0)(nil)},
+	}),
+	"contract_index128": eos.NewVariantDefinition([]eos.VariantType{
+		{"contract_index128_v0", (*ContractIndex128V0)(nil)},
+	}),
+	"contract_index256": eos.NewVariantDefinition([]eos.VariantType{
+		{"contract_index256_v0", (*ContractIndex256V0)(nil)},
+	}),
+	"contract_index_double": eos.NewVariantDefinition([]eos.VariantType{
+		{"contract_index_double_v0", (*ContractIndexDoubleV0)(nil)},
+	}),
+	"contract_index_long_double": eos.NewVariantDefinition([]eos.VariantType{
+		{"contract_index_long_double_v0", (*ContractIndexLongDoubleV0)(nil)},
+	}),
+	"permission": eos.NewVariantDefinition([]eos.VariantType{
+		{"permission_v0", (*PermissionV0)(nil)},
+	}),
+	"permission_link": eos.NewVariantDefinition([]eos.VariantType{
+		{"permission_link_v0", (*PermissionLinkV0)(nil)},
+	}),
+	"resource_limits": eos.NewVariantDefinition([]eos.VariantType{
+		{"resource_limits_v0", (*ResourceLimitsV0)(nil)},
+	}),
+	"resource_usage": eos.NewVariantDefinition([]eos.VariantType{
+		{"resource_usage_v0", (*ResourceUsageV0)(nil)},
+	}),
+}
+
+type deltaRowVariant struct {
+	eos.BaseVariant
+	definition *eos.VariantDefinition
+}
+
+func (r *deltaRowVariant) UnmarshalBinary(decoder *eos.Decoder) error {
+	return r.BaseVariant.UnmarshalBinaryVariant(decoder, r.definition)
+}
@@ -0,0 +1,70 @@
+package eos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eoscanada/eos-go/ecc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyBag_SignR1(t *testing.T) {
+	keyBag := NewKeyBag()
+	require.NoError(t, keyBag.Add("PVT_R1_2o5WfMRU4dTp23pbcbP2yn5MumQzSMy3ayNQ31qi5nUfa2jdWC"))
+
+	ctx := context.Background()
+	keys, err := keyBag.AvailableKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "PUB_R1_6RJ9pXJNe1wk6p2yiJcuJ8QPo7WTudHya9z8vu1VPk44fhBz79", keys[0].String())
+
+	tx := testRemoteSignerTransaction(t, "1.0000 EOS")
+	signed, err := keyBag.Sign(ctx, tx, testRemoteSignerChainID, keys[0])
+	require.NoError(t, err)
+	require.Len(t, signed.Signatures, 1)
+	assert.Equal(t, ecc.CurveR1, signed.Signatures[0].Curve)
+
+	publicKeys, err := signed.SignedByKeys(testRemoteSignerChainID)
+	require.NoError(t, err)
+	assert.Equal(t, []ecc.PublicKey{keys[0]}, publicKeys)
+
+	packed, err := signed.Pack(CompressionNone)
+	require.NoError(t, err)
+	unpacked, err := packed.UnpackBare()
+	require.NoError(t, err)
+	assert.Equal(t, signed.Signatures[0].String(), unpacked.Signatures[0].String())
+}
+
+func TestKeyBag_SignWA(t *testing.T) {
+	authenticator, err := ecc.NewRandomWebAuthnAuthenticator("example.com")
+	require.NoError(t, err)
+
+	keyBag := NewKeyBag()
+	require.NoError(t, keyBag.Append(authenticator.PrivateKey()))
+
+	ctx := context.Background()
+	keys, err := keyBag.AvailableKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ecc.PublicKey{authenticator.PublicKey()}, keys)
+
+	tx := testRemoteSignerTransaction(t, "1.0000 EOS")
+	signed, err := keyBag.Sign(ctx, tx, testRemoteSignerChainID, keys[0])
+	require.NoError(t, err)
+	require.Len(t, signed.Signatures, 1)
+	assert.Equal(t, ecc.CurveWA, signed.Signatures[0].Curve)
+
+	publicKeys, err := signed.SignedByKeys(testRemoteSignerChainID)
+	require.NoError(t, err)
+	assert.Equal(t, keys[0].String(), publicKeys[0].String())
+
+	packed, err := signed.Pack(CompressionNone)
+	require.NoError(t, err)
+	unpacked, err := packed.UnpackBare()
+	require.NoError(t, err)
+	assert.Equal(t, signed.Signatures[0].String(), unpacked.Signatures[0].String())
+
+	publicKeys, err = unpacked.SignedByKeys(testRemoteSignerChainID)
+	require.NoError(t, err)
+	assert.Equal(t, keys[0].String(), publicKeys[0].String())
+}
@@ -0,0 +1,208 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/eoscanada/eos-go"
+	"go.uber.org/zap"
+)
+
+// TableRow is a row of a contract table, as found in the
+// `contract_tables` section of a snapshot, decoded with the ABI of its
+// contract when possible.
+type TableRow struct {
+	Code       eos.AccountName `json:"code"`
+	Scope      eos.Name        `json:"scope"`
+	Table      eos.TableName   `json:"table"`
+	PrimaryKey eos.Uint64      `json:"primary_key"`
+	Payer      eos.AccountName `json:"payer"`
+
+	// Data is the row decoded to JSON, nil when the contract has no ABI
+	// or the row could not be decoded with it.
+	Data json.RawMessage `json:"data,omitempty"`
+	Hex  eos.HexBytes    `json:"hex"`
+}
+
+// TableRowSink receives the rows imported by an `Importer`.
+type TableRowSink interface {
+	WriteTableRow(row *TableRow) error
+
+	// Flush is called once the import completes.
+	Flush() error
+}
+
+// ImportStats summarizes the rows seen by an `Importer`.
+type ImportStats struct {
+	Contracts   uint64 // accounts with an ABI
+	Tables      uint64
+	Rows        uint64
+	DecodedRows uint64
+}
+
+// Importer walks a snapshot, picking up the ABI of each contract from
+// the `account_object` section, then writes every row of the primary
+// index of the `contract_tables` section, decoded with those ABIs, to
+// a `TableRowSink`.
+type Importer struct {
+	Stats ImportStats
+
+	sink      TableRowSink
+	abis      map[eos.AccountName]*eos.ABI
+	contracts map[eos.AccountName]bool
+}
+
+type ImporterOption interface {
+	apply(i *Importer)
+}
+
+type importerOptionFunc func(i *Importer)
+
+func (f importerOptionFunc) apply(i *Importer) {
+	f(i)
+}
+
+// ImportContracts restricts the import to the tables of the given
+// contracts.
+func ImportContracts(contracts ...eos.AccountName) ImporterOption {
+	return importerOptionFunc(func(i *Importer) {
+		i.contracts = map[eos.AccountName]bool{}
+		for _, contract := range contracts {
+			i.contracts[contract] = true
+		}
+	})
+}
+
+func NewImporter(sink TableRowSink, opts ...ImporterOption) *Importer {
+	i := &Importer{
+		sink: sink,
+		abis: map[eos.AccountName]*eos.ABI{},
+	}
+
+	for _, opt := range opts {
+		opt.apply(i)
+	}
+
+	return i
+}
+
+// ABI returns the ABI of a contract seen so far, nil if the account
+// has none.
+func (i *Importer) ABI(account eos.AccountName) *eos.ABI {
+	return i.abis[account]
+}
+
+// ImportFile imports the snapshot file `filename`.
+func (i *Importer) ImportFile(filename string) error {
+	r, err := NewDefaultReader(filename)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return i.Import(r)
+}
+
+// Import reads all the remaining sections of `r`, which must have
+// the default section handlers, and flushes the sink.
+func (i *Importer) Import(r *Reader) error {
+	for {
+		err := r.NextSection()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch r.CurrentSection.Name {
+		case SectionNameAccountObject:
+			err = r.ProcessCurrentSection(i.processAccountObject)
+		case SectionNameContractTables:
+			err = i.processContractTables(r)
+		}
+		if err != nil {
+			return fmt.Errorf("import section %s: %w", r.CurrentSection.Name, err)
+		}
+	}
+
+	return i.sink.Flush()
+}
+
+func (i *Importer) processAccountObject(obj interface{}) error {
+	account, ok := obj.(AccountObject)
+	if !ok {
+		return fmt.Errorf("unexpected object type: %T", obj)
+	}
+
+	if len(account.RawABI) == 0 || !i.imports(account.Name) {
+		return nil
+	}
+
+	abi := &eos.ABI{}
+	if err := eos.UnmarshalBinary(account.RawABI, abi); err != nil {
+		zlog.Info("skipping invalid abi, rows will not be decoded", zap.String("account", string(account.Name)), zap.Error(err))
+		return nil
+	}
+
+	i.abis[account.Name] = abi
+	i.Stats.Contracts++
+	return nil
+}
+
+func (i *Importer) processContractTables(r *Reader) error {
+	var table *TableIDObject
+	return r.ProcessCurrentSection(func(obj interface{}) error {
+		switch o := obj.(type) {
+		case *TableIDObject:
+			table = nil
+			if i.imports(eos.AccountName(o.Code)) {
+				table = o
+				i.Stats.Tables++
+			}
+			return nil
+		case *KeyValueObject:
+			if table == nil {
+				return nil
+			}
+			return i.writeRow(table, o)
+		}
+
+		// secondary indexes only reference the rows of the primary one
+		return nil
+	})
+}
+
+func (i *Importer) writeRow(table *TableIDObject, obj *KeyValueObject) error {
+	primaryKey, err := parseName("primary key", obj.PrimKey)
+	if err != nil {
+		return fmt.Errorf("%s:%s:%s row: %w", table.Code, table.Scope, table.TableName, err)
+	}
+
+	row := &TableRow{
+		Code:       eos.AccountName(table.Code),
+		Scope:      eos.Name(table.Scope),
+		Table:      eos.TableName(table.TableName),
+		PrimaryKey: eos.Uint64(primaryKey),
+		Payer:      eos.AccountName(obj.Payer),
+		Hex:        obj.Value,
+	}
+
+	if abi := i.abis[row.Code]; abi != nil && abi.TableForName(row.Table) != nil {
+		data, err := abi.DecodeTableRow(row.Table, obj.Value)
+		if err != nil {
+			zlog.Debug("unable to decode row", zap.String("code", table.Code), zap.String("table", table.TableName), zap.String("primary_key", obj.PrimKey), zap.Error(err))
+		} else {
+			row.Data = data
+			i.Stats.DecodedRows++
+		}
+	}
+
+	i.Stats.Rows++
+	return i.sink.WriteTableRow(row)
+}
+
+func (i *Importer) imports(contract eos.AccountName) bool {
+	return i.contracts == nil || i.contracts[contract]
+}
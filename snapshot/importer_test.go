@@ -0,0 +1,175 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImportFile(t *testing.T) string {
+	testFile := testData("ultra-testnet-snapshot.bin")
+	if !fileExists(testFile) {
+		t.Skipf("test file %s not found", testFile)
+	}
+	return testFile
+}
+
+func TestImporter_JSONLSink(t *testing.T) {
+	testFile := testImportFile(t)
+
+	buf := &bytes.Buffer{}
+	importer := NewImporter(NewJSONLSink(buf), ImportContracts("eosio.token"))
+	require.NoError(t, importer.ImportFile(testFile))
+
+	assert.NotNil(t, importer.ABI("eosio.token"))
+	assert.Nil(t, importer.ABI("eosio"))
+	assert.Equal(t, uint64(1), importer.Stats.Contracts)
+	assert.NotZero(t, importer.Stats.Rows)
+	assert.Equal(t, importer.Stats.Rows, importer.Stats.DecodedRows)
+
+	var rows []*TableRow
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		row := &TableRow{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), row))
+		rows = append(rows, row)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, rows, int(importer.Stats.Rows))
+
+	for _, row := range rows {
+		assert.Equal(t, eos.AccountName("eosio.token"), row.Code)
+		assert.NotNil(t, row.Data)
+	}
+}
+
+func TestImporter_TableStore(t *testing.T) {
+	testFile := testImportFile(t)
+
+	store, err := OpenTableStore(filepath.Join(t.TempDir(), "tables.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	importer := NewImporter(store)
+	require.NoError(t, importer.ImportFile(testFile))
+	assert.NotZero(t, importer.Stats.DecodedRows)
+
+	ctx := context.Background()
+	out, err := store.GetTableRows(ctx, eos.GetTableRowsRequest{
+		Code:  "eosio.token",
+		Scope: "alice",
+		Table: "accounts",
+		JSON:  true,
+	})
+	require.NoError(t, err)
+	assert.False(t, out.More)
+	assert.JSONEq(t, `[{"balance":"10000.00000000 UOS"}]`, string(out.Rows))
+
+	out, err = store.GetTableRows(ctx, eos.GetTableRowsRequest{
+		Code:  "eosio.token",
+		Scope: "alice",
+		Table: "accounts",
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `["0010a5d4e800000008554f5300000000"]`, string(out.Rows))
+}
+
+func TestTableStore_GetTableRows(t *testing.T) {
+	store, err := OpenTableStore(filepath.Join(t.TempDir(), "tables.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	for _, primaryKey := range []string{"alice", "bob", "carol", "daniel"} {
+		require.NoError(t, store.WriteTableRow(&TableRow{
+			Code:       "eosio",
+			Scope:      "eosio",
+			Table:      "voters",
+			PrimaryKey: eos.Uint64(eos.MustStringToName(primaryKey)),
+			Payer:      eos.AccountName(primaryKey),
+			Data:       json.RawMessage(`{"owner":"` + primaryKey + `"}`),
+		}))
+	}
+	require.NoError(t, store.WriteTableRow(&TableRow{Code: "eosio", Scope: "eosio", Table: "voters2", Hex: []byte{0x01}}))
+	assert.Error(t, store.WriteTableRow(&TableRow{Code: "Alice!", Scope: "eosio", Table: "voters", Hex: []byte{0x01}}))
+	assert.Error(t, store.WriteTableRow(&TableRow{Code: "eosio", Scope: "eosio", Table: "eosio.tokenzzzz", Hex: []byte{0x01}}))
+	require.NoError(t, store.WriteTableRow(&TableRow{Code: "eosio", Scope: "eosio", Table: "global", Hex: []byte{0x02}}))
+	require.NoError(t, store.Flush())
+
+	tests := []struct {
+		name         string
+		params       eos.GetTableRowsRequest
+		expectedRows string
+		expectedMore bool
+		expectedNext string
+		expectedErr  error
+	}{
+		{
+			name:         "all",
+			params:       eos.GetTableRowsRequest{JSON: true},
+			expectedRows: `[{"owner":"alice"},{"owner":"bob"},{"owner":"carol"},{"owner":"daniel"}]`,
+		},
+		{
+			name:         "limit",
+			params:       eos.GetTableRowsRequest{JSON: true, Limit: 2},
+			expectedRows: `[{"owner":"alice"},{"owner":"bob"}]`,
+			expectedMore: true,
+			expectedNext: "4733081447982694400",
+		},
+		{
+			name:         "limit with name key type",
+			params:       eos.GetTableRowsRequest{JSON: true, Limit: 1, KeyType: "name"},
+			expectedRows: `[{"owner":"alice"}]`,
+			expectedMore: true,
+			expectedNext: "bob",
+		},
+		{
+			name:         "bounds",
+			params:       eos.GetTableRowsRequest{JSON: true, LowerBound: "bob", UpperBound: "carol", KeyType: "name"},
+			expectedRows: `[{"owner":"bob"},{"owner":"carol"}]`,
+		},
+		{
+			name:         "numeric bound",
+			params:       eos.GetTableRowsRequest{JSON: true, LowerBound: "4733081447982694400"},
+			expectedRows: `[{"owner":"carol"},{"owner":"daniel"}]`,
+		},
+		{
+			name:         "reverse",
+			params:       eos.GetTableRowsRequest{JSON: true, Reverse: true, Limit: 3, UpperBound: "carol"},
+			expectedRows: `[{"owner":"carol"},{"owner":"bob"},{"owner":"alice"}]`,
+		},
+		{
+			name:         "reverse between rows",
+			params:       eos.GetTableRowsRequest{JSON: true, Reverse: true, UpperBound: "bobby", LowerBound: "b"},
+			expectedRows: `[{"owner":"bob"}]`,
+		},
+		{
+			name:        "secondary index",
+			params:      eos.GetTableRowsRequest{Index: "2"},
+			expectedErr: ErrUnsupportedIndex,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.params.Code, test.params.Scope, test.params.Table = "eosio", "eosio", "voters"
+
+			out, err := store.GetTableRows(context.Background(), test.params)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expectedRows, string(out.Rows))
+			assert.Equal(t, test.expectedMore, out.More)
+			assert.Equal(t, test.expectedNext, out.NextKey)
+		})
+	}
+}
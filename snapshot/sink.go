@@ -0,0 +1,276 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+// JSONLSink writes imported rows as JSON, one row per line.
+type JSONLSink struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	buf := bufio.NewWriter(w)
+	return &JSONLSink{w: buf, enc: json.NewEncoder(buf)}
+}
+
+func (s *JSONLSink) WriteTableRow(row *TableRow) error {
+	return s.enc.Encode(row)
+}
+
+func (s *JSONLSink) Flush() error {
+	return s.w.Flush()
+}
+
+var tableRowsBucket = []byte("table_rows")
+
+// tableStoreBatchSize is the number of rows written per transaction
+// while importing.
+const tableStoreBatchSize = 10000
+
+var ErrUnsupportedIndex = errors.New("only the primary index is supported")
+
+// TableStore is an embedded key-value store of contract table rows,
+// backed by a bbolt database. Rows are written to it by an `Importer`
+// and can then be queried offline like the `get_table_rows` endpoint
+// of `nodeos`.
+type TableStore struct {
+	db      *bolt.DB
+	tx      *bolt.Tx
+	pending int
+}
+
+// OpenTableStore opens, or creates, the table store at `path`.
+func OpenTableStore(path string) (*TableStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(tableRowsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &TableStore{db: db}, nil
+}
+
+func (s *TableStore) WriteTableRow(row *TableRow) error {
+	code, err := parseName("code", string(row.Code))
+	if err != nil {
+		return err
+	}
+	scope, err := parseName("scope", string(row.Scope))
+	if err != nil {
+		return err
+	}
+	table, err := parseName("table", string(row.Table))
+	if err != nil {
+		return err
+	}
+
+	if s.tx == nil {
+		tx, err := s.db.Begin(true)
+		if err != nil {
+			return err
+		}
+		s.tx = tx
+	}
+
+	value, err := json.Marshal(row)
+	if err != nil {
+		return fmt.Errorf("marshal row: %w", err)
+	}
+
+	key := tableRowKey(code, scope, table, uint64(row.PrimaryKey))
+	if err := s.tx.Bucket(tableRowsBucket).Put(key, value); err != nil {
+		return err
+	}
+
+	s.pending++
+	if s.pending >= tableStoreBatchSize {
+		return s.Flush()
+	}
+
+	return nil
+}
+
+// Flush commits the rows written so far.
+func (s *TableStore) Flush() error {
+	if s.tx == nil {
+		return nil
+	}
+
+	tx := s.tx
+	s.tx, s.pending = nil, 0
+	return tx.Commit()
+}
+
+func (s *TableStore) Close() error {
+	if err := s.Flush(); err != nil {
+		s.db.Close()
+		return err
+	}
+
+	return s.db.Close()
+}
+
+// GetTableRows queries the rows of a table like `API.GetTableRows`.
+// Only the primary index is supported, with `i64` and `name` key
+// types. With `JSON`, rows that could not be decoded are returned as
+// hex strings.
+func (s *TableStore) GetTableRows(ctx context.Context, params eos.GetTableRowsRequest) (out *eos.GetTableRowsResp, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if params.Index != "" && params.Index != "1" && params.Index != "primary" {
+		return nil, fmt.Errorf("%w, got index %q", ErrUnsupportedIndex, params.Index)
+	}
+
+	code, err := eos.StringToName(params.Code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid code: %w", err)
+	}
+	scope, err := eos.ExtendedStringToName(params.Scope)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scope: %w", err)
+	}
+	table, err := eos.StringToName(params.Table)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table: %w", err)
+	}
+
+	lowerBound, err := parsePrimaryKeyBound(params.LowerBound, params.KeyType, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lower bound: %w", err)
+	}
+	upperBound, err := parsePrimaryKeyBound(params.UpperBound, params.KeyType, math.MaxUint64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upper bound: %w", err)
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = 10
+	}
+
+	prefix := tableRowKey(code, scope, table, 0)[:24]
+	lowerKey := tableRowKey(code, scope, table, lowerBound)
+	upperKey := tableRowKey(code, scope, table, upperBound)
+	inRange := func(k []byte) bool {
+		return k != nil && bytes.Compare(k, lowerKey) >= 0 && bytes.Compare(k, upperKey) <= 0
+	}
+
+	out = &eos.GetTableRowsResp{}
+	rows := []json.RawMessage{}
+	err = s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(tableRowsBucket).Cursor()
+
+		var k, v []byte
+		next := c.Next
+		if params.Reverse {
+			next = c.Prev
+			k, v = c.Seek(upperKey)
+			if k == nil {
+				k, v = c.Last()
+			} else if !bytes.Equal(k, upperKey) {
+				k, v = c.Prev()
+			}
+		} else {
+			k, v = c.Seek(lowerKey)
+		}
+
+		for ; inRange(k) && bytes.HasPrefix(k, prefix); k, v = next() {
+			if uint32(len(rows)) == limit {
+				out.More = true
+				out.NextKey = formatPrimaryKeyBound(binary.BigEndian.Uint64(k[24:32]), params.KeyType)
+				break
+			}
+
+			row, err := tableRowResult(v, params.JSON)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, row)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out.Rows, err = json.Marshal(rows)
+	return out, err
+}
+
+func tableRowResult(value []byte, decoded bool) (json.RawMessage, error) {
+	var row TableRow
+	if err := json.Unmarshal(value, &row); err != nil {
+		return nil, fmt.Errorf("unmarshal stored row: %w", err)
+	}
+
+	if decoded && row.Data != nil {
+		return row.Data, nil
+	}
+
+	return json.Marshal(row.Hex)
+}
+
+// tableRowKey orders rows by table, then primary key.
+func tableRowKey(code, scope, table, primaryKey uint64) []byte {
+	key := make([]byte, 32)
+	binary.BigEndian.PutUint64(key[0:8], code)
+	binary.BigEndian.PutUint64(key[8:16], scope)
+	binary.BigEndian.PutUint64(key[16:24], table)
+	binary.BigEndian.PutUint64(key[24:32], primaryKey)
+	return key
+}
+
+// parsePrimaryKeyBound converts a bound like `nodeos` does, trying a
+// number first then a name, unless the key type is `name`.
+func parsePrimaryKeyBound(bound string, keyType string, defaultValue uint64) (uint64, error) {
+	if bound == "" {
+		return defaultValue, nil
+	}
+
+	switch keyType {
+	case "name":
+		return eos.StringToName(bound)
+	case "", "i64":
+		if value, err := strconv.ParseUint(bound, 10, 64); err == nil {
+			return value, nil
+		}
+		return eos.StringToName(bound)
+	}
+
+	return 0, fmt.Errorf("unsupported key type %q for the primary index", keyType)
+}
+
+// formatPrimaryKeyBound formats the `next_key` of a response like
+// `nodeos` does, as a name for the `name` key type, else as a number.
+func formatPrimaryKeyBound(primaryKey uint64, keyType string) string {
+	if keyType == "name" {
+		return eos.NameToString(primaryKey)
+	}
+	return strconv.FormatUint(primaryKey, 10)
+}
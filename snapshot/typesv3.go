@@ -7,6 +7,7 @@ import (
 	"io"
 
 	"github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/ecc"
 )
 
 type TableIDObject struct {
@@ -223,7 +224,8 @@ type BlockState struct {
 	BlockID                   eos.Checksum256                   `json:"id"`
 	Header                    *eos.SignedBlockHeader            `json:"header"`
 	PendingSchedule           *ScheduleInfo                     `json:"pending_schedule"`
-	ActivatedProtocolFeatures *eos.ProtocolFeatureActivationSet `json:"activated_protocol_features"`
+	ActivatedProtocolFeatures *eos.ProtocolFeatureActivationSet `json:"activated_protocol_features" eos:"optional"`
+	AdditionalSignatures      []ecc.Signature                   `json:"additional_signatures" eos:"binary_extension"`
 }
 
 type ScheduleInfo struct {
@@ -710,9 +712,14 @@ type ElasticLimitParameters struct {
 	Max     eos.Uint64 // the maximum usage
 	Periods uint32     // the number of aggregation periods that contribute to the average usage
 
-	MaxMultiplier uint32     // the multiplier by which virtual space can oversell usage when uncongested
-	ContractRate  eos.Uint64 // the rate at which a congested resource contracts its limit
-	ExpandRate    eos.Uint64 // the rate at which an uncongested resource expands its limits
+	MaxMultiplier uint32 // the multiplier by which virtual space can oversell usage when uncongested
+	ContractRate  Ratio  // the rate at which a congested resource contracts its limit
+	ExpandRate    Ratio  // the rate at which an uncongested resource expands its limits
+}
+
+type Ratio struct {
+	Numerator   eos.Uint64
+	Denominator eos.Uint64
 }
 
 func readResourceLimitsConfigObject(section *Section, f sectionCallbackFunc) error {
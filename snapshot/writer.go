@@ -0,0 +1,340 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/eoscanada/eos-go"
+)
+
+// endMarker terminates the list of sections, in place of the size of
+// a section.
+var endMarker = []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+var ErrNoCurrentSection = errors.New("no section started")
+
+// Writer writes snapshots in the format read by `Reader`, the one
+// produced by `nodeos`.
+//
+// Sections are written one at a time with `BeginSection`, `WriteRow`
+// and `EndSection`. Rows are the objects passed by `Reader` to the
+// section callbacks, so a snapshot can be re-written, possibly
+// altered, by writing back every object read from it:
+//
+//	w.BeginSection(r.CurrentSection.Name)
+//	r.ProcessCurrentSection(w.WriteRow)
+//	w.EndSection()
+//
+// The rows of the `contract_tables` section are a `*TableIDObject`
+// followed by the rows of that table, in any order.
+type Writer struct {
+	filename string
+	fl       *os.File
+	buf      *bufio.Writer
+	offset   uint64
+
+	section *sectionWriter
+}
+
+type sectionWriter struct {
+	name     SectionName
+	offset   uint64
+	rowCount uint64
+
+	// contract_tables only, rows of the current table, grouped by
+	// index type in the order they are packed
+	table      *TableIDObject
+	tableRows  [6]bytes.Buffer
+	tableSizes [6]uint64
+}
+
+// NewWriter creates the snapshot file `filename` and writes its
+// header.
+func NewWriter(filename string) (w *Writer, err error) {
+	w = &Writer{
+		filename: filename,
+	}
+
+	w.fl, err = os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	w.buf = bufio.NewWriter(w.fl)
+
+	if err := w.writeHeader(); err != nil {
+		w.fl.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// writeHeader writes the top-most header, always of the version 1
+// format, the only one `nodeos` and `Reader` know about.
+func (w *Writer) writeHeader() error {
+	buf := make([]byte, 8)
+	copy(buf[:4], magicNumber)
+	binary.LittleEndian.PutUint32(buf[4:8], 1)
+
+	return w.write(buf)
+}
+
+// BeginSection starts a new section, ending the current one if any.
+func (w *Writer) BeginSection(name SectionName) error {
+	if w.section != nil {
+		if err := w.EndSection(); err != nil {
+			return err
+		}
+	}
+
+	w.section = &sectionWriter{name: name, offset: w.offset}
+
+	// size and row count are known and overwritten once the section ends
+	if err := w.write(make([]byte, 16)); err != nil {
+		return err
+	}
+
+	return w.write(append([]byte(name), 0x00))
+}
+
+// WriteRow appends an object to the current section. Objects are the
+// ones returned by `Reader`, either as values or pointers.
+func (w *Writer) WriteRow(obj interface{}) error {
+	if w.section == nil {
+		return ErrNoCurrentSection
+	}
+
+	if w.section.name == SectionNameContractTables {
+		return w.writeContractTablesRow(obj)
+	}
+
+	cnt, err := eos.MarshalBinary(obj)
+	if err != nil {
+		return fmt.Errorf("marshal %T row of section %s: %w", obj, w.section.name, err)
+	}
+
+	if err := w.write(cnt); err != nil {
+		return err
+	}
+
+	w.section.rowCount++
+	return nil
+}
+
+// WriteRawSection writes a whole section from its packed rows, like
+// the bytes following the header of a section read by `Reader`. It is
+// useful to copy sections without handlers.
+func (w *Writer) WriteRawSection(name SectionName, rowCount uint64, data []byte) error {
+	if err := w.BeginSection(name); err != nil {
+		return err
+	}
+
+	if err := w.write(data); err != nil {
+		return err
+	}
+
+	w.section.rowCount = rowCount
+	return w.EndSection()
+}
+
+// EndSection completes the current section, writing its size and row
+// count.
+func (w *Writer) EndSection() error {
+	s := w.section
+	if s == nil {
+		return ErrNoCurrentSection
+	}
+
+	if err := w.flushTable(); err != nil {
+		return err
+	}
+	w.section = nil
+
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+
+	// like the section size read by `Reader`, it excludes the size itself
+	head := make([]byte, 16)
+	binary.LittleEndian.PutUint64(head[0:8], w.offset-s.offset-8)
+	binary.LittleEndian.PutUint64(head[8:16], s.rowCount)
+
+	if _, err := w.fl.WriteAt(head, int64(s.offset)); err != nil {
+		return fmt.Errorf("write header of section %s: %w", s.name, err)
+	}
+
+	return nil
+}
+
+// Close ends the current section if any, writes the end marker of the
+// snapshot and closes the file.
+func (w *Writer) Close() error {
+	if w.section != nil {
+		if err := w.EndSection(); err != nil {
+			w.fl.Close()
+			return err
+		}
+	}
+
+	if err := w.write(endMarker); err != nil {
+		w.fl.Close()
+		return err
+	}
+
+	if err := w.buf.Flush(); err != nil {
+		w.fl.Close()
+		return err
+	}
+
+	return w.fl.Close()
+}
+
+func (w *Writer) write(cnt []byte) error {
+	written, err := w.buf.Write(cnt)
+	w.offset += uint64(written)
+	return err
+}
+
+func (w *Writer) writeContractTablesRow(obj interface{}) error {
+	s := w.section
+
+	var idxType int
+	var contractRow ContractRow
+	var value interface{}
+	switch row := obj.(type) {
+	case TableIDObject:
+		return w.beginTable(&row)
+	case *TableIDObject:
+		return w.beginTable(row)
+	case KeyValueObject:
+		idxType, contractRow, value = 0, row.ContractRow, []byte(row.Value)
+	case *KeyValueObject:
+		idxType, contractRow, value = 0, row.ContractRow, []byte(row.Value)
+	case Index64Object:
+		idxType, contractRow, value = 1, row.ContractRow, row.SecondaryKey
+	case *Index64Object:
+		idxType, contractRow, value = 1, row.ContractRow, row.SecondaryKey
+	case Index128Object:
+		idxType, contractRow, value = 2, row.ContractRow, row.SecondaryKey
+	case *Index128Object:
+		idxType, contractRow, value = 2, row.ContractRow, row.SecondaryKey
+	case Index256Object:
+		idxType, contractRow, value = 3, row.ContractRow, row.SecondaryKey
+	case *Index256Object:
+		idxType, contractRow, value = 3, row.ContractRow, row.SecondaryKey
+	case IndexDoubleObject:
+		idxType, contractRow, value = 4, row.ContractRow, row.SecondaryKey
+	case *IndexDoubleObject:
+		idxType, contractRow, value = 4, row.ContractRow, row.SecondaryKey
+	case IndexLongDoubleObject:
+		idxType, contractRow, value = 5, row.ContractRow, row.SecondaryKey
+	case *IndexLongDoubleObject:
+		idxType, contractRow, value = 5, row.ContractRow, row.SecondaryKey
+	default:
+		return fmt.Errorf("unexpected object type %T in section %s", obj, s.name)
+	}
+
+	if s.table == nil {
+		return fmt.Errorf("%T row written before any table in section %s", obj, s.name)
+	}
+
+	primaryKey, err := parseName("primary key", contractRow.PrimKey)
+	if err != nil {
+		return fmt.Errorf("%T row in section %s: %w", obj, s.name, err)
+	}
+	payer, err := parseName("payer", contractRow.Payer)
+	if err != nil {
+		return fmt.Errorf("%T row in section %s: %w", obj, s.name, err)
+	}
+
+	head := make([]byte, 16)
+	binary.LittleEndian.PutUint64(head[0:8], primaryKey)
+	binary.LittleEndian.PutUint64(head[8:16], payer)
+
+	cnt, err := eos.MarshalBinary(value)
+	if err != nil {
+		return fmt.Errorf("marshal %T row: %w", obj, err)
+	}
+
+	s.tableRows[idxType].Write(head)
+	s.tableRows[idxType].Write(cnt)
+	s.tableSizes[idxType]++
+
+	return nil
+}
+
+func (w *Writer) beginTable(table *TableIDObject) error {
+	if err := w.flushTable(); err != nil {
+		return err
+	}
+
+	w.section.table = table
+	return nil
+}
+
+// flushTable writes the pending table of the contract_tables section,
+// packed as a table_id_object followed by the number of rows and the
+// rows of each index type. Each of them counts as a row of the section.
+func (w *Writer) flushTable() error {
+	s := w.section
+	if s.table == nil {
+		return nil
+	}
+
+	head := make([]byte, 8+8+8+8+4)
+	for i, field := range []struct{ name, value string }{
+		{"code", s.table.Code},
+		{"scope", s.table.Scope},
+		{"table", s.table.TableName},
+		{"payer", s.table.Payer},
+	} {
+		name, err := parseName(field.name, field.value)
+		if err != nil {
+			return fmt.Errorf("table in section %s: %w", s.name, err)
+		}
+		binary.LittleEndian.PutUint64(head[i*8:i*8+8], name)
+	}
+	binary.LittleEndian.PutUint32(head[32:36], s.table.Count)
+
+	if err := w.write(head); err != nil {
+		return err
+	}
+	s.rowCount++
+
+	for idxType := range s.tableRows {
+		size := make([]byte, binary.MaxVarintLen64)
+		if err := w.write(size[:binary.PutUvarint(size, s.tableSizes[idxType])]); err != nil {
+			return err
+		}
+
+		if err := w.write(s.tableRows[idxType].Bytes()); err != nil {
+			return err
+		}
+
+		s.rowCount += 1 + s.tableSizes[idxType]
+		s.tableRows[idxType].Reset()
+		s.tableSizes[idxType] = 0
+	}
+
+	s.table = nil
+	return nil
+}
+
+// parseName parses the name in the `field` of a row, rejecting the
+// strings which are not valid names instead of packing another name.
+func parseName(field, name string) (uint64, error) {
+	value, err := eos.StringToName(name)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", field, err)
+	}
+
+	if eos.NameToString(value) != name {
+		return 0, fmt.Errorf("invalid %s: %q is not a valid name", field, name)
+	}
+
+	return value, nil
+}
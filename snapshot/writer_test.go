@@ -0,0 +1,158 @@
+package snapshot
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_RoundTrip(t *testing.T) {
+	testFile := testData("ultra-testnet-snapshot.bin")
+	if !fileExists(testFile) {
+		t.Skipf("test file %s not found", testFile)
+	}
+
+	outFile := filepath.Join(t.TempDir(), "snapshot.bin")
+
+	r, err := NewDefaultReader(testFile)
+	require.NoError(t, err)
+	defer r.Close()
+
+	w, err := NewWriter(outFile)
+	require.NoError(t, err)
+
+	for {
+		err := r.NextSection()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		section := r.CurrentSection
+
+		// The chain configuration of Ultra has an extra field, not
+		// modeled by `ChainConfig`, so the section is copied as is.
+		if section.Name == SectionNameGlobalPropertyObject {
+			cnt := make([]byte, section.BufferSize)
+			_, err := io.ReadFull(section.Buffer, cnt)
+			require.NoError(t, err)
+			require.NoError(t, w.WriteRawSection(section.Name, section.RowCount, cnt))
+			continue
+		}
+
+		require.NoError(t, w.BeginSection(section.Name))
+		require.NoError(t, r.ProcessCurrentSection(w.WriteRow), "section %s", section.Name)
+		require.NoError(t, w.EndSection())
+	}
+	require.NoError(t, w.Close())
+
+	expected, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	actual, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+
+	assert.True(t, bytes.Equal(expected, actual), "written snapshot differs from %s", testFile)
+}
+
+func TestWriter_ContractTables(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "snapshot.bin")
+	creationDate := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	w, err := NewWriter(outFile)
+	require.NoError(t, err)
+
+	require.NoError(t, w.BeginSection(SectionNameChainSnapshotHeader))
+	require.NoError(t, w.WriteRow(ChainSnapshotHeader{Version: 3}))
+
+	// starting a section ends the previous one
+	require.NoError(t, w.BeginSection(SectionNameAccountObject))
+	require.NoError(t, w.WriteRow(AccountObject{Name: "eosio", CreationDate: eos.BlockTimestamp{Time: creationDate}, RawABI: []byte{0x01, 0x02}}))
+	require.NoError(t, w.WriteRow(&AccountObject{Name: "eosio.token", CreationDate: eos.BlockTimestamp{Time: creationDate}}))
+	require.NoError(t, w.EndSection())
+
+	require.NoError(t, w.BeginSection(SectionNameContractTables))
+	assert.Error(t, w.WriteRow(&KeyValueObject{}))
+	rows := []interface{}{
+		&TableIDObject{Code: "eosio.token", Scope: "eosio", TableName: "accounts", Payer: "eosio", Count: 1},
+		&Index64Object{ContractRow: ContractRow{PrimKey: "eos", Payer: "eosio"}, SecondaryKey: eos.Name("eosio")},
+		&KeyValueObject{ContractRow: ContractRow{PrimKey: "eos", Payer: "eosio"}, Value: []byte{0xaa, 0xbb}},
+		&TableIDObject{Code: "eosio", Scope: "eosio", TableName: "global", Payer: "eosio", Count: 1},
+		&KeyValueObject{ContractRow: ContractRow{PrimKey: "global", Payer: "eosio"}, Value: []byte{0xcc}},
+		&IndexDoubleObject{ContractRow: ContractRow{PrimKey: "global", Payer: "eosio"}, SecondaryKey: eos.Float64(0.5)},
+	}
+	for _, row := range rows {
+		require.NoError(t, w.WriteRow(row))
+	}
+	require.NoError(t, w.Close())
+
+	r, err := NewDefaultReader(outFile)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var sections []SectionName
+	var rowCounts []uint64
+	var read []interface{}
+	for {
+		err := r.NextSection()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		sections = append(sections, r.CurrentSection.Name)
+		rowCounts = append(rowCounts, r.CurrentSection.RowCount)
+		require.NoError(t, r.ProcessCurrentSection(func(obj interface{}) error {
+			if account, ok := obj.(AccountObject); ok {
+				// block timestamps are decoded in the local time zone
+				assert.True(t, account.CreationDate.Equal(creationDate))
+				account.CreationDate = eos.BlockTimestamp{}
+				obj = account
+			}
+
+			read = append(read, obj)
+			return nil
+		}))
+	}
+
+	assert.Equal(t, []SectionName{SectionNameChainSnapshotHeader, SectionNameAccountObject, SectionNameContractTables}, sections)
+	assert.Equal(t, []uint64{1, 2, 2 + 2*6 + 4}, rowCounts)
+
+	expected := []interface{}{
+		ChainSnapshotHeader{Version: 3},
+		AccountObject{Name: "eosio", RawABI: []byte{0x01, 0x02}},
+		AccountObject{Name: "eosio.token", RawABI: []byte{}},
+		rows[0], rows[2], rows[1],
+		rows[3], rows[4], rows[5],
+	}
+	assert.Equal(t, expected, read)
+}
+
+func TestWriter_NoCurrentSection(t *testing.T) {
+	w, err := NewWriter(filepath.Join(t.TempDir(), "snapshot.bin"))
+	require.NoError(t, err)
+	defer w.Close()
+
+	assert.Equal(t, ErrNoCurrentSection, w.WriteRow(ChainSnapshotHeader{Version: 3}))
+	assert.Equal(t, ErrNoCurrentSection, w.EndSection())
+}
+
+func TestWriter_InvalidNames(t *testing.T) {
+	w, err := NewWriter(filepath.Join(t.TempDir(), "snapshot.bin"))
+	require.NoError(t, err)
+	defer w.Close()
+
+	require.NoError(t, w.BeginSection(SectionNameContractTables))
+	require.NoError(t, w.WriteRow(&TableIDObject{Code: "eosio.token", Scope: "eosio", TableName: "accounts", Payer: "eosio", Count: 1}))
+	assert.Error(t, w.WriteRow(&KeyValueObject{ContractRow: ContractRow{PrimKey: "Alice!", Payer: "eosio"}}))
+	assert.Error(t, w.WriteRow(&KeyValueObject{ContractRow: ContractRow{PrimKey: "eos", Payer: "eosio.tokenzzzz"}}))
+
+	require.NoError(t, w.WriteRow(&TableIDObject{Code: "Token", Scope: "eosio", TableName: "accounts", Payer: "eosio", Count: 0}))
+	assert.Error(t, w.EndSection(), "the table is written when the section ends")
+}
@@ -0,0 +1,167 @@
+package eos
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// TableIterator pages through the rows of a table, or the scopes of a
+// contract, fetching the next page when the rows of the current one
+// are consumed:
+//
+//	rows := api.TableRows(ctx, eos.GetTableRowsRequest{Code: "eosio.token", Scope: "eosio.token", Table: "stat", JSON: true})
+//	for rows.Next() {
+//		var stat CurrencyStats
+//		if err := rows.Unmarshal(&stat); err != nil {
+//			return err
+//		}
+//	}
+//	if err := rows.Err(); err != nil {
+//		return err
+//	}
+//
+// A `TableIterator` is not safe for concurrent use.
+type TableIterator struct {
+	ctx    context.Context
+	fetch  func(ctx context.Context) (rows json.RawMessage, more bool, err error)
+	binary bool
+
+	rows []json.RawMessage
+	row  json.RawMessage
+	done bool
+	err  error
+}
+
+// TableRows returns an iterator over all the rows of the table matching
+// `request`, from its lower bound to its upper bound, or the other way
+// around when `request.Reverse` is set. `request.Limit` is the number
+// of rows fetched per page.
+//
+// Pages are chained with the `next_key` returned by `nodeos`, which is
+// encoded for the `key_type` and `index_position` of the request, so
+// it is passed back as is as the next lower bound (upper bound when
+// iterating in reverse).
+func (api *API) TableRows(ctx context.Context, request GetTableRowsRequest) *TableIterator {
+	return &TableIterator{
+		ctx:    ctx,
+		binary: !request.JSON,
+		fetch: func(ctx context.Context) (json.RawMessage, bool, error) {
+			resp, err := api.GetTableRows(ctx, request)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if !resp.More {
+				return resp.Rows, false, nil
+			}
+
+			if resp.NextKey == "" {
+				return nil, false, fmt.Errorf("more rows available but no next_key returned, nodeos 2.0 or later is required to page through tables")
+			}
+
+			if request.Reverse {
+				request.UpperBound = resp.NextKey
+			} else {
+				request.LowerBound = resp.NextKey
+			}
+
+			return resp.Rows, true, nil
+		},
+	}
+}
+
+// TableScopes returns an iterator over all the scopes of the contract
+// matching `request`. Each row is a JSON object with the `code`,
+// `scope`, `table`, `payer` and `count` of a table.
+func (api *API) TableScopes(ctx context.Context, request GetTableByScopeRequest) *TableIterator {
+	return &TableIterator{
+		ctx: ctx,
+		fetch: func(ctx context.Context) (json.RawMessage, bool, error) {
+			resp, err := api.GetTableByScope(ctx, request)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if resp.More == "" {
+				return resp.Rows, false, nil
+			}
+
+			if request.Reverse {
+				request.UpperBound = resp.More
+			} else {
+				request.LowerBound = resp.More
+			}
+
+			return resp.Rows, true, nil
+		},
+	}
+}
+
+// Next advances to the next row, fetching the next page when needed.
+// It returns false when all the rows were read or an error occurred,
+// see `Err`.
+func (it *TableIterator) Next() bool {
+	for len(it.rows) == 0 {
+		if it.done || it.err != nil {
+			it.row = nil
+			return false
+		}
+
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			continue
+		}
+
+		data, more, err := it.fetch(it.ctx)
+		if err != nil {
+			it.err = err
+			continue
+		}
+		it.done = !more
+
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &it.rows); err != nil {
+				it.rows = nil
+				it.err = fmt.Errorf("decode rows: %w", err)
+			}
+		}
+	}
+
+	it.row = it.rows[0]
+	it.rows = it.rows[1:]
+	return true
+}
+
+// Row returns the current row as returned by `nodeos`, a JSON object,
+// or a JSON string with the hex-encoded row when the request's `JSON`
+// is false.
+func (it *TableIterator) Row() json.RawMessage {
+	return it.row
+}
+
+// Unmarshal decodes the current row into `v`, from JSON, or from
+// binary when the request's `JSON` is false.
+func (it *TableIterator) Unmarshal(v interface{}) error {
+	if !it.binary {
+		return json.Unmarshal(it.row, v)
+	}
+
+	var row string
+	if err := json.Unmarshal(it.row, &row); err != nil {
+		return fmt.Errorf("row is not hex encoded: %w", err)
+	}
+
+	data, err := hex.DecodeString(row)
+	if err != nil {
+		return fmt.Errorf("decode row hex: %w", err)
+	}
+
+	return UnmarshalBinary(data, v)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TableIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,185 @@
+package eos
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tableIteratorTestRow struct {
+	ID    Uint64 `json:"id"`
+	Owner Name   `json:"owner"`
+}
+
+// newTableIteratorTestServer serves `get_table_rows` for rows with ids
+// 1 to `count`, using the id as key, and `get_table_by_scope` for
+// scopes `a` to `e`.
+func newTableIteratorTestServer(t *testing.T, count int) (*API, *[]GetTableRowsRequest) {
+	var requests []GetTableRowsRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chain/get_table_rows":
+			var request GetTableRowsRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			requests = append(requests, request)
+
+			lower, upper := uint64(0), uint64(count)
+			if request.LowerBound != "" {
+				lower, _ = strconv.ParseUint(request.LowerBound, 10, 64)
+			}
+			if request.UpperBound != "" {
+				upper, _ = strconv.ParseUint(request.UpperBound, 10, 64)
+			}
+
+			var ids []uint64
+			for id := uint64(1); id <= uint64(count); id++ {
+				if id >= lower && id <= upper {
+					ids = append(ids, id)
+				}
+			}
+			if request.Reverse {
+				sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+			}
+
+			resp := map[string]interface{}{"rows": []interface{}{}, "more": false}
+			var rows []interface{}
+			for i, id := range ids {
+				if i == int(request.Limit) {
+					resp["more"] = true
+					resp["next_key"] = strconv.FormatUint(id, 10)
+					break
+				}
+
+				row := tableIteratorTestRow{ID: Uint64(id), Owner: Name(fmt.Sprintf("owner%d", id))}
+				if request.JSON {
+					rows = append(rows, row)
+				} else {
+					data, err := MarshalBinary(row)
+					require.NoError(t, err)
+					rows = append(rows, hex.EncodeToString(data))
+				}
+			}
+			if rows != nil {
+				resp["rows"] = rows
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(resp))
+
+		case "/v1/chain/get_table_by_scope":
+			var request GetTableByScopeRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+			scopes := []string{"a", "b", "c", "d", "e"}
+			var rows []interface{}
+			more := ""
+			for _, scope := range scopes {
+				if scope < request.LowerBound {
+					continue
+				}
+				if len(rows) == int(request.Limit) {
+					more = scope
+					break
+				}
+				rows = append(rows, map[string]interface{}{"code": "contract", "scope": scope, "table": "accounts", "payer": "contract", "count": 1})
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"rows": rows, "more": more}))
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	api := New(server.URL)
+	api.HttpClient = server.Client()
+	return api, &requests
+}
+
+func collectTableIteratorTestRows(t *testing.T, rows *TableIterator) (ids []uint64) {
+	for rows.Next() {
+		var row tableIteratorTestRow
+		require.NoError(t, rows.Unmarshal(&row))
+		assert.Equal(t, Name(fmt.Sprintf("owner%d", row.ID)), row.Owner)
+		ids = append(ids, uint64(row.ID))
+	}
+	require.NoError(t, rows.Err())
+	return
+}
+
+func TestTableRows(t *testing.T) {
+	api, requests := newTableIteratorTestServer(t, 7)
+
+	rows := api.TableRows(context.Background(), GetTableRowsRequest{Code: "contract", Scope: "contract", Table: "rows", Limit: 3, JSON: true})
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7}, collectTableIteratorTestRows(t, rows))
+
+	require.Len(t, *requests, 3)
+	assert.Equal(t, "", (*requests)[0].LowerBound)
+	assert.Equal(t, "4", (*requests)[1].LowerBound)
+	assert.Equal(t, "7", (*requests)[2].LowerBound)
+
+	assert.False(t, rows.Next(), "should stay done")
+}
+
+func TestTableRows_ReverseBinary(t *testing.T) {
+	api, requests := newTableIteratorTestServer(t, 5)
+
+	rows := api.TableRows(context.Background(), GetTableRowsRequest{Code: "contract", Scope: "contract", Table: "rows", Limit: 2, Reverse: true, UpperBound: "4"})
+	assert.Equal(t, []uint64{4, 3, 2, 1}, collectTableIteratorTestRows(t, rows))
+
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "2", (*requests)[1].UpperBound)
+}
+
+func TestTableRows_Empty(t *testing.T) {
+	api, _ := newTableIteratorTestServer(t, 0)
+
+	rows := api.TableRows(context.Background(), GetTableRowsRequest{Code: "contract", Scope: "contract", Table: "rows", JSON: true})
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.Err())
+}
+
+func TestTableRows_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rows": [{"id": 1}], "more": true}`))
+	}))
+	defer server.Close()
+
+	api := New(server.URL)
+	api.HttpClient = server.Client()
+
+	rows := api.TableRows(context.Background(), GetTableRowsRequest{JSON: true})
+	assert.False(t, rows.Next())
+	assert.EqualError(t, rows.Err(), "more rows available but no next_key returned, nodeos 2.0 or later is required to page through tables")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rows = api.TableRows(ctx, GetTableRowsRequest{JSON: true})
+	assert.False(t, rows.Next())
+	assert.ErrorIs(t, rows.Err(), context.Canceled)
+}
+
+func TestTableScopes(t *testing.T) {
+	api, _ := newTableIteratorTestServer(t, 0)
+
+	scopes := api.TableScopes(context.Background(), GetTableByScopeRequest{Code: "contract", Table: "accounts", Limit: 2})
+
+	var names []string
+	for scopes.Next() {
+		var row struct {
+			Scope string `json:"scope"`
+		}
+		require.NoError(t, scopes.Unmarshal(&row))
+		names = append(names, row.Scope)
+	}
+	require.NoError(t, scopes.Err())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+}
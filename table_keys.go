@@ -0,0 +1,146 @@
+package eos
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// TableKey is a bound of a `get_table_rows` index, along with the
+// `key_type` and `encode_type` `nodeos` parses it with. Set it on a
+// request with `GetTableRowsRequest.SetLowerBound` and `SetUpperBound`:
+//
+//	request := eos.GetTableRowsRequest{Code: "eosio", Scope: "eosio", Table: "voters", Index: "2", JSON: true}
+//	request.SetLowerBound(eos.NewFloat64TableKey(1e12))
+type TableKey struct {
+	Bound      string
+	KeyType    string
+	EncodeType string
+}
+
+// NewI64TableKey returns the bound of a `uint64_t` index, like the
+// primary index of a table.
+func NewI64TableKey(value uint64) TableKey {
+	return TableKey{Bound: strconv.FormatUint(value, 10), KeyType: "i64", EncodeType: "dec"}
+}
+
+// NewNameTableKey returns the bound of an index on a name, stored as
+// its `uint64_t` value.
+func NewNameTableKey(name Name) TableKey {
+	return TableKey{Bound: string(name), KeyType: "name", EncodeType: "dec"}
+}
+
+// NewI128TableKey returns the bound of a `uint128_t` index.
+func NewI128TableKey(value Uint128) TableKey {
+	return TableKey{Bound: value.DecimalString(), KeyType: "i128", EncodeType: "dec"}
+}
+
+// NewCompositeTableKey returns the bound of a `uint128_t` index packing
+// two `uint64_t`, the common `(uint128_t(high) << 64) | low` way
+// contracts index rows by two values, like two names.
+func NewCompositeTableKey(high, low uint64) TableKey {
+	return NewI128TableKey(Uint128{Lo: low, Hi: high})
+}
+
+// NewFloat64TableKey returns the bound of a `double` index.
+func NewFloat64TableKey(value float64) TableKey {
+	return TableKey{Bound: strconv.FormatFloat(value, 'f', -1, 64), KeyType: "float64", EncodeType: "dec"}
+}
+
+// NewSHA256TableKey returns the bound of a `checksum256` index.
+//
+// The checksum is given as is, `nodeos` converts it to the two 128
+// bits words contracts store it as (see `Checksum256ToIndexKey`).
+func NewSHA256TableKey(checksum Checksum256) (TableKey, error) {
+	if len(checksum) != 32 {
+		return TableKey{}, fmt.Errorf("sha256 key must be 32 bytes, got %d", len(checksum))
+	}
+	return TableKey{Bound: hex.EncodeToString(checksum), KeyType: "sha256", EncodeType: "hex"}, nil
+}
+
+// NewRipemd160TableKey returns the bound of a `checksum160` index.
+//
+// The checksum is given as is, `nodeos` converts it to the two 128
+// bits words contracts store it as (see `Checksum160ToIndexKey`).
+func NewRipemd160TableKey(checksum Checksum160) (TableKey, error) {
+	if len(checksum) != 20 {
+		return TableKey{}, fmt.Errorf("ripemd160 key must be 20 bytes, got %d", len(checksum))
+	}
+	return TableKey{Bound: hex.EncodeToString(checksum), KeyType: "ripemd160", EncodeType: "hex"}, nil
+}
+
+// SetLowerBound sets the lower bound of the request, along with the
+// key and encode types of the index it is on.
+func (r *GetTableRowsRequest) SetLowerBound(key TableKey) {
+	r.LowerBound = key.Bound
+	r.KeyType = key.KeyType
+	r.EncodeType = key.EncodeType
+}
+
+// SetUpperBound sets the upper bound of the request, along with the
+// key and encode types of the index it is on.
+func (r *GetTableRowsRequest) SetUpperBound(key TableKey) {
+	r.UpperBound = key.Bound
+	r.KeyType = key.KeyType
+	r.EncodeType = key.EncodeType
+}
+
+// Checksum256ToIndexKey returns the 256 bits secondary key a contract
+// stores for `checksum`, as found in state history `contract_index256`
+// rows. Contracts store checksums as two big endian 128 bits words,
+// which are little endian in memory, so each 16 bytes half of the
+// checksum is reversed.
+func Checksum256ToIndexKey(checksum Checksum256) (Checksum256, error) {
+	if len(checksum) != 32 {
+		return nil, fmt.Errorf("sha256 key must be 32 bytes, got %d", len(checksum))
+	}
+
+	key := make(Checksum256, 32)
+	copyReversed(key[:16], checksum[:16])
+	copyReversed(key[16:], checksum[16:])
+	return key, nil
+}
+
+// IndexKeyToChecksum256 is the reverse of `Checksum256ToIndexKey`.
+func IndexKeyToChecksum256(key Checksum256) (Checksum256, error) {
+	return Checksum256ToIndexKey(key)
+}
+
+// Checksum160ToIndexKey returns the 256 bits secondary key a contract
+// stores for `checksum`. Like for `checksum256`, the first 16 bytes are
+// stored as a big endian 128 bits word, the 4 remaining bytes are the
+// most significant bytes of the second word, the rest being zeros.
+func Checksum160ToIndexKey(checksum Checksum160) (Checksum256, error) {
+	if len(checksum) != 20 {
+		return nil, fmt.Errorf("ripemd160 key must be 20 bytes, got %d", len(checksum))
+	}
+
+	key := make(Checksum256, 32)
+	copyReversed(key[:16], checksum[:16])
+	copyReversed(key[28:], checksum[16:])
+	return key, nil
+}
+
+// IndexKeyToChecksum160 is the reverse of `Checksum160ToIndexKey`.
+func IndexKeyToChecksum160(key Checksum256) (Checksum160, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("index key must be 32 bytes, got %d", len(key))
+	}
+
+	for _, b := range key[16:28] {
+		if b != 0 {
+			return nil, fmt.Errorf("index key is not a ripemd160 key, bytes 16 to 28 are not zeros")
+		}
+	}
+
+	checksum := make(Checksum160, 20)
+	copyReversed(checksum[:16], key[:16])
+	copyReversed(checksum[16:], key[28:])
+	return checksum, nil
+}
+
+func copyReversed(dst, src []byte) {
+	for i := range src {
+		dst[len(src)-1-i] = src[i]
+	}
+}
@@ -0,0 +1,90 @@
+package eos
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableKeys(t *testing.T) {
+	sha256Key, err := NewSHA256TableKey(mustHex(t, "f58262c8005bb64b8f99ec6083faf050c502d099d9929ae37ffed2fe1bb954fb"))
+	require.NoError(t, err)
+	ripemd160Key, err := NewRipemd160TableKey(mustHex(t, "83a83a3876c64c33f66f33c54f1869edef5b5d4a"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		key      TableKey
+		expected TableKey
+	}{
+		{"i64", NewI64TableKey(math.MaxUint64), TableKey{"18446744073709551615", "i64", "dec"}},
+		{"name", NewNameTableKey("eosio.token"), TableKey{"eosio.token", "name", "dec"}},
+		{"i128", NewI128TableKey(Uint128{Lo: 1, Hi: 1}), TableKey{"18446744073709551617", "i128", "dec"}},
+		{"i128 max", NewI128TableKey(Uint128{Lo: math.MaxUint64, Hi: math.MaxUint64}), TableKey{"340282366920938463463374607431768211455", "i128", "dec"}},
+		{"composite", NewCompositeTableKey(0x5530EA0000000000, 0x345C850000000000), TableKey{"113238355974774775417645304427308908544", "i128", "dec"}},
+		{"float64", NewFloat64TableKey(-1.5), TableKey{"-1.5", "float64", "dec"}},
+		{"float64 large", NewFloat64TableKey(1e21), TableKey{"1000000000000000000000", "float64", "dec"}},
+		{"sha256", sha256Key, TableKey{"f58262c8005bb64b8f99ec6083faf050c502d099d9929ae37ffed2fe1bb954fb", "sha256", "hex"}},
+		{"ripemd160", ripemd160Key, TableKey{"83a83a3876c64c33f66f33c54f1869edef5b5d4a", "ripemd160", "hex"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.key)
+		})
+	}
+}
+
+func TestTableKeys_InvalidChecksums(t *testing.T) {
+	_, err := NewSHA256TableKey(make(Checksum256, 20))
+	assert.EqualError(t, err, "sha256 key must be 32 bytes, got 20")
+
+	_, err = NewRipemd160TableKey(make(Checksum160, 32))
+	assert.EqualError(t, err, "ripemd160 key must be 20 bytes, got 32")
+}
+
+func TestGetTableRowsRequest_SetBounds(t *testing.T) {
+	request := GetTableRowsRequest{Code: "eosio", Scope: "eosio", Table: "voters", Index: "2"}
+	request.SetLowerBound(NewFloat64TableKey(1))
+	request.SetUpperBound(NewFloat64TableKey(2.5))
+
+	assert.Equal(t, "1", request.LowerBound)
+	assert.Equal(t, "2.5", request.UpperBound)
+	assert.Equal(t, "float64", request.KeyType)
+	assert.Equal(t, "dec", request.EncodeType)
+}
+
+// The expected keys are the ones given as examples in `nodeos`
+// `chain_plugin.cpp` key type converters.
+func TestChecksumIndexKeys(t *testing.T) {
+	checksum256 := Checksum256(mustHex(t, "f58262c8005bb64b8f99ec6083faf050c502d099d9929ae37ffed2fe1bb954fb"))
+	key, err := Checksum256ToIndexKey(checksum256)
+	require.NoError(t, err)
+	assert.Equal(t, "50f0fa8360ec998f4bb65b00c86282f5fb54b91bfed2fe7fe39a92d999d002c5", hex.EncodeToString(key))
+
+	back, err := IndexKeyToChecksum256(key)
+	require.NoError(t, err)
+	assert.Equal(t, checksum256, back)
+
+	checksum160 := Checksum160(mustHex(t, "83a83a3876c64c33f66f33c54f1869edef5b5d4a"))
+	key, err = Checksum160ToIndexKey(checksum160)
+	require.NoError(t, err)
+	assert.Equal(t, "ed69184fc5336ff6334cc676383aa8830000000000000000000000004a5d5bef", hex.EncodeToString(key))
+
+	back160, err := IndexKeyToChecksum160(key)
+	require.NoError(t, err)
+	assert.Equal(t, checksum160, back160)
+
+	_, err = IndexKeyToChecksum160(checksum256)
+	assert.EqualError(t, err, "index key is not a ripemd160 key, bytes 16 to 28 are not zeros")
+}
+
+func mustHex(t *testing.T, in string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(in)
+	require.NoError(t, err)
+	return data
+}
@@ -1,6 +1,11 @@
 package token
 
-import eos "github.com/eoscanada/eos-go"
+import (
+	"fmt"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/ecc"
+)
 
 func NewTransfer(from, to eos.AccountName, quantity eos.Asset, memo string) *eos.Action {
 	return &eos.Action{
@@ -25,3 +30,40 @@ type Transfer struct {
 	Quantity eos.Asset       `json:"quantity"`
 	Memo     string          `json:"memo"`
 }
+
+// maxMemoSize is the maximum size of a memo accepted by `eosio.token`.
+const maxMemoSize = 256
+
+// NewEncryptedTransfer is like `NewTransfer` with the memo encrypted
+// from the sender's key to the recipient's key, see
+// `ecc.EncryptMessage`. It fails when the encrypted memo is larger
+// than the 256 bytes accepted by `eosio.token`.
+func NewEncryptedTransfer(from, to eos.AccountName, quantity eos.Asset, memo string, senderKey *ecc.PrivateKey, recipientKey ecc.PublicKey) (*eos.Action, error) {
+	message, err := ecc.EncryptMessage(senderKey, recipientKey, []byte(memo))
+	if err != nil {
+		return nil, fmt.Errorf("encrypt memo: %w", err)
+	}
+
+	encryptedMemo := message.Memo()
+	if len(encryptedMemo) > maxMemoSize {
+		return nil, fmt.Errorf("encrypted memo is %d bytes, more than the %d bytes accepted", len(encryptedMemo), maxMemoSize)
+	}
+
+	return NewTransfer(from, to, quantity, encryptedMemo), nil
+}
+
+// DecryptMemo decrypts the memo of the transfer with the private key of
+// either its sender or its recipient. It fails when the memo is not an
+// encrypted memo, see `ecc.IsEncryptedMemo`.
+func (t *Transfer) DecryptMemo(privKey *ecc.PrivateKey) (string, error) {
+	message, err := ecc.ParseEncryptedMemo(t.Memo)
+	if err != nil {
+		return "", err
+	}
+
+	memo, err := message.Decrypt(privKey)
+	if err != nil {
+		return "", err
+	}
+	return string(memo), nil
+}
@@ -0,0 +1,45 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/eoscanada/eos-go/ecc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedTransfer(t *testing.T) {
+	senderKey, err := ecc.NewRandomPrivateKey()
+	require.NoError(t, err)
+	recipientKey, err := ecc.NewRandomPrivateKey()
+	require.NoError(t, err)
+
+	action, err := NewEncryptedTransfer("alice", "bob", eos.NewEOSAsset(10000), "invoice 42", senderKey, recipientKey.PublicKey())
+	require.NoError(t, err)
+
+	data, err := eos.MarshalBinary(action)
+	require.NoError(t, err)
+
+	var decoded *eos.Action
+	require.NoError(t, eos.UnmarshalBinary(data, &decoded))
+
+	transfer, ok := decoded.Data.(*Transfer)
+	require.True(t, ok)
+	assert.True(t, ecc.IsEncryptedMemo(transfer.Memo))
+	assert.NotContains(t, transfer.Memo, "invoice 42")
+
+	for _, key := range []*ecc.PrivateKey{senderKey, recipientKey} {
+		memo, err := transfer.DecryptMemo(key)
+		require.NoError(t, err)
+		assert.Equal(t, "invoice 42", memo)
+	}
+
+	_, err = NewEncryptedTransfer("alice", "bob", eos.NewEOSAsset(10000), strings.Repeat("a", 200), senderKey, recipientKey.PublicKey())
+	assert.Error(t, err)
+
+	plain := Transfer{From: "alice", To: "bob", Quantity: eos.NewEOSAsset(10000), Memo: "invoice 42"}
+	_, err = plain.DecryptMemo(recipientKey)
+	assert.EqualError(t, err, `encrypted memo should start with "#"`)
+}
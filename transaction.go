@@ -376,16 +376,22 @@ func (p *PackedTransaction) ID() (Checksum256, error) {
 // Unpack decodes the bytestream of the transaction, and attempts to
 // decode the registered actions.
 func (p *PackedTransaction) Unpack() (signedTx *SignedTransaction, err error) {
-	return p.unpack(false)
+	return p.unpack(false, nil)
+}
+
+// UnpackWithRegistry is like `Unpack`, decoding the actions with the
+// types and ABIs of `registry`.
+func (p *PackedTransaction) UnpackWithRegistry(registry *Registry) (signedTx *SignedTransaction, err error) {
+	return p.unpack(false, registry)
 }
 
 // UnpackBare decodes the transcation payload, but doesn't decode the
 // nested action data structure.  See also `Unpack`.
 func (p *PackedTransaction) UnpackBare() (signedTx *SignedTransaction, err error) {
-	return p.unpack(true)
+	return p.unpack(true, nil)
 }
 
-func (p *PackedTransaction) unpack(bare bool) (signedTx *SignedTransaction, err error) {
+func (p *PackedTransaction) unpack(bare bool, registry *Registry) (signedTx *SignedTransaction, err error) {
 	var txReader io.Reader
 	txReader = bytes.NewBuffer(p.PackedTransaction)
 
@@ -413,6 +419,7 @@ func (p *PackedTransaction) unpack(bare bool) (signedTx *SignedTransaction, err
 	}
 	decoder := NewDecoder(data)
 	decoder.DecodeActions(!bare)
+	decoder.SetRegistry(registry)
 
 	var tx Transaction
 	err = decoder.Decode(&tx)